@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec/differential"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
+)
+
+func main() {
+	var allFlagNames []string
+	for _, knownFlag := range hostCore.AllFlags() {
+		allFlagNames = append(allFlagNames, string(knownFlag))
+	}
+	allFlags := strings.Join(allFlagNames, ",")
+
+	leftFlags := flag.String("left-flags", allFlags, "comma-separated enable epoch flags active in the left VM")
+	rightFlags := flag.String("right-flags", allFlags, "comma-separated enable epoch flags active in the right VM")
+	leftGas := flag.String("left-gas", "", "gas schedule of the left VM (dummy, v1, v2, v3); empty keeps the scenario's own")
+	rightGas := flag.String("right-gas", "", "gas schedule of the right VM (dummy, v1, v2, v3); empty keeps the scenario's own")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Println("One argument expected - the path to the scenario directory.")
+		os.Exit(1)
+	}
+
+	left, err := newConfiguration("left", *leftFlags, *leftGas)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	right, err := newConfiguration("right", *rightFlags, *rightGas)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	harness, err := differential.NewHarness(left, right)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	differences, err := harness.CompareDirectory(flag.Arg(0))
+	if err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	for _, difference := range differences {
+		fmt.Println(difference.String())
+	}
+	fmt.Printf("Done. Differences: %d.\n", len(differences))
+	if len(differences) > 0 {
+		os.Exit(1)
+	}
+}
+
+func newConfiguration(name string, flagsArg string, gasArg string) (*differential.Configuration, error) {
+	enabledFlags := make(map[core.EnableEpochFlag]bool)
+	for _, flagName := range strings.Split(flagsArg, ",") {
+		enableEpochFlag := core.EnableEpochFlag(strings.TrimSpace(flagName))
+		if len(enableEpochFlag) == 0 {
+			continue
+		}
+		if !hostCore.IsKnownFlag(enableEpochFlag) {
+			return nil, fmt.Errorf("unknown %s flag: %s", name, enableEpochFlag)
+		}
+		enabledFlags[enableEpochFlag] = true
+	}
+
+	config := &differential.Configuration{
+		Name: name,
+		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
+			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
+				return enabledFlags[flag]
+			},
+		},
+	}
+
+	if len(gasArg) > 0 {
+		gasSchedule, err := parseGasSchedule(gasArg)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s gas schedule: %w", name, err)
+		}
+		config.GasSchedule = gasSchedule
+		config.OverrideGasSchedule = true
+	}
+
+	return config, nil
+}
+
+func parseGasSchedule(gasArg string) (mj.GasSchedule, error) {
+	switch gasArg {
+	case "dummy":
+		return mj.GasScheduleDummy, nil
+	case "v1":
+		return mj.GasScheduleV1, nil
+	case "v2":
+		return mj.GasScheduleV2, nil
+	case "v3":
+		return mj.GasScheduleV3, nil
+	default:
+		return mj.GasScheduleDefault, fmt.Errorf("unknown gas schedule: %s", gasArg)
+	}
+}
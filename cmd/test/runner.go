@@ -1,11 +1,13 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/kalyan3104/k-chain-core-go/core"
 	am "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
 	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
 )
@@ -31,11 +33,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// flags
+	coveragePath := flag.String("coverage", "", "write a coverage report of the executed contract functions to this file (JSON, or lcov for .info/.lcov)")
+	callGraphPath := flag.String("call-graph", "", "write the tree of contract calls executed by each transaction to this file (JSON, or Graphviz for .dot/.gv)")
+	storageDiffs := flag.Bool("storage-diff", false, "print the storage changes made by each successful transaction")
+	abiPaths := flag.String("abi", "", "comma-separated contract ABI files, providing the types of abi: expressions and of expected logs and results")
+	enableEpochFlags := flag.String("flags", "", "comma-separated enable epoch flags enabled in all scenarios, on top of the default ones and of the ones required by each scenario")
+	generateExpectations := flag.Bool("generate-expectations", false, "overwrite the expected results and check states of .scen.json files with the actual execution results")
+	flag.Parse()
+
 	// argument
-	if len(os.Args) != 2 {
+	if flag.NArg() != 1 {
 		panic("One argument expected - the path to the json test.")
 	}
-	jsonFilePath, isDir, err := resolveArgument(exeDir, os.Args[1])
+	jsonFilePath, isDir, err := resolveArgument(exeDir, flag.Arg(0))
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -47,6 +58,42 @@ func main() {
 		panic("Could not instantiate VM VM")
 	}
 
+	if len(*enableEpochFlags) > 0 {
+		err = executor.EnableFlags(parseFlags(*enableEpochFlags))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	var coverage *am.CoverageTracker
+	if len(*coveragePath) > 0 {
+		coverage, err = executor.EnableCoverage()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+	var callGraph *am.CallGraphTracker
+	if len(*callGraphPath) > 0 {
+		callGraph = executor.EnableCallGraph()
+	}
+	if *storageDiffs {
+		executor.EnableStorageDiffs(os.Stdout)
+	}
+	if *generateExpectations {
+		executor.EnableExpectationGeneration(true)
+	}
+	if len(*abiPaths) > 0 {
+		for _, abiPath := range strings.Split(*abiPaths, ",") {
+			err = executor.LoadABI(abiPath)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+	}
+
 	// execute
 	switch {
 	case isDir:
@@ -54,6 +101,8 @@ func main() {
 			executor,
 			mc.NewDefaultFileResolver(),
 		)
+		runner.RewriteScenarios = *generateExpectations
+		runner.Parser.ExprInterpreter.ABI = executor.ContractABI()
 		err = runner.RunAllJSONScenariosInDirectory(
 			jsonFilePath,
 			"",
@@ -64,15 +113,32 @@ func main() {
 			executor,
 			mc.NewDefaultFileResolver(),
 		)
+		runner.RewriteScenarios = *generateExpectations
+		runner.Parser.ExprInterpreter.ABI = executor.ContractABI()
 		err = runner.RunSingleJSONScenario(jsonFilePath)
 	default:
 		runner := mc.NewTestRunner(
 			executor,
 			mc.NewDefaultFileResolver(),
 		)
+		runner.Parser.ExprInterpreter.ABI = executor.ContractABI()
 		err = runner.RunSingleJSONTest(jsonFilePath)
 	}
 
+	if coverage != nil {
+		coverageErr := coverage.WriteReport(*coveragePath)
+		if coverageErr != nil {
+			fmt.Printf("could not write coverage report: %s\n", coverageErr.Error())
+		}
+	}
+
+	if callGraph != nil {
+		callGraphErr := callGraph.WriteReport(*callGraphPath)
+		if callGraphErr != nil {
+			fmt.Printf("could not write call graph: %s\n", callGraphErr.Error())
+		}
+	}
+
 	// print result
 	if err == nil {
 		fmt.Println("SUCCESS")
@@ -81,3 +147,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func parseFlags(flagsArg string) []core.EnableEpochFlag {
+	var flags []core.EnableEpochFlag
+	for _, flagName := range strings.Split(flagsArg, ",") {
+		enableEpochFlag := core.EnableEpochFlag(strings.TrimSpace(flagName))
+		if len(enableEpochFlag) > 0 {
+			flags = append(flags, enableEpochFlag)
+		}
+	}
+	return flags
+}
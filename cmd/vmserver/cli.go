@@ -93,6 +93,13 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 		Destination: &args.CodeMetadata,
 	}
 
+	// For simulate
+	flagStateOverrides := cli.StringFlag{
+		Name:        "state-overrides",
+		Usage:       "path to a JSON file with the AccountOverrides and BlockOverride to simulate against",
+		Destination: &args.StateOverrides,
+	}
+
 	// For create-account
 	flagAccountAddress := cli.StringFlag{
 		Required:    true,
@@ -216,6 +223,47 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 				flagGasLimit,
 			},
 		},
+		{
+			Name:        "simulate",
+			Description: "simulate smart contract call against overridden state",
+			Action: func(context *cli.Context) error {
+				_, err := facade.SimulateSmartContract(args.toSimulateRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagContract,
+				flagImpersonated,
+				flagFunction,
+				flagArguments,
+				flagValue,
+				flagGasLimit,
+				flagGasPrice,
+				flagStateOverrides,
+			},
+		},
+		{
+			Name:        "estimate",
+			Description: "estimate the gas limit of a smart contract call",
+			Action: func(context *cli.Context) error {
+				_, err := facade.EstimateGas(args.toEstimateRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagContract,
+				flagImpersonated,
+				flagFunction,
+				flagArguments,
+				flagValue,
+				flagGasLimit,
+				flagGasPrice,
+			},
+		},
 		{
 			Name:        "create-account",
 			Description: "create account",
@@ -23,6 +23,7 @@ type cliArguments struct {
 	Value           string
 	GasLimit        uint64
 	GasPrice        uint64
+	StateOverrides  string
 	// For blockchain-related action
 	AccountAddress string
 	AccountBalance string
@@ -90,6 +91,21 @@ func (args *cliArguments) toQueryRequest() vmserver.QueryRequest {
 	return *request
 }
 
+func (args *cliArguments) toSimulateRequest() vmserver.SimulateRequest {
+	request := &vmserver.SimulateRequest{}
+	args.populateRunRequest(&request.RunRequest)
+
+	request.StateOverridesPath = args.StateOverrides
+	return *request
+}
+
+func (args *cliArguments) toEstimateRequest() vmserver.EstimateRequest {
+	request := &vmserver.EstimateRequest{}
+	args.populateRunRequest(&request.RunRequest)
+
+	return *request
+}
+
 func (args *cliArguments) toCreateAccountRequest() vmserver.CreateAccountRequest {
 	request := &vmserver.CreateAccountRequest{}
 	args.populateRequestBase(&request.RequestBase)
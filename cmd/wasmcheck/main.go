@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
+	worldmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
+	gasSchedules "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec/gasSchedules"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmcheck"
+)
+
+func main() {
+	var allFlagNames []string
+	for _, knownFlag := range hostCore.AllFlags() {
+		allFlagNames = append(allFlagNames, string(knownFlag))
+	}
+
+	jsonOutput := flag.Bool("json", false, "print the reports as JSON")
+	enabledFlags := flag.String("flags", strings.Join(allFlagNames, ","), "comma-separated enable epoch flags considered active; imports gated by other flags are rejected")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Println("At least one argument expected - the path to a contract .wasm file.")
+		os.Exit(1)
+	}
+
+	analyzer, err := newAnalyzer(*enabledFlags)
+	if err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	allValid := true
+	reports := make(map[string]*wasmcheck.Report)
+	for _, path := range flag.Args() {
+		report, err := analyzeFile(analyzer, path)
+		if err != nil {
+			fmt.Printf("%s: ERROR: %s\n", path, err.Error())
+			allValid = false
+			continue
+		}
+
+		allValid = allValid && report.IsValid()
+		reports[path] = report
+		if !*jsonOutput {
+			fmt.Printf("%s:\n", path)
+			report.WriteText(os.Stdout)
+		}
+	}
+
+	if *jsonOutput {
+		serialized, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Printf("ERROR: %s\n", err.Error())
+			os.Exit(1)
+		}
+		fmt.Println(string(serialized))
+	}
+
+	if !allValid {
+		os.Exit(1)
+	}
+}
+
+func newAnalyzer(flagsArg string) (*wasmcheck.Analyzer, error) {
+	enabledFlags := make(map[core.EnableEpochFlag]bool)
+	for _, flagName := range strings.Split(flagsArg, ",") {
+		enableEpochFlag := core.EnableEpochFlag(strings.TrimSpace(flagName))
+		if len(enableEpochFlag) == 0 {
+			continue
+		}
+		if !hostCore.IsKnownFlag(enableEpochFlag) {
+			return nil, fmt.Errorf("unknown flag: %s", enableEpochFlag)
+		}
+		enabledFlags[enableEpochFlag] = true
+	}
+
+	gasSchedule, err := gasSchedules.LoadGasScheduleConfig(gasSchedules.GetV3())
+	if err != nil {
+		return nil, err
+	}
+
+	world := worldmock.NewMockWorld()
+	err = world.InitBuiltinFunctions(gasSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return wasmcheck.NewAnalyzer(wasmcheck.ArgsAnalyzer{
+		GasSchedule:          gasSchedule,
+		BuiltInFuncContainer: world.BuiltinFuncs.Container,
+		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
+			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
+				return enabledFlags[flag]
+			},
+		},
+	})
+}
+
+func analyzeFile(analyzer *wasmcheck.Analyzer, path string) (*wasmcheck.Report, error) {
+	code, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return analyzer.Analyze(code)
+}
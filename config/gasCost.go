@@ -9,6 +9,8 @@ type GasCost struct {
 	BaseOpsAPICost    BaseOpsAPICost
 	CryptoAPICost     CryptoAPICost
 	WASMOpcodeCost    WASMOpcodeCost
+	StorageAccessCost StorageAccessCost
+	AsyncContextCost  AsyncContextCost
 }
 
 type BaseOperationCost struct {
@@ -21,6 +23,25 @@ type BaseOperationCost struct {
 	GetCode           uint64
 }
 
+// StorageAccessCost holds the costs of storage accesses which depend on whether the key has
+// already been accessed during the current transaction, i.e. whether it is "warm" or "cold".
+// The section is optional in the gas schedule; when missing, warm keys cost as much as cold ones.
+type StorageAccessCost struct {
+	ColdAccess          uint64
+	WarmAccess          uint64
+	WarmDataCopyPerByte uint64
+	WarmPersistPerByte  uint64
+}
+
+// AsyncContextCost holds the costs and the limits of the async calls grouped in async contexts.
+// The section is optional in the gas schedule; when missing, both hooks cost as much as an
+// async call step and an async context holds at most DefaultMaxAsyncCallsPerContext calls.
+type AsyncContextCost struct {
+	CreateAsyncCall         uint64
+	SetAsyncContextCallback uint64
+	MaxAsyncCallsPerContext uint64
+}
+
 type BaseOpsAPICost struct {
 	GetSCAddress         uint64
 	GetOwnerAddress      uint64
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -11,6 +13,9 @@ const GasValueForTests = 1
 
 var AsyncCallbackGasLockForTests = uint64(100_000)
 
+// DefaultMaxAsyncCallsPerContext bounds the async calls of an async context when the gas schedule does not
+const DefaultMaxAsyncCallsPerContext = 100
+
 // GasScheduleMap (alias) is the map for gas schedule
 type GasScheduleMap = map[string]map[string]uint64
 
@@ -81,6 +86,26 @@ func CreateGasConfig(gasMap GasScheduleMap) (*GasCost, error) {
 		return nil, err
 	}
 
+	// warm keys cost the same as cold ones, unless the gas schedule says otherwise
+	storageAccess := &StorageAccessCost{
+		WarmDataCopyPerByte: baseOps.DataCopyPerByte,
+		WarmPersistPerByte:  baseOps.PersistPerByte,
+	}
+	err = mapstructure.Decode(gasMap["StorageAccessCost"], storageAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	asyncContext := &AsyncContextCost{
+		CreateAsyncCall:         baseOpsAPI.AsyncCallStep,
+		SetAsyncContextCallback: baseOpsAPI.AsyncCallStep,
+		MaxAsyncCallsPerContext: DefaultMaxAsyncCallsPerContext,
+	}
+	err = mapstructure.Decode(gasMap["AsyncContextCost"], asyncContext)
+	if err != nil {
+		return nil, err
+	}
+
 	gasCost := &GasCost{
 		BaseOperationCost: *baseOps,
 		BigIntAPICost:     *bigIntOps,
@@ -88,11 +113,37 @@ func CreateGasConfig(gasMap GasScheduleMap) (*GasCost, error) {
 		BaseOpsAPICost:    *baseOpsAPI,
 		CryptoAPICost:     *cryptOps,
 		WASMOpcodeCost:    *opcodeCosts,
+		StorageAccessCost: *storageAccess,
+		AsyncContextCost:  *asyncContext,
 	}
 
 	return gasCost, nil
 }
 
+// GasScheduleVersion returns a digest of the gas schedule, which changes whenever any of its values does
+func GasScheduleVersion(gasMap GasScheduleMap) []byte {
+	sections := make([]string, 0, len(gasMap))
+	for section := range gasMap {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+
+	hasher := sha256.New()
+	for _, section := range sections {
+		names := make([]string, 0, len(gasMap[section]))
+		for name := range gasMap[section] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			_, _ = fmt.Fprintf(hasher, "%s.%s=%d;", section, name, gasMap[section][name])
+		}
+	}
+
+	return hasher.Sum(nil)
+}
+
 func checkForZeroUint64Fields(arg interface{}) error {
 	v := reflect.ValueOf(arg)
 	for i := 0; i < v.NumField(); i++ {
@@ -167,6 +218,29 @@ func FillGasMap_BaseOperationCosts(value uint64) map[string]uint64 {
 	return gasMap
 }
 
+// FillGasMap_StorageAccessCosts returns the optional StorageAccessCost section, with cold
+// accesses costing the given value and warm accesses costing the given warm value
+func FillGasMap_StorageAccessCosts(value, warmValue uint64) map[string]uint64 {
+	gasMap := make(map[string]uint64)
+	gasMap["ColdAccess"] = value
+	gasMap["WarmAccess"] = warmValue
+	gasMap["WarmDataCopyPerByte"] = warmValue
+	gasMap["WarmPersistPerByte"] = warmValue
+
+	return gasMap
+}
+
+// FillGasMap_AsyncContextCosts returns the optional AsyncContextCost section, with both hooks
+// costing the given value and at most maxAsyncCalls async calls per async context
+func FillGasMap_AsyncContextCosts(value, maxAsyncCalls uint64) map[string]uint64 {
+	gasMap := make(map[string]uint64)
+	gasMap["CreateAsyncCall"] = value
+	gasMap["SetAsyncContextCallback"] = value
+	gasMap["MaxAsyncCallsPerContext"] = maxAsyncCalls
+
+	return gasMap
+}
+
 func FillGasMap_BaseOpsAPICosts(value, asyncCallbackGasLock uint64) map[string]uint64 {
 	gasMap := make(map[string]uint64)
 	gasMap["GetSCAddress"] = value
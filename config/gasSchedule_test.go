@@ -74,3 +74,55 @@ func TestDecode_ZeroGasCostError(t *testing.T) {
 	err = checkForZeroUint64Fields(*wasmCosts)
 	assert.Error(t, err)
 }
+
+func TestGasScheduleVersion(t *testing.T) {
+	gasMap := MakeGasMapForTests()
+	version := GasScheduleVersion(gasMap)
+	assert.Equal(t, version, GasScheduleVersion(MakeGasMapForTests()))
+
+	gasMap["BaseOpsAPICost"]["StorePerByte"]++
+	assert.NotEqual(t, version, GasScheduleVersion(gasMap))
+}
+
+func TestCreateGasConfig_StorageAccessCostDefaults(t *testing.T) {
+	gasMap := MakeGasMapForTests()
+
+	gasCost, err := CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), gasCost.StorageAccessCost.ColdAccess)
+	assert.Equal(t, uint64(0), gasCost.StorageAccessCost.WarmAccess)
+	assert.Equal(t, gasCost.BaseOperationCost.DataCopyPerByte, gasCost.StorageAccessCost.WarmDataCopyPerByte)
+	assert.Equal(t, gasCost.BaseOperationCost.PersistPerByte, gasCost.StorageAccessCost.WarmPersistPerByte)
+
+	gasMap["StorageAccessCost"] = FillGasMap_StorageAccessCosts(100, 2)
+	gasCost, err = CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), gasCost.StorageAccessCost.ColdAccess)
+	assert.Equal(t, uint64(2), gasCost.StorageAccessCost.WarmAccess)
+	assert.Equal(t, uint64(2), gasCost.StorageAccessCost.WarmDataCopyPerByte)
+	assert.Equal(t, uint64(2), gasCost.StorageAccessCost.WarmPersistPerByte)
+
+	gasMap["StorageAccessCost"] = map[string]uint64{"ColdAccess": 50}
+	gasCost, err = CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(50), gasCost.StorageAccessCost.ColdAccess)
+	assert.Equal(t, gasCost.BaseOperationCost.PersistPerByte, gasCost.StorageAccessCost.WarmPersistPerByte)
+}
+
+func TestCreateGasConfig_AsyncContextCostDefaults(t *testing.T) {
+	gasMap := MakeGasMapForTests()
+	gasMap["BaseOpsAPICost"]["AsyncCallStep"] = 7
+
+	gasCost, err := CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), gasCost.AsyncContextCost.CreateAsyncCall)
+	assert.Equal(t, uint64(7), gasCost.AsyncContextCost.SetAsyncContextCallback)
+	assert.Equal(t, uint64(DefaultMaxAsyncCallsPerContext), gasCost.AsyncContextCost.MaxAsyncCallsPerContext)
+
+	gasMap["AsyncContextCost"] = FillGasMap_AsyncContextCosts(20, 3)
+	gasCost, err = CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(20), gasCost.AsyncContextCost.CreateAsyncCall)
+	assert.Equal(t, uint64(20), gasCost.AsyncContextCost.SetAsyncContextCallback)
+	assert.Equal(t, uint64(3), gasCost.AsyncContextCost.MaxAsyncCallsPerContext)
+}
@@ -0,0 +1,201 @@
+package fuzzengine
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+
+	roulette "github.com/kalyan3104/k-chain-vm-v1_3-go/fuzz/weightedroulette"
+	fr "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/fileresolver"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	mjwrite "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/write"
+)
+
+const defaultGasLimit = 100000000
+
+const defaultMaxShrinkRuns = 200
+
+// ErrNoActions signals that the engine was configured without any action with a positive weight.
+var ErrNoActions = errors.New("no fuzz actions with positive weight configured")
+
+// ErrNilFileResolver signals that the engine was configured without a file resolver.
+var ErrNilFileResolver = errors.New("nil file resolver")
+
+// Account declares an account present in the world before the first action.
+// All values are scenario expressions, e.g. "address:owner", "1,000,000" or "file:adder.wasm".
+type Account struct {
+	Address string
+	Nonce   string
+	Balance string
+	Code    string
+	Owner   string
+	Storage map[string]string
+}
+
+// ArgGenerator produces a scenario expression from the random source.
+type ArgGenerator func(r *rand.Rand) string
+
+// Action declares a contract endpoint that the engine calls at random.
+// The caller is picked uniformly from Callers, the action itself according to its Weight.
+type Action struct {
+	Name      string
+	Weight    int
+	Callers   []string
+	Contract  string
+	Function  string
+	Value     ArgGenerator
+	Arguments []ArgGenerator
+	GasLimit  uint64
+}
+
+// Invariant is a property checked after the initial state is set and after every action.
+// Check returns an error when the property does not hold.
+type Invariant struct {
+	Name  string
+	Check func(ctx *Context) error
+}
+
+// Config holds everything the engine needs to fuzz a set of contracts.
+type Config struct {
+	Accounts      []*Account
+	Actions       []*Action
+	Invariants    []*Invariant
+	FileResolver  fr.FileResolver
+	GasSchedule   mj.GasSchedule
+	NumSteps      int
+	Seed          int64
+	MaxShrinkRuns int
+}
+
+// Failure describes an invariant violation, together with the shrunk trace that reproduces it.
+type Failure struct {
+	Invariant string
+	Err       error
+	Trace     []*Call
+	Scenario  *mj.Scenario
+}
+
+// Error returns the invariant name and the error message.
+func (f *Failure) Error() string {
+	return fmt.Sprintf("invariant %s violated after %d calls: %s", f.Invariant, len(f.Trace), f.Err.Error())
+}
+
+// Result is the outcome of a fuzzing run.
+type Result struct {
+	Seed     int64
+	Trace    []*Call
+	Scenario *mj.Scenario
+	Failure  *Failure
+}
+
+// Engine runs randomised call sequences against contracts and checks invariants after each call.
+type Engine struct {
+	config       *Config
+	wasmCoverage bool
+}
+
+// NewEngine creates a new Engine instance.
+func NewEngine(config *Config) (*Engine, error) {
+	if config.FileResolver == nil {
+		return nil, ErrNilFileResolver
+	}
+
+	totalWeight := 0
+	for _, action := range config.Actions {
+		if action.Weight > 0 {
+			totalWeight += action.Weight
+		}
+	}
+	if totalWeight == 0 {
+		return nil, ErrNoActions
+	}
+
+	return &Engine{
+		config: config,
+	}, nil
+}
+
+// Run generates a random call sequence from the configured seed and executes it.
+// If an invariant fails, the failing sequence is shrunk to a minimal one before being reported.
+func (e *Engine) Run() (*Result, error) {
+	trace := e.GenerateTrace()
+
+	outcome, err := e.Replay(trace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Seed:     e.config.Seed,
+		Trace:    outcome.Trace,
+		Scenario: outcome.Scenario,
+	}
+	if outcome.Failure == nil {
+		return result, nil
+	}
+
+	shrunkOutcome, err := e.shrink(outcome)
+	if err != nil {
+		return nil, err
+	}
+	result.Failure = shrunkOutcome.Failure
+
+	return result, nil
+}
+
+// GenerateTrace produces the random call sequence for the configured seed, without executing it.
+func (e *Engine) GenerateTrace() []*Call {
+	r := rand.New(rand.NewSource(e.config.Seed))
+
+	outcomes := make([]roulette.Outcome, 0, len(e.config.Actions))
+	var trace []*Call
+	for _, action := range e.config.Actions {
+		if action.Weight <= 0 {
+			continue
+		}
+		currentAction := action
+		outcomes = append(outcomes, roulette.Outcome{
+			Weight: currentAction.Weight,
+			Event: func() {
+				trace = append(trace, newCall(currentAction, r))
+			},
+		})
+	}
+
+	for i := 0; i < e.config.NumSteps; i++ {
+		roulette.RandomChoice(r, outcomes...)
+	}
+
+	return trace
+}
+
+// WriteReproducer saves the scenario reproducing the failure to the given path.
+func (f *Failure) WriteReproducer(path string) error {
+	serialized := mjwrite.ScenarioToJSONString(f.Scenario)
+	return ioutil.WriteFile(path, []byte(serialized), 0644)
+}
+
+func newCall(action *Action, r *rand.Rand) *Call {
+	call := &Call{
+		Action:   action.Name,
+		To:       action.Contract,
+		Function: action.Function,
+		Value:    "0",
+		GasLimit: action.GasLimit,
+	}
+	if len(action.Callers) > 0 {
+		call.From = action.Callers[r.Intn(len(action.Callers))]
+	}
+	if action.Value != nil {
+		call.Value = action.Value(r)
+	}
+	for _, argGenerator := range action.Arguments {
+		call.Arguments = append(call.Arguments, argGenerator(r))
+	}
+	if call.GasLimit == 0 {
+		call.GasLimit = defaultGasLimit
+	}
+
+	return call
+}
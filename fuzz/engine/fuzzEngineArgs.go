@@ -0,0 +1,27 @@
+package fuzzengine
+
+import (
+	"math/big"
+	"math/rand"
+)
+
+// Constant generates the same expression every time.
+func Constant(expression string) ArgGenerator {
+	return func(_ *rand.Rand) string {
+		return expression
+	}
+}
+
+// OneOf picks one of the given expressions uniformly.
+func OneOf(expressions ...string) ArgGenerator {
+	return func(r *rand.Rand) string {
+		return expressions[r.Intn(len(expressions))]
+	}
+}
+
+// RandomBigUint generates a decimal number in the interval [0, max).
+func RandomBigUint(max *big.Int) ArgGenerator {
+	return func(r *rand.Rand) string {
+		return big.NewInt(0).Rand(r, max).String()
+	}
+}
@@ -0,0 +1,136 @@
+package fuzzengine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+)
+
+// numEdgeSlots is the size of the map of wasm edges, as in AFL
+const numEdgeSlots = 1 << 16
+
+// wasmEdgeCoverage collects the edges between the probes of the instrumented contracts,
+// i.e. the pairs of consecutive probes, hashed AFL-style.
+type wasmEdgeCoverage struct {
+	previousLocation uint32
+	edges            map[uint32]struct{}
+}
+
+func newWasmEdgeCoverage() *wasmEdgeCoverage {
+	return &wasmEdgeCoverage{
+		edges: make(map[uint32]struct{}),
+	}
+}
+
+// ModuleInstrumented does nothing, the probes are identified by their value only
+func (coverage *wasmEdgeCoverage) ModuleInstrumented(_ []byte, _ *wasmcov.InstrumentedModule) {
+}
+
+// ProbeVisited records the edge from the previous probe
+func (coverage *wasmEdgeCoverage) ProbeVisited(probeID int32) {
+	location := uint32(probeID)
+	edge := (location ^ coverage.previousLocation) % numEdgeSlots
+	coverage.edges[edge] = struct{}{}
+	coverage.previousLocation = location >> 1
+}
+
+// wasmCorpus adds the fuzz inputs reaching wasm edges that no earlier input reached to the seed
+// corpus of a Go fuzz target. The Go fuzzer only measures the coverage of the Go code, so the
+// inputs exploring new contract code are kept this way, and later fuzzing sessions start from them.
+type wasmCorpus struct {
+	directory string
+	mutSeen   sync.Mutex
+	seenEdges map[uint32]struct{}
+}
+
+func newWasmCorpus(directory string) *wasmCorpus {
+	return &wasmCorpus{
+		directory: directory,
+		seenEdges: make(map[uint32]struct{}),
+	}
+}
+
+// addIfNewEdges writes the input to the corpus directory if it reached any new edge
+func (corpus *wasmCorpus) addIfNewEdges(edges map[uint32]struct{}, actionIndex uint8, callValue []byte, rawArguments []byte) error {
+	if !corpus.markEdgesSeen(edges) {
+		return nil
+	}
+
+	err := os.MkdirAll(corpus.directory, 0755)
+	if err != nil {
+		return err
+	}
+
+	entry := marshalCorpusEntry(actionIndex, callValue, rawArguments)
+	name := fmt.Sprintf("%x", sha256.Sum256(entry))[:16]
+	return os.WriteFile(filepath.Join(corpus.directory, name), entry, 0644)
+}
+
+func (corpus *wasmCorpus) markEdgesSeen(edges map[uint32]struct{}) bool {
+	corpus.mutSeen.Lock()
+	defer corpus.mutSeen.Unlock()
+
+	hasNewEdges := false
+	for edge := range edges {
+		_, seen := corpus.seenEdges[edge]
+		if !seen {
+			corpus.seenEdges[edge] = struct{}{}
+			hasNewEdges = true
+		}
+	}
+	return hasNewEdges
+}
+
+// marshalCorpusEntry encodes the arguments of the fuzz target the way the go command writes corpus files
+func marshalCorpusEntry(actionIndex uint8, callValue []byte, rawArguments []byte) []byte {
+	entry := &bytes.Buffer{}
+	entry.WriteString("go test fuzz v1\n")
+	_, _ = fmt.Fprintf(entry, "byte(%q)\n", actionIndex)
+	_, _ = fmt.Fprintf(entry, "[]byte(%q)\n", callValue)
+	_, _ = fmt.Fprintf(entry, "[]byte(%q)\n", rawArguments)
+	return entry.Bytes()
+}
+
+// recordTraps wraps the exported functions of the instances, recording their traps into the context
+func recordTraps(ctx *Context) func(instance *wasmer.Instance) wasmer.InstanceHandler {
+	return func(instance *wasmer.Instance) wasmer.InstanceHandler {
+		exports := make(wasmer.ExportsMap, len(instance.Exports))
+		for name, function := range instance.Exports {
+			exports[name] = recordTrapsOf(ctx, function)
+		}
+
+		return &trapRecordingInstance{
+			InstanceHandler: instance,
+			exports:         exports,
+		}
+	}
+}
+
+func recordTrapsOf(ctx *Context, function wasmer.ExportedFunctionCallback) wasmer.ExportedFunctionCallback {
+	return func(args ...interface{}) (wasmer.Value, error) {
+		result, err := function(args...)
+		trap := &wasmer.TrapError{}
+		if errors.As(err, &trap) {
+			ctx.LastTraps = append(ctx.LastTraps, trap)
+		}
+		return result, err
+	}
+}
+
+// trapRecordingInstance is a Wasmer instance whose exported functions record their traps
+type trapRecordingInstance struct {
+	wasmer.InstanceHandler
+	exports wasmer.ExportsMap
+}
+
+// GetExports returns the exported functions, wrapped to record their traps
+func (instance *trapRecordingInstance) GetExports() wasmer.ExportsMap {
+	return instance.exports
+}
@@ -0,0 +1,133 @@
+package fuzzengine
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+)
+
+// UnexpectedTrapInvariant fails whenever the last call failed because of a wasm trap
+// whose kind is not among the expected ones.
+// Failures signalled by the contract or by the VM hooks interrupt the execution through
+// runtime breakpoints; they are not wasm traps and always pass.
+func UnexpectedTrapInvariant(expectedTraps ...wasmer.TrapKind) *Invariant {
+	return &Invariant{
+		Name: "no unexpected wasm traps",
+		Check: func(ctx *Context) error {
+			if ctx.LastOutput == nil || ctx.LastOutput.ReturnCode != vmi.ExecutionFailed {
+				return nil
+			}
+			for _, trap := range ctx.LastTraps {
+				if trap.Kind == wasmer.TrapRuntimeBreakpoint || isExpectedTrap(trap.Kind, expectedTraps) {
+					continue
+				}
+				return fmt.Errorf("unexpected wasm trap %s: %s", trap.Kind.String(), trap.Error())
+			}
+			return nil
+		},
+	}
+}
+
+func isExpectedTrap(kind wasmer.TrapKind, expectedTraps []wasmer.TrapKind) bool {
+	for _, expectedTrap := range expectedTraps {
+		if kind == expectedTrap {
+			return true
+		}
+	}
+	return false
+}
+
+// FuzzEndpoints runs a Go native fuzz target that calls the configured actions with arbitrary
+// call values and arguments, on a fresh world for each input. The declared callers, contract,
+// function and gas limit of the actions are kept; their generators are ignored.
+//
+// The raw arguments are split into length-prefixed chunks: each argument is preceded by
+// a single byte holding its length. A missing or truncated tail yields a shorter last argument.
+//
+// Besides the configured invariants, every input is checked for VM errors (panics recovered
+// by the host are reported this way) and for wasm traps not listed in expectedTraps.
+// While fuzzing, the contract code is instrumented with a probe on each branch, and the inputs
+// reaching edges between probes that no earlier input reached are added to the seed corpus
+// of the fuzz target, testdata/fuzz/<name>, so that the fuzzer is guided by the contract code
+// across sessions, besides the coverage of the host within each session.
+func (e *Engine) FuzzEndpoints(f *testing.F, expectedTraps ...wasmer.TrapKind) {
+	nativeConfig := *e.config
+	nativeConfig.Invariants = append([]*Invariant{UnexpectedTrapInvariant(expectedTraps...)}, e.config.Invariants...)
+	var corpus *wasmCorpus
+	if isFuzzing() {
+		corpus = newWasmCorpus(filepath.Join("testdata", "fuzz", f.Name()))
+	}
+	nativeEngine := &Engine{
+		config:       &nativeConfig,
+		wasmCoverage: corpus != nil,
+	}
+
+	for actionIndex := range e.config.Actions {
+		f.Add(uint8(actionIndex), []byte{}, []byte{})
+		f.Add(uint8(actionIndex), []byte{}, []byte{1, 0})
+	}
+
+	f.Fuzz(func(t *testing.T, actionIndex uint8, callValue []byte, rawArguments []byte) {
+		action := e.config.Actions[int(actionIndex)%len(e.config.Actions)]
+		call := &Call{
+			Action:    action.Name,
+			To:        action.Contract,
+			Function:  action.Function,
+			Value:     bytesToExpression(callValue),
+			Arguments: splitRawArguments(rawArguments),
+			GasLimit:  action.GasLimit,
+		}
+		if len(action.Callers) > 0 {
+			call.From = action.Callers[int(actionIndex)%len(action.Callers)]
+		}
+		if call.GasLimit == 0 {
+			call.GasLimit = defaultGasLimit
+		}
+
+		outcome, err := nativeEngine.Replay([]*Call{call})
+		if err != nil {
+			t.Fatalf("VM error calling %s: %s", call.Function, err.Error())
+		}
+		if corpus != nil {
+			err = corpus.addIfNewEdges(outcome.wasmEdges, actionIndex, callValue, rawArguments)
+			if err != nil {
+				t.Fatalf("cannot add to the corpus: %s", err.Error())
+			}
+		}
+		if outcome.Failure != nil {
+			t.Fatal(outcome.Failure.Error())
+		}
+	})
+}
+
+// isFuzzing returns true when the test binary runs with -test.fuzz, rather than only running the seed corpus
+func isFuzzing() bool {
+	fuzzFlag := flag.Lookup("test.fuzz")
+	return fuzzFlag != nil && len(fuzzFlag.Value.String()) > 0
+}
+
+func splitRawArguments(rawArguments []byte) []string {
+	arguments := make([]string, 0)
+	for len(rawArguments) > 0 {
+		length := int(rawArguments[0])
+		rawArguments = rawArguments[1:]
+		if length > len(rawArguments) {
+			length = len(rawArguments)
+		}
+		arguments = append(arguments, bytesToExpression(rawArguments[:length]))
+		rawArguments = rawArguments[length:]
+	}
+	return arguments
+}
+
+func bytesToExpression(value []byte) string {
+	if len(value) == 0 {
+		return "0"
+	}
+	return "0x" + hex.EncodeToString(value)
+}
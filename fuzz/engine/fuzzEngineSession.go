@@ -0,0 +1,285 @@
+package fuzzengine
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	worldhook "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
+	am "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	mjparse "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/parse"
+	oj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/orderedjson"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+)
+
+// Call is a single contract call of a fuzzing trace. All values are scenario expressions,
+// so that a trace can be replayed and written as a scenario.
+type Call struct {
+	Action    string
+	From      string
+	To        string
+	Function  string
+	Value     string
+	Arguments []string
+	GasLimit  uint64
+}
+
+// Outcome is the result of replaying a trace.
+// Trace only contains the calls executed before the first invariant violation, if any.
+// When replaying with wasm coverage, the edges reached by the contracts are kept as well.
+type Outcome struct {
+	Trace    []*Call
+	Scenario *mj.Scenario
+	Failure  *Failure
+
+	wasmEdges map[uint32]struct{}
+}
+
+// Context gives invariants access to the state of the replay in progress.
+type Context struct {
+	World      *worldhook.MockWorld
+	Trace      []*Call
+	LastOutput *vmi.VMOutput
+	LastTraps  []*wasmer.TrapError
+
+	executor *am.VMTestExecutor
+	parser   mjparse.Parser
+	numQuery int
+}
+
+// InterpretExpr evaluates a scenario expression.
+func (ctx *Context) InterpretExpr(expression string) ([]byte, error) {
+	return ctx.parser.ExprInterpreter.InterpretString(expression)
+}
+
+// Query runs a SC query and yields the returned data. Queries are not recorded in the trace,
+// and their traps are not added to LastTraps.
+func (ctx *Context) Query(contract string, function string, arguments ...string) ([][]byte, error) {
+	lastTraps := ctx.LastTraps
+	defer func() {
+		ctx.LastTraps = lastTraps
+	}()
+
+	ctx.numQuery++
+	txOJ := oj.NewMap()
+	txOJ.Put("to", stringToOJ(contract))
+	txOJ.Put("function", stringToOJ(function))
+	txOJ.Put("arguments", stringListToOJ(arguments))
+
+	stepOJ := oj.NewMap()
+	stepOJ.Put("step", stringToOJ(mj.StepNameScQuery))
+	stepOJ.Put("txId", stringToOJ(fmt.Sprintf("query-%d", ctx.numQuery)))
+	stepOJ.Put("tx", txOJ)
+
+	output, err := ctx.executeTxSnippet(oj.JSONString(stepOJ))
+	if err != nil {
+		return nil, err
+	}
+	if output.ReturnCode != vmi.Ok {
+		return nil, fmt.Errorf("query %s failed: %s (%s)", function, output.ReturnCode.String(), output.ReturnMessage)
+	}
+
+	return output.ReturnData, nil
+}
+
+func (ctx *Context) executeTxSnippet(stepSnippet string) (*vmi.VMOutput, error) {
+	step, err := ctx.parser.ParseScenarioStep(stepSnippet)
+	if err != nil {
+		return nil, err
+	}
+
+	txStep, isTx := step.(*mj.TxStep)
+	if !isTx {
+		return nil, errors.New("tx step expected")
+	}
+
+	return ctx.executor.ExecuteTxStep(txStep)
+}
+
+// Replay executes the given trace on a fresh world, checking the invariants after each call.
+// The returned scenario contains the initial state, the executed calls and their results,
+// and a final state check, all filled in from the actual execution.
+func (e *Engine) Replay(trace []*Call) (*Outcome, error) {
+	executor, err := am.NewVMTestExecutor()
+	if err != nil {
+		return nil, err
+	}
+	gasSchedule := e.config.GasSchedule
+	if gasSchedule == mj.GasScheduleDefault {
+		gasSchedule = mj.GasScheduleV3
+	}
+	err = executor.SetScenariosGasSchedule(gasSchedule)
+	if err != nil {
+		return nil, err
+	}
+	executor.EnableExpectationGeneration(false)
+
+	ctx := &Context{
+		World:    executor.World,
+		executor: executor,
+		parser:   mjparse.NewParser(e.config.FileResolver),
+	}
+	executor.SetInstanceWrapper(recordTraps(ctx))
+	var coverage *wasmEdgeCoverage
+	if e.wasmCoverage {
+		coverage = newWasmEdgeCoverage()
+		err = executor.AddProbeHandler(coverage)
+		if err != nil {
+			return nil, err
+		}
+		defer executor.RemoveProbeHandlers()
+	}
+	outcome := &Outcome{
+		Scenario: &mj.Scenario{
+			Name:        "fuzz generated",
+			Comment:     fmt.Sprintf("seed %d", e.config.Seed),
+			GasSchedule: gasSchedule,
+		},
+	}
+
+	setStateStep, err := ctx.parser.ParseScenarioStep(e.setStateSnippet())
+	if err != nil {
+		return nil, err
+	}
+	outcome.Scenario.Steps = append(outcome.Scenario.Steps, setStateStep)
+	err = executor.ExecuteStep(setStateStep)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome.Failure = e.checkInvariants(ctx)
+	for txIndex, call := range trace {
+		if outcome.Failure != nil {
+			break
+		}
+
+		step, err := ctx.parser.ParseScenarioStep(callSnippet(call, txIndex+1))
+		if err != nil {
+			return nil, err
+		}
+		txStep := step.(*mj.TxStep)
+		outcome.Scenario.Steps = append(outcome.Scenario.Steps, txStep)
+
+		if coverage != nil {
+			coverage.previousLocation = 0
+		}
+		ctx.LastTraps = nil
+		ctx.LastOutput, err = executor.ExecuteTxStep(txStep)
+		if err != nil {
+			return nil, err
+		}
+		ctx.Trace = append(ctx.Trace, call)
+
+		outcome.Failure = e.checkInvariants(ctx)
+	}
+	outcome.Trace = ctx.Trace
+	if coverage != nil {
+		outcome.wasmEdges = coverage.edges
+	}
+
+	checkStateStep := &mj.CheckStateStep{
+		CheckAccounts: &mj.CheckAccounts{},
+	}
+	err = executor.ExecuteCheckStateStep(checkStateStep)
+	if err != nil {
+		return nil, err
+	}
+	outcome.Scenario.Steps = append(outcome.Scenario.Steps, checkStateStep)
+
+	if outcome.Failure != nil {
+		outcome.Failure.Trace = outcome.Trace
+		outcome.Failure.Scenario = outcome.Scenario
+		checkStateStep.Comment = outcome.Failure.Error()
+	}
+
+	return outcome, nil
+}
+
+func (e *Engine) checkInvariants(ctx *Context) *Failure {
+	for _, invariant := range e.config.Invariants {
+		err := invariant.Check(ctx)
+		if err != nil {
+			return &Failure{
+				Invariant: invariant.Name,
+				Err:       err,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *Engine) setStateSnippet() string {
+	accountsOJ := oj.NewMap()
+	for _, account := range e.config.Accounts {
+		accountOJ := oj.NewMap()
+		accountOJ.Put("nonce", stringToOJ(valueOrZero(account.Nonce)))
+		accountOJ.Put("balance", stringToOJ(valueOrZero(account.Balance)))
+
+		storageKeys := make([]string, 0, len(account.Storage))
+		for key := range account.Storage {
+			storageKeys = append(storageKeys, key)
+		}
+		sort.Strings(storageKeys)
+		storageOJ := oj.NewMap()
+		for _, key := range storageKeys {
+			storageOJ.Put(key, stringToOJ(account.Storage[key]))
+		}
+		accountOJ.Put("storage", storageOJ)
+
+		if len(account.Code) > 0 {
+			accountOJ.Put("code", stringToOJ(account.Code))
+		}
+		if len(account.Owner) > 0 {
+			accountOJ.Put("owner", stringToOJ(account.Owner))
+		}
+		accountsOJ.Put(account.Address, accountOJ)
+	}
+
+	stepOJ := oj.NewMap()
+	stepOJ.Put("step", stringToOJ(mj.StepNameSetState))
+	stepOJ.Put("accounts", accountsOJ)
+
+	return oj.JSONString(stepOJ)
+}
+
+func callSnippet(call *Call, txIndex int) string {
+	txOJ := oj.NewMap()
+	txOJ.Put("from", stringToOJ(call.From))
+	txOJ.Put("to", stringToOJ(call.To))
+	txOJ.Put("value", stringToOJ(call.Value))
+	txOJ.Put("function", stringToOJ(call.Function))
+	txOJ.Put("arguments", stringListToOJ(call.Arguments))
+	txOJ.Put("gasLimit", stringToOJ(fmt.Sprintf("%d", call.GasLimit)))
+	txOJ.Put("gasPrice", stringToOJ("0"))
+
+	stepOJ := oj.NewMap()
+	stepOJ.Put("step", stringToOJ(mj.StepNameScCall))
+	stepOJ.Put("txId", stringToOJ(fmt.Sprintf("%d", txIndex)))
+	stepOJ.Put("comment", stringToOJ(call.Action))
+	stepOJ.Put("tx", txOJ)
+
+	return oj.JSONString(stepOJ)
+}
+
+func stringToOJ(str string) oj.OJsonObject {
+	return &oj.OJsonString{Value: str}
+}
+
+func stringListToOJ(strs []string) oj.OJsonObject {
+	list := make([]oj.OJsonObject, 0, len(strs))
+	for _, str := range strs {
+		list = append(list, stringToOJ(str))
+	}
+	listOJ := oj.OJsonList(list)
+	return &listOJ
+}
+
+func valueOrZero(value string) string {
+	if len(value) == 0 {
+		return "0"
+	}
+	return value
+}
@@ -0,0 +1,73 @@
+package fuzzengine
+
+// shrink replays ever smaller subsequences of the failing trace, keeping those that still
+// violate the same invariant, until no single call can be removed anymore.
+func (e *Engine) shrink(failing *Outcome) (*Outcome, error) {
+	maxRuns := e.config.MaxShrinkRuns
+	if maxRuns <= 0 {
+		maxRuns = defaultMaxShrinkRuns
+	}
+
+	smallest := failing
+	var replayErr error
+	stillFails := func(candidate []*Call) bool {
+		if replayErr != nil {
+			return false
+		}
+		outcome, err := e.Replay(candidate)
+		if err != nil {
+			replayErr = err
+			return false
+		}
+		if outcome.Failure == nil || outcome.Failure.Invariant != failing.Failure.Invariant {
+			return false
+		}
+		smallest = outcome
+		return true
+	}
+
+	shrinkTrace(failing.Trace, stillFails, maxRuns)
+	if replayErr != nil {
+		return nil, replayErr
+	}
+
+	return smallest, nil
+}
+
+// shrinkTrace removes chunks of calls, halving the chunk size whenever no chunk can be removed.
+// The predicate is called at most maxRuns times.
+func shrinkTrace(trace []*Call, stillFails func([]*Call) bool, maxRuns int) []*Call {
+	numRuns := 0
+	chunkSize := len(trace) / 2
+	if chunkSize == 0 {
+		chunkSize = 1
+	}
+
+	for chunkSize > 0 && numRuns < maxRuns {
+		removedAny := false
+		for start := 0; start < len(trace) && numRuns < maxRuns; {
+			end := start + chunkSize
+			if end > len(trace) {
+				end = len(trace)
+			}
+
+			candidate := make([]*Call, 0, len(trace)-(end-start))
+			candidate = append(candidate, trace[:start]...)
+			candidate = append(candidate, trace[end:]...)
+
+			numRuns++
+			if stillFails(candidate) {
+				trace = candidate
+				removedAny = true
+			} else {
+				start = end
+			}
+		}
+
+		if !removedAny {
+			chunkSize /= 2
+		}
+	}
+
+	return trace
+}
@@ -0,0 +1,230 @@
+package fuzzengine
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestRoot() string {
+	exePath, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	vmTestRoot := filepath.Join(exePath, "../../test")
+	return vmTestRoot
+}
+
+func newAdderConfig(invariants ...*Invariant) *Config {
+	fileResolver := mc.NewDefaultFileResolver().
+		ReplacePath(
+			"adder.wasm",
+			filepath.Join(getTestRoot(), "adder/output/adder.wasm"))
+
+	return &Config{
+		Accounts: []*Account{
+			{Address: "address:owner", Nonce: "1"},
+			{Address: "address:user", Nonce: "1"},
+			{
+				Address: "sc:adder",
+				Code:    "file:adder.wasm",
+				Owner:   "address:owner",
+				Storage: map[string]string{"str:sum": "5"},
+			},
+		},
+		Actions: []*Action{
+			{
+				Name:      "add",
+				Weight:    3,
+				Callers:   []string{"address:owner", "address:user"},
+				Contract:  "sc:adder",
+				Function:  "add",
+				Arguments: []ArgGenerator{RandomBigUint(big.NewInt(100))},
+				GasLimit:  5000000,
+			},
+			{
+				Name:      "add zero",
+				Weight:    1,
+				Callers:   []string{"address:user"},
+				Contract:  "sc:adder",
+				Function:  "add",
+				Arguments: []ArgGenerator{Constant("0")},
+				GasLimit:  5000000,
+			},
+		},
+		Invariants:   invariants,
+		FileResolver: fileResolver,
+		NumSteps:     30,
+		Seed:         42,
+	}
+}
+
+func adderSum(ctx *Context) (*big.Int, error) {
+	result, err := ctx.Query("sc:adder", "getSum")
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(0).SetBytes(result[0]), nil
+}
+
+func TestFuzzEngine_InvariantHolds(t *testing.T) {
+	sumMatchesTrace := &Invariant{
+		Name: "sum matches added values",
+		Check: func(ctx *Context) error {
+			expected := big.NewInt(5)
+			for _, call := range ctx.Trace {
+				added, _ := big.NewInt(0).SetString(call.Arguments[0], 10)
+				expected.Add(expected, added)
+			}
+			sum, err := adderSum(ctx)
+			if err != nil {
+				return err
+			}
+			if sum.Cmp(expected) != 0 {
+				return fmt.Errorf("want %d, have %d", expected, sum)
+			}
+			return nil
+		},
+	}
+
+	engine, err := NewEngine(newAdderConfig(sumMatchesTrace))
+	require.Nil(t, err)
+
+	result, err := engine.Run()
+	require.Nil(t, err)
+	require.Nil(t, result.Failure)
+	require.Len(t, result.Trace, 30)
+}
+
+func TestFuzzEngine_ShrinksFailingTrace(t *testing.T) {
+	sumBelowLimit := &Invariant{
+		Name: "sum below 200",
+		Check: func(ctx *Context) error {
+			sum, err := adderSum(ctx)
+			if err != nil {
+				return err
+			}
+			if sum.Cmp(big.NewInt(200)) >= 0 {
+				return errors.New("sum too large")
+			}
+			return nil
+		},
+	}
+
+	engine, err := NewEngine(newAdderConfig(sumBelowLimit))
+	require.Nil(t, err)
+
+	result, err := engine.Run()
+	require.Nil(t, err)
+	require.NotNil(t, result.Failure)
+	require.Equal(t, "sum below 200", result.Failure.Invariant)
+	require.Less(t, len(result.Failure.Trace), len(result.Trace))
+	for _, call := range result.Failure.Trace {
+		require.NotEqual(t, "0", call.Arguments[0])
+	}
+
+	replayed, err := engine.Replay(result.Failure.Trace)
+	require.Nil(t, err)
+	require.NotNil(t, replayed.Failure)
+
+	reproducerPath := filepath.Join(t.TempDir(), "fuzz_gen.scen.json")
+	err = result.Failure.WriteReproducer(reproducerPath)
+	require.Nil(t, err)
+	require.FileExists(t, reproducerPath)
+}
+
+func TestFuzzEngine_NoActions(t *testing.T) {
+	config := newAdderConfig()
+	config.Actions = nil
+
+	engine, err := NewEngine(config)
+	require.Nil(t, engine)
+	require.Equal(t, ErrNoActions, err)
+}
+
+func FuzzAdderEndpoints(f *testing.F) {
+	engine, err := NewEngine(newAdderConfig())
+	require.Nil(f, err)
+
+	engine.FuzzEndpoints(f)
+}
+
+func TestUnexpectedTrapInvariant(t *testing.T) {
+	invariant := UnexpectedTrapInvariant(wasmer.TrapIllegalArithmetic)
+	unreachable := &wasmer.TrapError{FunctionName: "add", Kind: wasmer.TrapUnreachable}
+	ctx := &Context{
+		LastOutput: &vmi.VMOutput{ReturnCode: vmi.ExecutionFailed},
+		LastTraps: []*wasmer.TrapError{
+			{FunctionName: "add", Kind: wasmer.TrapRuntimeBreakpoint},
+			{FunctionName: "add", Kind: wasmer.TrapIllegalArithmetic},
+		},
+	}
+	require.Nil(t, invariant.Check(ctx))
+
+	ctx.LastTraps = append(ctx.LastTraps, unreachable)
+	require.NotNil(t, invariant.Check(ctx))
+
+	ctx.LastOutput.ReturnCode = vmi.UserError
+	require.Nil(t, invariant.Check(ctx))
+}
+
+func TestSplitRawArguments(t *testing.T) {
+	require.Equal(t, []string{}, splitRawArguments(nil))
+	require.Equal(t, []string{"0x0102", "0", "0x03"}, splitRawArguments([]byte{2, 1, 2, 0, 5, 3}))
+}
+
+func TestShrinkTrace(t *testing.T) {
+	var trace []*Call
+	for i := 0; i < 20; i++ {
+		trace = append(trace, &Call{Action: fmt.Sprintf("call %d", i)})
+	}
+
+	numRuns := 0
+	containsCalls3And11 := func(candidate []*Call) bool {
+		numRuns++
+		found := 0
+		for _, call := range candidate {
+			if call == trace[3] || call == trace[11] {
+				found++
+			}
+		}
+		return found == 2
+	}
+
+	shrunk := shrinkTrace(trace, containsCalls3And11, 1000)
+	require.Equal(t, []*Call{trace[3], trace[11]}, shrunk)
+
+	numRuns = 0
+	shrinkTrace(trace, containsCalls3And11, 5)
+	require.Equal(t, 5, numRuns)
+}
+
+func TestWasmCorpus_AddsInputsReachingNewEdges(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "FuzzAdderEndpoints")
+	corpus := newWasmCorpus(directory)
+
+	edges := map[uint32]struct{}{1: {}, 2: {}}
+	require.Nil(t, corpus.addIfNewEdges(edges, 1, []byte{}, []byte{1, 'a'}))
+	require.Nil(t, corpus.addIfNewEdges(map[uint32]struct{}{2: {}}, 2, []byte{}, []byte{}))
+	require.Nil(t, corpus.addIfNewEdges(nil, 3, []byte{}, []byte{}))
+
+	entries, err := os.ReadDir(directory)
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	data, err := os.ReadFile(filepath.Join(directory, entries[0].Name()))
+	require.Nil(t, err)
+	require.Equal(t, "go test fuzz v1\nbyte('\\x01')\n[]byte(\"\")\n[]byte(\"\\x01a\")\n", string(data))
+
+	require.Nil(t, corpus.addIfNewEdges(map[uint32]struct{}{3: {}}, 4, []byte{5}, []byte{}))
+	entries, err = os.ReadDir(directory)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+}
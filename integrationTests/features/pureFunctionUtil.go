@@ -7,7 +7,6 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/kalyan3104/k-chain-core-go/core"
 	vmi "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/config"
@@ -51,9 +50,7 @@ func newPureFunctionExecutor() (*pureFunctionExecutor, error) {
 		BuiltInFuncContainer: builtInFunctions.NewBuiltInFunctionContainer(),
 		ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
 		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-			},
+			IsFlagEnabledCalled: hostCore.IsKnownFlag,
 		},
 	})
 	if err != nil {
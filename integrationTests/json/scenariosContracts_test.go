@@ -8,6 +8,10 @@ func TestRustAdder(t *testing.T) {
 	runAllTestsInFolder(t, "adder/scenarios")
 }
 
+func TestDCDTManagement(t *testing.T) {
+	runAllTestsInFolder(t, "dcdt-management/scenarios")
+}
+
 func TestRustErc20(t *testing.T) {
 	runAllTestsInFolder(t, "erc20-rust/scenarios")
 }
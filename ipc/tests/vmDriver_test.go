@@ -3,7 +3,6 @@ package tests
 import (
 	"testing"
 
-	"github.com/kalyan3104/k-chain-core-go/core"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
@@ -116,9 +115,7 @@ func newDriver(tb testing.TB, blockchain *contextmock.BlockchainHookStub) *nodep
 				ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
 				BuiltInFuncContainer: builtInFunctions.NewBuiltInFunctionContainer(),
 				EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-					IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-						return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-					},
+					IsFlagEnabledCalled: hostCore.IsKnownFlag,
 				},
 			},
 		},
@@ -5,7 +5,6 @@ import (
 	"sync"
 	"testing"
 
-	"github.com/kalyan3104/k-chain-core-go/core"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/config"
@@ -78,9 +77,7 @@ func doContractRequest(
 			ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
 			BuiltInFuncContainer: builtInFunctions.NewBuiltInFunctionContainer(),
 			EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-				IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-					return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-				},
+				IsFlagEnabledCalled: hostCore.IsKnownFlag,
 			},
 		}
 
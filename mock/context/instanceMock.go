@@ -13,6 +13,7 @@ import (
 type InstanceMock struct {
 	Code            []byte
 	Exports         wasmer.ExportsMap
+	Imports         map[string]struct{}
 	Points          uint64
 	Data            uintptr
 	GasLimit        uint64
@@ -28,6 +29,7 @@ func NewInstanceMock(code []byte) *InstanceMock {
 	return &InstanceMock{
 		Code:            code,
 		Exports:         make(wasmer.ExportsMap),
+		Imports:         make(map[string]struct{}),
 		Points:          0,
 		Data:            0,
 		GasLimit:        0,
@@ -41,6 +43,11 @@ func (instance *InstanceMock) AddMockMethod(name string, method func() *Instance
 	instance.AddMockMethodWithError(name, method, nil)
 }
 
+// AddMockImport declares that the instance imports the VM hook with the specified name.
+func (instance *InstanceMock) AddMockImport(name string) {
+	instance.Imports[name] = struct{}{}
+}
+
 // AddMockMethodWithError adds the provided function as a mocked method to the instance under the specified name and returns an error
 func (instance *InstanceMock) AddMockMethodWithError(name string, method func() *InstanceMock, err error) {
 	wrappedMethod := func(...interface{}) (wasmer.Value, error) {
@@ -138,9 +145,14 @@ func (instance *InstanceMock) GetMemory() wasmer.MemoryHandler {
 	return instance.Memory
 }
 
+// Reset mocked method
+func (instance *InstanceMock) Reset() bool {
+	return true
+}
+
 // IsFunctionImported mocked method
 func (instance *InstanceMock) IsFunctionImported(name string) bool {
-	_, ok := instance.Exports[name]
+	_, ok := instance.Imports[name]
 	return ok
 }
 
@@ -133,6 +133,11 @@ func (m *MeteringContextMock) ComputeGasLockedForAsync() uint64 {
 	return m.GasComputedToLock
 }
 
+// ComputeGasLockedForAsyncWithCodeSize mocked method
+func (m *MeteringContextMock) ComputeGasLockedForAsyncWithCodeSize(_ uint64) uint64 {
+	return m.GasComputedToLock
+}
+
 // DeductGasIfAsyncStep mocked method
 func (m *MeteringContextMock) DeductGasIfAsyncStep() error {
 	return m.Err
@@ -175,6 +175,11 @@ func (o *OutputContextMock) TransferDCDT(_ []byte, _ []byte, _ []byte, _ uint64,
 	return 0, nil
 }
 
+// TransferMultiDCDT mocked method
+func (o *OutputContextMock) TransferMultiDCDT(_ []byte, _ []byte, _ []*vmcommon.DCDTTransfer, _ *vmcommon.ContractCallInput) (uint64, error) {
+	return 0, nil
+}
+
 // AddTxValueToAccount mocked method
 func (o *OutputContextMock) AddTxValueToAccount(_ []byte, _ *big.Int) {
 }
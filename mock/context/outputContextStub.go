@@ -26,6 +26,7 @@ type OutputContextStub struct {
 	WriteLogCalled                    func(address []byte, topics [][]byte, data []byte)
 	TransferCalled                    func(destination []byte, sender []byte, gasLimit uint64, gasLocked uint64, value *big.Int, input []byte) error
 	TransferDCDTCalled                func(destination []byte, sender []byte, tokenIdentifier []byte, nonce uint64, value *big.Int, input *vmcommon.ContractCallInput) (uint64, error)
+	TransferMultiDCDTCalled           func(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, input *vmcommon.ContractCallInput) (uint64, error)
 	SelfDestructCalled                func(address []byte, beneficiary []byte)
 	GetRefundCalled                   func() uint64
 	SetRefundCalled                   func(refund uint64)
@@ -164,6 +165,14 @@ func (o *OutputContextStub) TransferDCDT(destination []byte, sender []byte, toke
 	return 0, nil
 }
 
+// TransferMultiDCDT mocked method
+func (o *OutputContextStub) TransferMultiDCDT(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callInput *vmcommon.ContractCallInput) (uint64, error) {
+	if o.TransferMultiDCDTCalled != nil {
+		return o.TransferMultiDCDTCalled(destination, sender, transfers, callInput)
+	}
+	return 0, nil
+}
+
 // SelfDestruct mocked method
 func (o *OutputContextStub) SelfDestruct(address []byte, beneficiary []byte) {
 	if o.SelfDestructCalled != nil {
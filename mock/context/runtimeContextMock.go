@@ -11,6 +11,7 @@ var _ vmhost.RuntimeContext = (*RuntimeContextMock)(nil)
 // RuntimeContextMock is used in tests to check the RuntimeContextMock interface method calls
 type RuntimeContextMock struct {
 	Err                    error
+	Errors                 vmhost.WrappableError
 	VMInput                *vmcommon.VMInput
 	SCAddress              []byte
 	SCCode                 []byte
@@ -19,6 +20,7 @@ type RuntimeContextMock struct {
 	VMType                 []byte
 	IsContractOnStack      bool
 	ReadOnlyFlag           bool
+	QueryModeFlag          bool
 	VerifyCode             bool
 	CurrentBreakpointValue vmhost.BreakpointValue
 	PointsUsed             uint64
@@ -96,6 +98,11 @@ func (r *RuntimeContextMock) IsWarmInstance() bool {
 func (r *RuntimeContextMock) ResetWarmInstance() {
 }
 
+// GetWarmInstancePoolMetrics mocked method
+func (r *RuntimeContextMock) GetWarmInstancePoolMetrics() vmhost.WarmInstancePoolMetrics {
+	return vmhost.WarmInstancePoolMetrics{}
+}
+
 // RunningInstancesCount mocked method
 func (r *RuntimeContextMock) RunningInstancesCount() uint64 {
 	return r.RunningInstances
@@ -105,6 +112,10 @@ func (r *RuntimeContextMock) RunningInstancesCount() uint64 {
 func (r *RuntimeContextMock) SetMaxInstanceCount(uint64) {
 }
 
+// SetCompiledCodeCache mocked method
+func (r *RuntimeContextMock) SetCompiledCodeCache(_ vmhost.CompiledCodeCache, _ []byte) {
+}
+
 // ClearInstanceStack mocked method
 func (r *RuntimeContextMock) ClearInstanceStack() {
 }
@@ -226,6 +237,16 @@ func (r *RuntimeContextMock) SetReadOnly(readOnly bool) {
 	r.ReadOnlyFlag = readOnly
 }
 
+// QueryMode mocked method
+func (r *RuntimeContextMock) QueryMode() bool {
+	return r.QueryModeFlag
+}
+
+// SetQueryMode mocked method
+func (r *RuntimeContextMock) SetQueryMode(queryMode bool) {
+	r.QueryModeFlag = queryMode
+}
+
 // GetInstance mocked method()
 func (r *RuntimeContextMock) GetInstance() wasmer.InstanceHandler {
 	return nil
@@ -297,7 +318,8 @@ func (r *RuntimeContextMock) BigIntAPIErrorShouldFailExecution() bool {
 }
 
 // FailExecution mocked method
-func (r *RuntimeContextMock) FailExecution(_ error) {
+func (r *RuntimeContextMock) FailExecution(err error) {
+	r.AddError(err)
 }
 
 // GetAsyncCallInfo mocked method
@@ -336,9 +358,24 @@ func (r *RuntimeContextMock) IsFunctionImported(_ string) bool {
 
 // AddError mocked method
 func (r *RuntimeContextMock) AddError(err error, otherInfo ...string) {
+	if err == nil {
+		return
+	}
+	if r.Errors == nil {
+		r.Errors = vmhost.WrapError(err, otherInfo...)
+		return
+	}
+	r.Errors = r.Errors.WrapWithError(err, otherInfo...)
 }
 
 // GetAllErrors mocked method
 func (r *RuntimeContextMock) GetAllErrors() error {
-	return nil
+	if r.Errors == nil {
+		return nil
+	}
+	return r.Errors
+}
+
+// SetFunctionCallTracer mocked method
+func (r *RuntimeContextMock) SetFunctionCallTracer(_ vmhost.FunctionCallTracer) {
 }
@@ -72,16 +72,24 @@ type RuntimeContextWrapper struct {
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	ResetWarmInstanceFunc func()
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	GetWarmInstancePoolMetricsFunc func() vmhost.WarmInstancePoolMetrics
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	ReadOnlyFunc func() bool
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	SetReadOnlyFunc func(readOnly bool)
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	QueryModeFunc func() bool
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	SetQueryModeFunc func(queryMode bool)
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	StartWasmerInstanceFunc func(contract []byte, gasLimit uint64, newCode bool) error
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	CleanWasmerInstanceFunc func()
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	SetMaxInstanceCountFunc func(maxInstances uint64)
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	SetCompiledCodeCacheFunc func(cache vmhost.CompiledCodeCache, gasScheduleVersion []byte)
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	VerifyContractCodeFunc func() error
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	GetInstanceFunc func() wasmer.InstanceHandler
@@ -117,6 +125,8 @@ type RuntimeContextWrapper struct {
 	AddErrorFunc func(err error, otherInfo ...string)
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	GetAllErrorsFunc func() error
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	SetFunctionCallTracerFunc func(tracer vmhost.FunctionCallTracer)
 
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	InitStateFunc func()
@@ -255,6 +265,10 @@ func NewRuntimeContextWrapper(inputRuntimeContext *vmhost.RuntimeContext) *Runti
 		runtimeWrapper.runtimeContext.ResetWarmInstance()
 	}
 
+	runtimeWrapper.GetWarmInstancePoolMetricsFunc = func() vmhost.WarmInstancePoolMetrics {
+		return runtimeWrapper.runtimeContext.GetWarmInstancePoolMetrics()
+	}
+
 	runtimeWrapper.ReadOnlyFunc = func() bool {
 		return runtimeWrapper.runtimeContext.ReadOnly()
 	}
@@ -263,6 +277,18 @@ func NewRuntimeContextWrapper(inputRuntimeContext *vmhost.RuntimeContext) *Runti
 		runtimeWrapper.runtimeContext.SetReadOnly(readOnly)
 	}
 
+	runtimeWrapper.QueryModeFunc = func() bool {
+		return runtimeWrapper.runtimeContext.QueryMode()
+	}
+
+	runtimeWrapper.SetQueryModeFunc = func(queryMode bool) {
+		runtimeWrapper.runtimeContext.SetQueryMode(queryMode)
+	}
+
+	runtimeWrapper.SetFunctionCallTracerFunc = func(tracer vmhost.FunctionCallTracer) {
+		runtimeWrapper.runtimeContext.SetFunctionCallTracer(tracer)
+	}
+
 	runtimeWrapper.StartWasmerInstanceFunc = func(contract []byte, gasLimit uint64, newCode bool) error {
 		return runtimeWrapper.runtimeContext.StartWasmerInstance(contract, gasLimit, newCode)
 	}
@@ -275,6 +301,10 @@ func NewRuntimeContextWrapper(inputRuntimeContext *vmhost.RuntimeContext) *Runti
 		runtimeWrapper.runtimeContext.SetMaxInstanceCount(maxInstances)
 	}
 
+	runtimeWrapper.SetCompiledCodeCacheFunc = func(cache vmhost.CompiledCodeCache, gasScheduleVersion []byte) {
+		runtimeWrapper.runtimeContext.SetCompiledCodeCache(cache, gasScheduleVersion)
+	}
+
 	runtimeWrapper.VerifyContractCodeFunc = func() error {
 		return runtimeWrapper.runtimeContext.VerifyContractCode()
 	}
@@ -520,6 +550,11 @@ func (contextWrapper *RuntimeContextWrapper) ResetWarmInstance() {
 	contextWrapper.ResetWarmInstanceFunc()
 }
 
+// GetWarmInstancePoolMetrics calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) GetWarmInstancePoolMetrics() vmhost.WarmInstancePoolMetrics {
+	return contextWrapper.GetWarmInstancePoolMetricsFunc()
+}
+
 // ReadOnly calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
 func (contextWrapper *RuntimeContextWrapper) ReadOnly() bool {
 	return contextWrapper.ReadOnlyFunc()
@@ -530,6 +565,16 @@ func (contextWrapper *RuntimeContextWrapper) SetReadOnly(readOnly bool) {
 	contextWrapper.SetReadOnlyFunc(readOnly)
 }
 
+// QueryMode calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) QueryMode() bool {
+	return contextWrapper.QueryModeFunc()
+}
+
+// SetQueryMode calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) SetQueryMode(queryMode bool) {
+	contextWrapper.SetQueryModeFunc(queryMode)
+}
+
 // StartWasmerInstance calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
 func (contextWrapper *RuntimeContextWrapper) StartWasmerInstance(contract []byte, gasLimit uint64, newCode bool) error {
 	return contextWrapper.StartWasmerInstanceFunc(contract, gasLimit, newCode)
@@ -545,6 +590,11 @@ func (contextWrapper *RuntimeContextWrapper) SetMaxInstanceCount(maxInstances ui
 	contextWrapper.SetMaxInstanceCountFunc(maxInstances)
 }
 
+// SetCompiledCodeCache calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) SetCompiledCodeCache(cache vmhost.CompiledCodeCache, gasScheduleVersion []byte) {
+	contextWrapper.SetCompiledCodeCacheFunc(cache, gasScheduleVersion)
+}
+
 // VerifyContractCode calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
 func (contextWrapper *RuntimeContextWrapper) VerifyContractCode() error {
 	return contextWrapper.VerifyContractCodeFunc()
@@ -635,6 +685,11 @@ func (contextWrapper *RuntimeContextWrapper) GetAllErrors() error {
 	return contextWrapper.GetAllErrorsFunc()
 }
 
+// SetFunctionCallTracer calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) SetFunctionCallTracer(tracer vmhost.FunctionCallTracer) {
+	contextWrapper.SetFunctionCallTracerFunc(tracer)
+}
+
 // InitState calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
 func (contextWrapper *RuntimeContextWrapper) InitState() {
 	contextWrapper.InitStateFunc()
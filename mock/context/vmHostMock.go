@@ -27,8 +27,11 @@ type VMHostMock struct {
 	StorageContext    vmhost.StorageContext
 	BigIntContext     vmhost.BigIntContext
 
-	SCAPIMethods  *wasmer.Imports
-	IsBuiltinFunc bool
+	SCAPIMethods              *wasmer.Imports
+	IsBuiltinFunc             bool
+	StrictReadOnly            bool
+	StorageAccessCostDisabled bool
+	StorageSizeDisabled       bool
 }
 
 // GetVersion mocked method
@@ -96,6 +99,31 @@ func (host *VMHostMock) IsDCDTFunctionsEnabled() bool {
 	return true
 }
 
+// IsStrictReadOnlyEnabled mocked method
+func (host *VMHostMock) IsStrictReadOnlyEnabled() bool {
+	return host.StrictReadOnly
+}
+
+// IsAsyncContextMeteringEnabled mocked method
+func (host *VMHostMock) IsAsyncContextMeteringEnabled() bool {
+	return true
+}
+
+// IsStorageSizeEnabled mocked method
+func (host *VMHostMock) IsStorageSizeEnabled() bool {
+	return !host.StorageSizeDisabled
+}
+
+// IsStorageAccessCostEnabled mocked method
+func (host *VMHostMock) IsStorageAccessCostEnabled() bool {
+	return !host.StorageAccessCostDisabled
+}
+
+// IsImportEnabled mocked method
+func (host *VMHostMock) IsImportEnabled(_ string) bool {
+	return true
+}
+
 // AreInSameShard mocked method
 func (host *VMHostMock) AreInSameShard(_ []byte, _ []byte) bool {
 	return true
@@ -106,6 +134,11 @@ func (host *VMHostMock) ExecuteDCDTTransfer(_ []byte, _ []byte, _ []byte, _ uint
 	return nil, 0, nil
 }
 
+// ExecuteMultiDCDTTransfer mocked method
+func (host *VMHostMock) ExecuteMultiDCDTTransfer(_ []byte, _ []byte, _ []*vmcommon.DCDTTransfer, _ vm.CallType) (*vmcommon.VMOutput, uint64, error) {
+	return nil, 0, nil
+}
+
 // CreateNewContract mocked method
 func (host *VMHostMock) CreateNewContract(_ *vmcommon.ContractCreateInput) ([]byte, error) {
 	return nil, nil
@@ -157,6 +190,26 @@ func (host *VMHostMock) RunSmartContractCall(_ *vmcommon.ContractCallInput) (vmO
 	return nil, nil
 }
 
+// RunSmartContractQuery mocked method
+func (host *VMHostMock) RunSmartContractQuery(_ *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error) {
+	return nil, nil
+}
+
+// SimulateCall mocked method
+func (host *VMHostMock) SimulateCall(_ *vmcommon.ContractCallInput, _ *vmhost.StateOverrides) (vmOutput *vmcommon.VMOutput, err error) {
+	return nil, nil
+}
+
+// RunSmartContractCallWithAccessList mocked method
+func (host *VMHostMock) RunSmartContractCallWithAccessList(_ *vmcommon.ContractCallInput, _ vmhost.StorageAccessList) (vmOutput *vmcommon.VMOutput, err error) {
+	return nil, nil
+}
+
+// EstimateGas mocked method
+func (host *VMHostMock) EstimateGas(_ *vmcommon.ContractCallInput) (*vmhost.GasEstimate, error) {
+	return nil, nil
+}
+
 // Close -
 func (host *VMHostMock) Close() error {
 	return nil
@@ -171,6 +224,19 @@ func (host *VMHostMock) RunSmartContractCreate(_ *vmcommon.ContractCreateInput)
 func (host *VMHostMock) GasScheduleChange(_ config.GasScheduleMap) {
 }
 
+// SetCallTracer mocked method
+func (host *VMHostMock) SetCallTracer(_ vmhost.CallTracer) {
+}
+
+// SetProbeTracer mocked method
+func (host *VMHostMock) SetProbeTracer(_ vmhost.ProbeTracer) {
+}
+
+// ProbeTracer mocked method
+func (host *VMHostMock) ProbeTracer() vmhost.ProbeTracer {
+	return nil
+}
+
 // SetBuiltInFunctionsContainer mocked method
 func (host *VMHostMock) SetBuiltInFunctionsContainer(_ vmcommon.BuiltInFunctionContainer) {
 }
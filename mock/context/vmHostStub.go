@@ -21,28 +21,41 @@ type VMHostStub struct {
 	ClearStateStackCalled func()
 	GetVersionCalled      func() string
 
-	CryptoCalled                func() crypto.VMCrypto
-	BlockchainCalled            func() vmhost.BlockchainContext
-	RuntimeCalled               func() vmhost.RuntimeContext
-	BigIntCalled                func() vmhost.BigIntContext
-	OutputCalled                func() vmhost.OutputContext
-	MeteringCalled              func() vmhost.MeteringContext
-	StorageCalled               func() vmhost.StorageContext
-	ExecuteDCDTTransferCalled   func(destination []byte, sender []byte, tokenIdentifier []byte, nonce uint64, value *big.Int, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
-	CreateNewContractCalled     func(input *vmcommon.ContractCreateInput) ([]byte, error)
-	ExecuteOnSameContextCalled  func(input *vmcommon.ContractCallInput) (*vmhost.AsyncContextInfo, error)
-	ExecuteOnDestContextCalled  func(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, *vmhost.AsyncContextInfo, error)
-	GetAPIMethodsCalled         func() *wasmer.Imports
-	IsBuiltinFunctionNameCalled func(functionName string) bool
-	AreInSameShardCalled        func(left []byte, right []byte) bool
-
-	RunSmartContractCallCalled   func(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error)
-	RunSmartContractCreateCalled func(input *vmcommon.ContractCreateInput) (vmOutput *vmcommon.VMOutput, err error)
-	GetGasScheduleMapCalled      func() config.GasScheduleMap
-	GasScheduleChangeCalled      func(newGasSchedule config.GasScheduleMap)
-	IsInterfaceNilCalled         func() bool
+	CryptoCalled                        func() crypto.VMCrypto
+	BlockchainCalled                    func() vmhost.BlockchainContext
+	RuntimeCalled                       func() vmhost.RuntimeContext
+	BigIntCalled                        func() vmhost.BigIntContext
+	OutputCalled                        func() vmhost.OutputContext
+	MeteringCalled                      func() vmhost.MeteringContext
+	StorageCalled                       func() vmhost.StorageContext
+	ExecuteDCDTTransferCalled           func(destination []byte, sender []byte, tokenIdentifier []byte, nonce uint64, value *big.Int, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
+	ExecuteMultiDCDTTransferCalled      func(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
+	CreateNewContractCalled             func(input *vmcommon.ContractCreateInput) ([]byte, error)
+	ExecuteOnSameContextCalled          func(input *vmcommon.ContractCallInput) (*vmhost.AsyncContextInfo, error)
+	ExecuteOnDestContextCalled          func(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, *vmhost.AsyncContextInfo, error)
+	GetAPIMethodsCalled                 func() *wasmer.Imports
+	IsBuiltinFunctionNameCalled         func(functionName string) bool
+	AreInSameShardCalled                func(left []byte, right []byte) bool
+	IsImportEnabledCalled               func(importName string) bool
+	IsStrictReadOnlyEnabledCalled       func() bool
+	IsAsyncContextMeteringEnabledCalled func() bool
+	IsStorageSizeEnabledCalled          func() bool
+	IsStorageAccessCostEnabledCalled    func() bool
+
+	RunSmartContractCallCalled               func(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error)
+	RunSmartContractCreateCalled             func(input *vmcommon.ContractCreateInput) (vmOutput *vmcommon.VMOutput, err error)
+	RunSmartContractQueryCalled              func(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error)
+	SimulateCallCalled                       func(input *vmcommon.ContractCallInput, overrides *vmhost.StateOverrides) (vmOutput *vmcommon.VMOutput, err error)
+	EstimateGasCalled                        func(input *vmcommon.ContractCallInput) (*vmhost.GasEstimate, error)
+	RunSmartContractCallWithAccessListCalled func(input *vmcommon.ContractCallInput, accessList vmhost.StorageAccessList) (vmOutput *vmcommon.VMOutput, err error)
+	GetGasScheduleMapCalled                  func() config.GasScheduleMap
+	GasScheduleChangeCalled                  func(newGasSchedule config.GasScheduleMap)
+	IsInterfaceNilCalled                     func() bool
 
 	SetRuntimeContextCalled func(runtime vmhost.RuntimeContext)
+	SetCallTracerCalled     func(tracer vmhost.CallTracer)
+	SetProbeTracerCalled    func(tracer vmhost.ProbeTracer)
+	ProbeTracerCalled       func() vmhost.ProbeTracer
 	GetContextsCalled       func() (vmhost.BigIntContext, vmhost.BlockchainContext, vmhost.MeteringContext, vmhost.OutputContext, vmhost.RuntimeContext, vmhost.StorageContext)
 
 	SetBuiltInFunctionsContainerCalled func(builtInFuncs vmcommon.BuiltInFunctionContainer)
@@ -142,6 +155,51 @@ func (vhs *VMHostStub) IsDCDTFunctionsEnabled() bool {
 	return true
 }
 
+// IsStrictReadOnlyEnabled mocked method
+func (vhs *VMHostStub) IsStrictReadOnlyEnabled() bool {
+	if vhs.IsStrictReadOnlyEnabledCalled != nil {
+		return vhs.IsStrictReadOnlyEnabledCalled()
+	}
+
+	return false
+}
+
+// IsAsyncContextMeteringEnabled mocked method
+func (vhs *VMHostStub) IsAsyncContextMeteringEnabled() bool {
+	if vhs.IsAsyncContextMeteringEnabledCalled != nil {
+		return vhs.IsAsyncContextMeteringEnabledCalled()
+	}
+
+	return false
+}
+
+// IsStorageSizeEnabled mocked method
+func (vhs *VMHostStub) IsStorageSizeEnabled() bool {
+	if vhs.IsStorageSizeEnabledCalled != nil {
+		return vhs.IsStorageSizeEnabledCalled()
+	}
+
+	return false
+}
+
+// IsStorageAccessCostEnabled mocked method
+func (vhs *VMHostStub) IsStorageAccessCostEnabled() bool {
+	if vhs.IsStorageAccessCostEnabledCalled != nil {
+		return vhs.IsStorageAccessCostEnabledCalled()
+	}
+
+	return false
+}
+
+// IsImportEnabled mocked method
+func (vhs *VMHostStub) IsImportEnabled(importName string) bool {
+	if vhs.IsImportEnabledCalled != nil {
+		return vhs.IsImportEnabledCalled(importName)
+	}
+
+	return true
+}
+
 // Output mocked method
 func (vhs *VMHostStub) Output() vmhost.OutputContext {
 	if vhs.OutputCalled != nil {
@@ -174,6 +232,14 @@ func (vhs *VMHostStub) ExecuteDCDTTransfer(destination []byte, sender []byte, to
 	return nil, 0, nil
 }
 
+// ExecuteMultiDCDTTransfer mocked method
+func (vhs *VMHostStub) ExecuteMultiDCDTTransfer(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error) {
+	if vhs.ExecuteMultiDCDTTransferCalled != nil {
+		return vhs.ExecuteMultiDCDTTransferCalled(destination, sender, transfers, callType)
+	}
+	return nil, 0, nil
+}
+
 // CreateNewContract mocked method
 func (vhs *VMHostStub) CreateNewContract(input *vmcommon.ContractCreateInput) ([]byte, error) {
 	if vhs.CreateNewContractCalled != nil {
@@ -238,6 +304,38 @@ func (vhs *VMHostStub) RunSmartContractCall(input *vmcommon.ContractCallInput) (
 	return nil, nil
 }
 
+// RunSmartContractQuery mocked method
+func (vhs *VMHostStub) RunSmartContractQuery(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error) {
+	if vhs.RunSmartContractQueryCalled != nil {
+		return vhs.RunSmartContractQueryCalled(input)
+	}
+	return nil, nil
+}
+
+// SimulateCall mocked method
+func (vhs *VMHostStub) SimulateCall(input *vmcommon.ContractCallInput, overrides *vmhost.StateOverrides) (vmOutput *vmcommon.VMOutput, err error) {
+	if vhs.SimulateCallCalled != nil {
+		return vhs.SimulateCallCalled(input, overrides)
+	}
+	return nil, nil
+}
+
+// RunSmartContractCallWithAccessList mocked method
+func (vhs *VMHostStub) RunSmartContractCallWithAccessList(input *vmcommon.ContractCallInput, accessList vmhost.StorageAccessList) (vmOutput *vmcommon.VMOutput, err error) {
+	if vhs.RunSmartContractCallWithAccessListCalled != nil {
+		return vhs.RunSmartContractCallWithAccessListCalled(input, accessList)
+	}
+	return nil, nil
+}
+
+// EstimateGas mocked method
+func (vhs *VMHostStub) EstimateGas(input *vmcommon.ContractCallInput) (*vmhost.GasEstimate, error) {
+	if vhs.EstimateGasCalled != nil {
+		return vhs.EstimateGasCalled(input)
+	}
+	return nil, nil
+}
+
 // Close -
 func (vhs *VMHostStub) Close() error {
 	return nil
@@ -258,6 +356,28 @@ func (vhs *VMHostStub) GasScheduleChange(newGasSchedule config.GasScheduleMap) {
 	}
 }
 
+// SetCallTracer mocked method
+func (vhs *VMHostStub) SetCallTracer(tracer vmhost.CallTracer) {
+	if vhs.SetCallTracerCalled != nil {
+		vhs.SetCallTracerCalled(tracer)
+	}
+}
+
+// SetProbeTracer mocked method
+func (vhs *VMHostStub) SetProbeTracer(tracer vmhost.ProbeTracer) {
+	if vhs.SetProbeTracerCalled != nil {
+		vhs.SetProbeTracerCalled(tracer)
+	}
+}
+
+// ProbeTracer mocked method
+func (vhs *VMHostStub) ProbeTracer() vmhost.ProbeTracer {
+	if vhs.ProbeTracerCalled != nil {
+		return vhs.ProbeTracerCalled()
+	}
+	return nil
+}
+
 // SetBuiltInFunctionsContainer mocked method
 func (vhs *VMHostStub) SetBuiltInFunctionsContainer(builtInFuncs vmcommon.BuiltInFunctionContainer) {
 	if vhs.SetBuiltInFunctionsContainerCalled != nil {
@@ -0,0 +1,142 @@
+package contracts
+
+import (
+	"math/big"
+
+	mock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
+	test "github.com/kalyan3104/k-chain-vm-v1_3-go/testcommon"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/vmhooks"
+	"github.com/stretchr/testify/require"
+)
+
+// AsyncContextIdentifier is the async context used by the async context mock contracts
+var AsyncContextIdentifier = []byte("ctx")
+
+// AsyncContextCallData is the data of the async calls created by the async context mock contracts
+var AsyncContextCallData = []byte("doSomething@01")
+
+// AsyncContextSuccessCallback is the success callback of the async calls created by the async context mock contracts
+var AsyncContextSuccessCallback = []byte("success")
+
+// AsyncContextErrorCallback is the error callback of the async calls created by the async context mock contracts
+var AsyncContextErrorCallback = []byte("error")
+
+const (
+	identifierOffset = int32(0)
+	destOffset       = int32(64)
+	valueOffset      = int32(128)
+	dataOffset       = int32(192)
+	successOffset    = int32(256)
+	errorOffset      = int32(320)
+	callbackOffset   = int32(384)
+)
+
+// CreateAsyncCallsParentMock is an exposed mock contract method which registers the configured
+// number of async calls through the createAsyncCall hook, finishing the gas consumed by each of them
+func CreateAsyncCallsParentMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(*AsyncContextTestConfig)
+	instanceMock.AddMockMethod("createAsyncCalls", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		storeAsyncContextArguments(host, instance, testConfig)
+
+		runtime := host.Runtime()
+		metering := host.Metering()
+		for i := 0; i < testConfig.AsyncCalls; i++ {
+			gasLeftBefore := metering.GasLeft()
+			vmhooks.CreateAsyncCallWithHost(
+				host,
+				identifierOffset, int32(len(AsyncContextIdentifier)),
+				destOffset,
+				valueOffset,
+				dataOffset, int32(len(AsyncContextCallData)),
+				successOffset, int32(len(AsyncContextSuccessCallback)),
+				errorOffset, int32(len(AsyncContextErrorCallback)),
+				testConfig.GasForAsyncCall,
+			)
+			if runtime.GetRuntimeBreakpointValue() != vmhost.BreakpointNone {
+				return instance
+			}
+
+			gasConsumed := gasLeftBefore - metering.GasLeft()
+			host.Output().Finish(big.NewInt(0).SetUint64(gasConsumed).Bytes())
+		}
+
+		if len(testConfig.Callback) == 0 {
+			return instance
+		}
+
+		gasLeftBefore := metering.GasLeft()
+		result := vmhooks.SetAsyncContextCallbackWithHost(
+			host,
+			identifierOffset, int32(len(AsyncContextIdentifier)),
+			callbackOffset, int32(len(testConfig.Callback)),
+		)
+		if result != 0 {
+			return instance
+		}
+
+		gasConsumed := gasLeftBefore - metering.GasLeft()
+		host.Output().Finish(big.NewInt(0).SetUint64(gasConsumed).Bytes())
+
+		return instance
+	})
+}
+
+// AsyncContextChildMock is an exposed mock contract method which is the destination of the async calls
+// created by the async context mock contracts
+func AsyncContextChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(*AsyncContextTestConfig)
+	instanceMock.AddMockMethod("doSomething", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByChild)
+		host.Output().Finish([]byte("child"))
+		return instance
+	})
+}
+
+// AsyncContextCallbacksParentMock is an exposed mock contract method which adds the callbacks of the
+// async calls and the completion callback of the async context, each finishing its own name
+func AsyncContextCallbacksParentMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(*AsyncContextTestConfig)
+	callbacks := []string{
+		string(AsyncContextSuccessCallback),
+		string(AsyncContextErrorCallback),
+	}
+	if len(testConfig.Callback) > 0 {
+		callbacks = append(callbacks, testConfig.Callback)
+	}
+
+	for _, callback := range callbacks {
+		name := callback
+		instanceMock.AddMockMethod(name, func() *mock.InstanceMock {
+			host := instanceMock.Host
+			instance := mock.GetMockInstance(host)
+			host.Metering().UseGas(testConfig.GasUsedByCallback)
+			host.Output().Finish([]byte(name))
+			return instance
+		})
+	}
+}
+
+func storeAsyncContextArguments(host vmhost.VMHost, instance *mock.InstanceMock, testConfig *AsyncContextTestConfig) {
+	t := instance.T
+	runtime := host.Runtime()
+
+	value := make([]byte, vmhost.BalanceLen)
+	arguments := map[int32][]byte{
+		identifierOffset: AsyncContextIdentifier,
+		destOffset:       test.ChildAddress,
+		valueOffset:      value,
+		dataOffset:       AsyncContextCallData,
+		successOffset:    AsyncContextSuccessCallback,
+		errorOffset:      AsyncContextErrorCallback,
+		callbackOffset:   []byte(testConfig.Callback),
+	}
+	for offset, argument := range arguments {
+		err := runtime.MemStore(offset, argument)
+		require.Nil(t, err)
+	}
+}
@@ -58,6 +58,14 @@ type AsyncCallMultiChildTestConfig struct {
 	ChildCalls int
 }
 
+// AsyncContextTestConfig is configuration for tests of async calls registered in async contexts
+type AsyncContextTestConfig struct {
+	AsyncCallBaseTestConfig
+	AsyncCalls      int
+	GasForAsyncCall int64
+	Callback        string
+}
+
 // GasTestConfig interface for gas tests configs
 type GasTestConfig interface {
 	GetGasUsedByChild() uint64
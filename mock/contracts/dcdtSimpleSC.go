@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"math/big"
 
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/txDataBuilder"
 	mock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
 	test "github.com/kalyan3104/k-chain-vm-v1_3-go/testcommon"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/vmhooks"
 )
 
@@ -85,6 +87,88 @@ func ExecDCDTTransferWithAPICall(instanceMock *mock.InstanceMock, config interfa
 	})
 }
 
+// ExecMultiDCDTTransferWithAPICall is an exposed mock contract method
+func ExecMultiDCDTTransferWithAPICall(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("execMultiDCDTTransferWithAPICall", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByParent)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) < 3 {
+			host.Runtime().SignalUserError("need at least 3 arguments")
+			return instance
+		}
+
+		receiver := arguments[0]
+		functionName := arguments[1]
+		transfers := make([]*vmcommon.DCDTTransfer, 0, len(arguments)-2)
+		for _, tokenName := range arguments[2:] {
+			transfers = append(transfers, &vmcommon.DCDTTransfer{
+				DCDTTokenName: tokenName,
+				DCDTValue:     big.NewInt(int64(testConfig.DCDTTokensToTransfer)),
+			})
+		}
+
+		vmhooks.MultiTransferDCDTNFTExecuteWithTypedArgs(
+			host,
+			receiver,
+			transfers,
+			int64(testConfig.GasProvidedToChild),
+			functionName,
+			nil)
+
+		return instance
+	})
+}
+
+// AcceptMultiPaymentChildMock is an exposed mock contract method
+func AcceptMultiPaymentChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("acceptMultiPayment", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByChild)
+
+		dcdtTransfers := host.Runtime().GetVMInput().DCDTTransfers
+		host.Output().Finish(big.NewInt(int64(len(dcdtTransfers))).Bytes())
+		for _, dcdtTransfer := range dcdtTransfers {
+			host.Output().Finish(dcdtTransfer.DCDTTokenName)
+			host.Output().Finish(dcdtTransfer.DCDTValue.Bytes())
+		}
+
+		return instance
+	})
+}
+
+// ReadMultiPaymentByIndexChildMock is an exposed mock contract method
+func ReadMultiPaymentByIndexChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("readMultiPaymentByIndex", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByChild)
+
+		numTransfers := vmhooks.GetNumDCDTTransfersWithHost(host)
+		if host.Runtime().GetRuntimeBreakpointValue() != vmhost.BreakpointNone {
+			return instance
+		}
+
+		host.Output().Finish(big.NewInt(int64(numTransfers)).Bytes())
+		for index := int32(0); index < numTransfers; index++ {
+			nameLength := vmhooks.GetDCDTTokenNameByIndexWithHost(host, 0, index)
+			tokenName, _ := host.Runtime().MemLoad(0, nameLength)
+			host.Output().Finish(tokenName)
+
+			nonce := vmhooks.GetDCDTTokenNonceByIndexWithHost(host, index)
+			host.Output().Finish(big.NewInt(nonce).Bytes())
+		}
+
+		return instance
+	})
+}
+
 // ExecDCDTTransferAndAsyncCallChild is an exposed mock contract method
 func ExecDCDTTransferAndAsyncCallChild(instanceMock *mock.InstanceMock, config interface{}) {
 	testConfig := config.(*AsyncCallTestConfig)
@@ -122,3 +206,115 @@ func ExecDCDTTransferAndAsyncCallChild(instanceMock *mock.InstanceMock, config i
 		return instance
 	})
 }
+
+// DCDTLocalMintAndBurnMock is an exposed mock contract method
+func DCDTLocalMintAndBurnMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("localMintAndBurn", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByParent)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) != 1 {
+			host.Runtime().SignalUserError("need 1 argument")
+			return instance
+		}
+
+		tokenID := arguments[0]
+		value := big.NewInt(int64(testConfig.DCDTTokensToTransfer))
+		if vmhooks.DCDTLocalMintWithTypedArgs(host, tokenID, big.NewInt(0).Mul(value, big.NewInt(2))) != 0 {
+			return instance
+		}
+		if vmhooks.DCDTLocalBurnWithTypedArgs(host, tokenID, value) != 0 {
+			return instance
+		}
+
+		roles := vmhooks.GetDCDTLocalRolesWithTypedArgs(host, tokenID)
+		host.Output().Finish(big.NewInt(roles).Bytes())
+
+		return instance
+	})
+}
+
+// DCDTNFTCreateAndBurnMock is an exposed mock contract method
+func DCDTNFTCreateAndBurnMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("nftCreateAndBurn", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByParent)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) != 1 {
+			host.Runtime().SignalUserError("need 1 argument")
+			return instance
+		}
+
+		tokenID := arguments[0]
+		quantity := big.NewInt(int64(testConfig.DCDTTokensToTransfer))
+		nonce := vmhooks.DCDTNFTCreateWithTypedArgs(
+			host,
+			tokenID,
+			quantity,
+			[]byte("name"),
+			0,
+			[]byte("hash"),
+			[]byte("attributes"),
+			[][]byte{[]byte("uri")})
+		if nonce < 0 {
+			return instance
+		}
+
+		if vmhooks.DCDTNFTBurnWithTypedArgs(host, tokenID, uint64(nonce), big.NewInt(1)) != 0 {
+			return instance
+		}
+
+		host.Output().Finish(big.NewInt(nonce).Bytes())
+
+		return instance
+	})
+}
+
+// DCDTNFTAddQuantityAndUpdateAttributesMock is an exposed mock contract method
+func DCDTNFTAddQuantityAndUpdateAttributesMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("nftAddQuantityAndUpdateAttributes", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByParent)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) != 2 {
+			host.Runtime().SignalUserError("need 2 arguments")
+			return instance
+		}
+
+		tokenID := arguments[0]
+		quantity := big.NewInt(int64(testConfig.DCDTTokensToTransfer))
+		nonce := vmhooks.DCDTNFTCreateWithTypedArgs(
+			host,
+			tokenID,
+			quantity,
+			[]byte("name"),
+			0,
+			[]byte("hash"),
+			[]byte("attributes"),
+			[][]byte{[]byte("uri")})
+		if nonce < 0 {
+			return instance
+		}
+
+		if vmhooks.DCDTNFTAddQuantityWithTypedArgs(host, tokenID, uint64(nonce), big.NewInt(3)) != 0 {
+			return instance
+		}
+
+		if vmhooks.DCDTNFTUpdateAttributesWithTypedArgs(host, tokenID, uint64(nonce), arguments[1]) != 0 {
+			return instance
+		}
+
+		host.Output().Finish(big.NewInt(nonce).Bytes())
+
+		return instance
+	})
+}
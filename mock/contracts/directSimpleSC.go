@@ -8,6 +8,7 @@ import (
 	"github.com/kalyan3104/k-chain-vm-common-go/txDataBuilder"
 	mock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
 	test "github.com/kalyan3104/k-chain-vm-v1_3-go/testcommon"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/vmhooks"
 )
 
 // WasteGasChildMock is an exposed mock contract method
@@ -54,6 +55,111 @@ func FailChildAndBurnDCDTMock(instanceMock *mock.InstanceMock, config interface{
 	})
 }
 
+// WriteStorageChildMock is an exposed mock contract method
+func WriteStorageChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	instanceMock.AddMockMethod("writeStorage", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+
+		_, err := host.Storage().SetStorage(test.ChildKey, test.ChildData)
+		if err != nil {
+			host.Runtime().FailExecution(err)
+			return instance
+		}
+
+		host.Output().WriteLog(instance.Address, [][]byte{test.ChildKey}, test.ChildData)
+		return instance
+	})
+}
+
+// TransferValueChildMock is an exposed mock contract method
+func TransferValueChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	instanceMock.AddMockMethod("transferValue", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+
+		err := host.Output().Transfer(test.ThirdPartyAddress, instance.Address, 0, 0, big.NewInt(1), nil, 0)
+		if err != nil {
+			host.Runtime().FailExecution(err)
+		}
+
+		return instance
+	})
+}
+
+// IterateStorageChildMock is an exposed mock contract method
+func IterateStorageChildMock(instanceMock *mock.InstanceMock, config interface{}) {
+	instanceMock.AddMockMethod("iterateStorage", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) != 3 {
+			host.Runtime().SignalUserError("need 3 arguments")
+			return instance
+		}
+
+		prefix := arguments[0]
+		cursor := arguments[1]
+		limit := int32(big.NewInt(0).SetBytes(arguments[2]).Int64())
+		entries, ok := vmhooks.StorageIterateWithTypedArgs(host, prefix, cursor, limit)
+		if !ok {
+			return instance
+		}
+
+		for _, entry := range entries {
+			host.Output().Finish(entry.Key)
+			host.Output().Finish(entry.Value)
+		}
+
+		return instance
+	})
+}
+
+// StorageSizeParentMock is an exposed mock contract method
+func StorageSizeParentMock(instanceMock *mock.InstanceMock, config interface{}) {
+	instanceMock.AddMockMethod("writeAndGetStorageSize", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+
+		_, err := host.Storage().SetStorage(test.ChildKey, test.ChildData)
+		if err != nil {
+			host.Runtime().FailExecution(err)
+			return instance
+		}
+
+		size := vmhooks.GetStorageSizeWithHost(host)
+		host.Output().Finish(big.NewInt(size).Bytes())
+
+		return instance
+	})
+}
+
+// ExecReadOnlyParentMock is an exposed mock contract method
+func ExecReadOnlyParentMock(instanceMock *mock.InstanceMock, config interface{}) {
+	testConfig := config.(DirectCallGasTestConfig)
+	instanceMock.AddMockMethod("execReadOnly", func() *mock.InstanceMock {
+		host := instanceMock.Host
+		instance := mock.GetMockInstance(host)
+		host.Metering().UseGas(testConfig.GasUsedByParent)
+
+		arguments := host.Runtime().Arguments()
+		if len(arguments) < 2 {
+			host.Runtime().SignalUserError("need at least 2 arguments")
+			return instance
+		}
+
+		vmhooks.ExecuteReadOnlyWithTypedArguments(
+			host,
+			int64(testConfig.GasProvidedToChild),
+			arguments[1],
+			arguments[0],
+			arguments[2:])
+
+		return instance
+	})
+}
+
 // ExecOnSameCtxParentMock is an exposed mock contract method
 func ExecOnSameCtxParentMock(instanceMock *mock.InstanceMock, config interface{}) {
 	testConfig := config.(DirectCallGasTestConfig)
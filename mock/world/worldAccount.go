@@ -8,6 +8,7 @@ import (
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/crypto/hashing"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 )
 
 // ErrOperationNotPermitted indicates an operation rejected due to insufficient
@@ -49,6 +50,16 @@ func (a *Account) StorageValue(key string) []byte {
 	return value
 }
 
+// StorageSize yields the number of bytes stored by the account, i.e. the
+// length of each key holding a value plus the length of the value
+func (a *Account) StorageSize() uint64 {
+	size := uint64(0)
+	for key, value := range a.Storage {
+		size += vmhost.StorageEntrySize([]byte(key), value)
+	}
+	return size
+}
+
 // SetCodeAndMetadata changes the account code, as well as all fields depending on it:
 // CodeHash, IsSmartContract, CodeMetadata.
 // The code metadata must be given explicitly.
@@ -8,11 +8,14 @@ import (
 	"math/big"
 
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 
 	"github.com/kalyan3104/k-chain-core-go/data/dcdt"
 )
 
 var _ vmcommon.BlockchainHook = (*MockWorld)(nil)
+var _ vmhost.StorageIterator = (*MockWorld)(nil)
+var _ vmhost.StorageSizeProvider = (*MockWorld)(nil)
 
 // ErrBuiltinFuncWrapperNotInitialized means that the builtin function wrapper was used before initialization.
 var ErrBuiltinFuncWrapperNotInitialized = errors.New("builtin function not found or container not initialized")
@@ -58,6 +61,35 @@ func (b *MockWorld) GetStorageData(accountAddress []byte, key []byte) ([]byte, u
 	return acct.StorageValue(string(key)), 0, nil
 }
 
+// IterateStorage yields at most limit storage entries of an account, whose keys
+// start with the given prefix and come strictly after the given cursor, sorted by key.
+func (b *MockWorld) IterateStorage(accountAddress []byte, prefix []byte, cursor []byte, limit int) ([]*vmhost.StorageEntry, error) {
+	// custom error
+	if b.Err != nil {
+		return nil, b.Err
+	}
+
+	acct := b.AcctMap.GetAccount(accountAddress)
+	if acct == nil {
+		return make([]*vmhost.StorageEntry, 0), nil
+	}
+	return vmhost.MergeStorageEntries(nil, acct.Storage, prefix, cursor, limit), nil
+}
+
+// GetStorageSize yields the number of bytes stored by an account.
+func (b *MockWorld) GetStorageSize(accountAddress []byte) (uint64, error) {
+	// custom error
+	if b.Err != nil {
+		return 0, b.Err
+	}
+
+	acct := b.AcctMap.GetAccount(accountAddress)
+	if acct == nil {
+		return 0, nil
+	}
+	return acct.StorageSize(), nil
+}
+
 // GetBlockhash should return the hash of the nth previous blockchain.
 // Offset specifies how many blocks we need to look back.
 func (b *MockWorld) GetBlockhash(nonce uint64) ([]byte, error) {
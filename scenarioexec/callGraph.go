@@ -0,0 +1,157 @@
+package scenarioexec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	er "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/reconstructor"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// CallGraphNode is a single contract call executed by the VM, together with the calls it triggered,
+// in the order they were executed: nested calls, synchronous async calls and callbacks.
+type CallGraphNode struct {
+	TxID        string           `json:"txId,omitempty"`
+	Caller      string           `json:"caller"`
+	Callee      string           `json:"callee"`
+	Function    string           `json:"function"`
+	CallType    string           `json:"callType"`
+	Value       string           `json:"value"`
+	GasProvided uint64           `json:"gasProvided"`
+	GasUsed     uint64           `json:"gasUsed"`
+	ReturnCode  string           `json:"returnCode"`
+	Calls       []*CallGraphNode `json:"calls,omitempty"`
+}
+
+// CallGraphTracker records the tree of contract calls executed by each transaction of a scenario run.
+type CallGraphTracker struct {
+	exprReconstructor er.ExprReconstructor
+	currentTxID       string
+	roots             []*CallGraphNode
+	stack             []*CallGraphNode
+}
+
+var _ vmhost.CallTracer = (*CallGraphTracker)(nil)
+
+// NewCallGraphTracker creates an empty CallGraphTracker.
+func NewCallGraphTracker() *CallGraphTracker {
+	return &CallGraphTracker{
+		roots: make([]*CallGraphNode, 0),
+		stack: make([]*CallGraphNode, 0),
+	}
+}
+
+// BeginTx marks the start of a new transaction; the calls executed next are attributed to it.
+// Calls left unfinished by the previous transaction, e.g. because of a VM panic, are discarded.
+func (cgt *CallGraphTracker) BeginTx(txID string) {
+	cgt.currentTxID = txID
+	cgt.stack = cgt.stack[:0]
+}
+
+// TraceCallStart records a new call, nested in the call currently executing, if any.
+func (cgt *CallGraphTracker) TraceCallStart(input *vmcommon.ContractCallInput) {
+	node := &CallGraphNode{
+		Caller:      cgt.exprReconstructor.Reconstruct(input.CallerAddr, er.AddressHint),
+		Callee:      cgt.exprReconstructor.Reconstruct(input.RecipientAddr, er.AddressHint),
+		Function:    input.Function,
+		CallType:    input.CallType.ToString(),
+		Value:       "0",
+		GasProvided: input.GasProvided,
+	}
+	if input.CallValue != nil {
+		node.Value = input.CallValue.String()
+	}
+
+	if len(cgt.stack) == 0 {
+		node.TxID = cgt.currentTxID
+		cgt.roots = append(cgt.roots, node)
+	} else {
+		parent := cgt.stack[len(cgt.stack)-1]
+		parent.Calls = append(parent.Calls, node)
+	}
+
+	cgt.stack = append(cgt.stack, node)
+}
+
+// TraceCallEnd completes the call currently executing.
+func (cgt *CallGraphTracker) TraceCallEnd(returnCode vmcommon.ReturnCode, gasRemaining uint64) {
+	if len(cgt.stack) == 0 {
+		return
+	}
+
+	node := cgt.stack[len(cgt.stack)-1]
+	cgt.stack = cgt.stack[:len(cgt.stack)-1]
+
+	node.ReturnCode = returnCode.String()
+	if gasRemaining < node.GasProvided {
+		node.GasUsed = node.GasProvided - gasRemaining
+	}
+}
+
+// Roots returns the top-level calls recorded so far, one per executed transaction.
+func (cgt *CallGraphTracker) Roots() []*CallGraphNode {
+	return cgt.roots
+}
+
+// WriteReport saves the call graph to the given path. Files ending in
+// ".dot" or ".gv" are written as Graphviz digraphs, all others as JSON.
+func (cgt *CallGraphTracker) WriteReport(path string) error {
+	var data []byte
+	var err error
+	if strings.HasSuffix(path, ".dot") || strings.HasSuffix(path, ".gv") {
+		data = []byte(cgt.dotReport())
+	} else {
+		data, err = json.MarshalIndent(cgt.roots, "", "  ")
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// dotReport renders every call as a node, linked to the calls it triggered by
+// edges numbered in execution order, so callback ordering can be followed.
+func (cgt *CallGraphTracker) dotReport() string {
+	var sb strings.Builder
+	sb.WriteString("digraph calls {\n")
+	sb.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+
+	nodeCount := 0
+	var writeNode func(node *CallGraphNode) string
+	writeNode = func(node *CallGraphNode) string {
+		nodeID := fmt.Sprintf("n%d", nodeCount)
+		nodeCount++
+
+		label := fmt.Sprintf("%s\\n%s -> %s\\n%s, value %s\\ngas %d/%d, %s",
+			node.Function, node.Caller, node.Callee,
+			node.CallType, node.Value,
+			node.GasUsed, node.GasProvided, node.ReturnCode)
+		if len(node.TxID) > 0 {
+			label = fmt.Sprintf("tx %s\\n%s", node.TxID, label)
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s];\n", nodeID, dotQuote(label)))
+
+		for i, call := range node.Calls {
+			callID := writeNode(call)
+			sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"%d\"];\n", nodeID, callID, i+1))
+		}
+
+		return nodeID
+	}
+
+	for _, root := range cgt.roots {
+		writeNode(root)
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotQuote quotes a label for Graphviz, keeping the "\n" line breaks already in it.
+func dotQuote(label string) string {
+	return "\"" + strings.ReplaceAll(label, "\"", "\\\"") + "\""
+}
@@ -0,0 +1,147 @@
+package scenarioexec
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
+	"github.com/stretchr/testify/require"
+)
+
+func traceTestCall(tracker *CallGraphTracker, function string, gasProvided uint64) {
+	tracker.TraceCallStart(&vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:  []byte("caller"),
+			CallValue:   big.NewInt(3),
+			CallType:    vm.DirectCall,
+			GasProvided: gasProvided,
+		},
+		RecipientAddr: []byte("callee"),
+		Function:      function,
+	})
+}
+
+func TestCallGraphTracker_NestedCalls(t *testing.T) {
+	tracker := NewCallGraphTracker()
+
+	tracker.BeginTx("1")
+	traceTestCall(tracker, "parent", 100)
+	traceTestCall(tracker, "child", 50)
+	tracker.TraceCallEnd(vmcommon.Ok, 40)
+	traceTestCall(tracker, "callback", 30)
+	tracker.TraceCallEnd(vmcommon.UserError, 30)
+	tracker.TraceCallEnd(vmcommon.Ok, 20)
+
+	tracker.BeginTx("2")
+	traceTestCall(tracker, "unfinished", 10)
+	tracker.BeginTx("3")
+	traceTestCall(tracker, "next", 10)
+	tracker.TraceCallEnd(vmcommon.Ok, 0)
+	tracker.TraceCallEnd(vmcommon.Ok, 0)
+
+	roots := tracker.Roots()
+	require.Len(t, roots, 3)
+
+	parent := roots[0]
+	require.Equal(t, "1", parent.TxID)
+	require.Equal(t, "parent", parent.Function)
+	require.Equal(t, "DirectCall", parent.CallType)
+	require.Equal(t, "3", parent.Value)
+	require.Equal(t, uint64(80), parent.GasUsed)
+	require.Equal(t, vmcommon.Ok.String(), parent.ReturnCode)
+	require.Len(t, parent.Calls, 2)
+
+	require.Empty(t, parent.Calls[0].TxID)
+	require.Equal(t, "child", parent.Calls[0].Function)
+	require.Equal(t, uint64(10), parent.Calls[0].GasUsed)
+	require.Equal(t, "callback", parent.Calls[1].Function)
+	require.Equal(t, uint64(0), parent.Calls[1].GasUsed)
+	require.Equal(t, vmcommon.UserError.String(), parent.Calls[1].ReturnCode)
+
+	require.Equal(t, "unfinished", roots[1].Function)
+	require.Empty(t, roots[1].ReturnCode)
+	require.Equal(t, "3", roots[2].TxID)
+	require.Empty(t, roots[2].Calls)
+}
+
+func TestCallGraphTracker_WriteReport(t *testing.T) {
+	tracker := NewCallGraphTracker()
+	tracker.roots = []*CallGraphNode{
+		{
+			TxID:        "1",
+			Caller:      "address:owner",
+			Callee:      "sc:parent",
+			Function:    "call",
+			CallType:    "DirectCall",
+			Value:       "0",
+			GasProvided: 100,
+			GasUsed:     60,
+			ReturnCode:  "ok",
+			Calls: []*CallGraphNode{
+				{
+					Caller:      "sc:parent",
+					Callee:      "sc:child",
+					Function:    "say \"hi\"",
+					CallType:    "AsynchronousCall",
+					Value:       "5",
+					GasProvided: 50,
+					GasUsed:     50,
+					ReturnCode:  "out of gas",
+				},
+			},
+		},
+	}
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "calls.json")
+	require.Nil(t, tracker.WriteReport(jsonPath))
+	data, err := os.ReadFile(jsonPath)
+	require.Nil(t, err)
+	var roots []*CallGraphNode
+	require.Nil(t, json.Unmarshal(data, &roots))
+	require.Equal(t, tracker.Roots(), roots)
+
+	dotPath := filepath.Join(dir, "calls.dot")
+	require.Nil(t, tracker.WriteReport(dotPath))
+	data, err = os.ReadFile(dotPath)
+	require.Nil(t, err)
+	expected := `digraph calls {
+  node [shape=box, fontname="monospace"];
+  n0 [label="tx 1\ncall\naddress:owner -> sc:parent\nDirectCall, value 0\ngas 60/100, ok"];
+  n1 [label="say \"hi\"\nsc:parent -> sc:child\nAsynchronousCall, value 5\ngas 50/50, out of gas"];
+  n0 -> n1 [label="1"];
+}
+`
+	require.Equal(t, expected, string(data))
+}
+
+func TestCallGraphTracker_TracesDeploys(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	tracker := executor.EnableCallGraph()
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	err = runner.RunSingleJSONScenario(filepath.Join(getTestRoot(), "adder/scenarios/adder.scen.json"))
+	require.Nil(t, err)
+
+	roots := tracker.Roots()
+	require.NotEmpty(t, roots)
+
+	deploy := roots[0]
+	require.Equal(t, "1", deploy.TxID)
+	require.Equal(t, "init", deploy.Function)
+	require.Equal(t, "DirectCall", deploy.CallType)
+	require.Equal(t, vmcommon.Ok.String(), deploy.ReturnCode)
+	require.Greater(t, deploy.GasUsed, uint64(0))
+
+	functions := make([]string, 0, len(roots))
+	for _, root := range roots {
+		functions = append(functions, root.Function)
+	}
+	require.Contains(t, functions, "add")
+}
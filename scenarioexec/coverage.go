@@ -0,0 +1,164 @@
+package scenarioexec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+)
+
+// ContractCoverage is the coverage report of a single contract code, identified by its code hash.
+// Endpoints counts the calls of the exported functions made by the VM, while Functions counts
+// the executions of all functions defined by the contract, including internal calls.
+// Uncovered lists the functions never executed, or the endpoints never called if the
+// functions of the contract are unknown.
+type ContractCoverage struct {
+	CodeHash  string            `json:"codeHash"`
+	Endpoints map[string]uint64 `json:"endpoints"`
+	Functions map[string]uint64 `json:"functions"`
+	Uncovered []string          `json:"uncovered"`
+}
+
+type functionEntry struct {
+	contract *ContractCoverage
+	name     string
+}
+
+// CoverageTracker counts the contract functions executed during scenario runs, per code hash.
+// The endpoints are traced by the VM; all other functions are counted by the probes at their
+// start, so only contracts instrumented by the executor report them, see VMTestExecutor.EnableCoverage.
+type CoverageTracker struct {
+	contracts      map[string]*ContractCoverage
+	functionProbes map[int32]*functionEntry
+}
+
+// NewCoverageTracker creates an empty CoverageTracker.
+func NewCoverageTracker() *CoverageTracker {
+	return &CoverageTracker{
+		contracts:      make(map[string]*ContractCoverage),
+		functionProbes: make(map[int32]*functionEntry),
+	}
+}
+
+func (ct *CoverageTracker) getContract(codeHash []byte) *ContractCoverage {
+	codeHashHex := hex.EncodeToString(codeHash)
+	contract, found := ct.contracts[codeHashHex]
+	if !found {
+		contract = &ContractCoverage{
+			CodeHash:  codeHashHex,
+			Endpoints: make(map[string]uint64),
+			Functions: make(map[string]uint64),
+		}
+		ct.contracts[codeHashHex] = contract
+	}
+	return contract
+}
+
+// ModuleInstrumented registers the functions of an instrumented contract, with no executions.
+func (ct *CoverageTracker) ModuleInstrumented(codeHash []byte, module *wasmcov.InstrumentedModule) {
+	contract := ct.getContract(codeHash)
+	for i, name := range module.FunctionNames {
+		if _, ok := contract.Functions[name]; !ok {
+			contract.Functions[name] = 0
+		}
+		ct.functionProbes[module.FunctionProbes[i]] = &functionEntry{
+			contract: contract,
+			name:     name,
+		}
+	}
+}
+
+// ProbeVisited counts an execution of the function starting with the given probe; other probes are ignored.
+func (ct *CoverageTracker) ProbeVisited(probeID int32) {
+	entry, found := ct.functionProbes[probeID]
+	if found {
+		entry.contract.Functions[entry.name]++
+	}
+}
+
+// TraceFunctionCall records a call to an exported function of the contract with the given code hash.
+func (ct *CoverageTracker) TraceFunctionCall(codeHash []byte, functionName string, exportedFunctions []string) {
+	contract := ct.getContract(codeHash)
+	for _, exported := range exportedFunctions {
+		if _, ok := contract.Endpoints[exported]; !ok {
+			contract.Endpoints[exported] = 0
+		}
+	}
+	contract.Endpoints[functionName]++
+}
+
+// Report returns the coverage of all contracts executed so far, sorted by code hash.
+func (ct *CoverageTracker) Report() []*ContractCoverage {
+	report := make([]*ContractCoverage, 0, len(ct.contracts))
+	for _, contract := range ct.contracts {
+		contract.Uncovered = make([]string, 0)
+		for name, calls := range contract.reportedFunctions() {
+			if calls == 0 {
+				contract.Uncovered = append(contract.Uncovered, name)
+			}
+		}
+		sort.Strings(contract.Uncovered)
+		report = append(report, contract)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].CodeHash < report[j].CodeHash
+	})
+
+	return report
+}
+
+// WriteReport saves the coverage report to the given path. Files ending in
+// ".info" or ".lcov" are written as lcov function records, all others as JSON.
+func (ct *CoverageTracker) WriteReport(path string) error {
+	var data []byte
+	var err error
+	if strings.HasSuffix(path, ".info") || strings.HasSuffix(path, ".lcov") {
+		data = []byte(ct.lcovReport())
+	} else {
+		data, err = json.MarshalIndent(ct.Report(), "", "  ")
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// reportedFunctions yields the executions of all functions, if known, otherwise the endpoint calls
+func (contract *ContractCoverage) reportedFunctions() map[string]uint64 {
+	if len(contract.Functions) > 0 {
+		return contract.Functions
+	}
+	return contract.Endpoints
+}
+
+func (ct *CoverageTracker) lcovReport() string {
+	var sb strings.Builder
+	for _, contract := range ct.Report() {
+		functions := contract.reportedFunctions()
+		names := make([]string, 0, len(functions))
+		for name := range functions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb.WriteString("TN:\n")
+		sb.WriteString(fmt.Sprintf("SF:%s\n", contract.CodeHash))
+		for i, name := range names {
+			sb.WriteString(fmt.Sprintf("FN:%d,%s\n", i+1, name))
+		}
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("FNDA:%d,%s\n", functions[name], name))
+		}
+		sb.WriteString(fmt.Sprintf("FNF:%d\n", len(names)))
+		sb.WriteString(fmt.Sprintf("FNH:%d\n", len(names)-len(contract.Uncovered)))
+		sb.WriteString("end_of_record\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,148 @@
+package scenarioexec
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+	"github.com/stretchr/testify/require"
+)
+
+var testCodeHash = []byte{0xab, 0xcd}
+
+func newTestCoverageTracker() *CoverageTracker {
+	tracker := NewCoverageTracker()
+	tracker.ModuleInstrumented(testCodeHash, &wasmcov.InstrumentedModule{
+		FunctionProbes: []int32{10, 20, 30},
+		FunctionNames:  []string{"add", "getSum", "func[5]"},
+	})
+
+	tracker.TraceFunctionCall(testCodeHash, "add", []string{"add", "getSum"})
+	tracker.ProbeVisited(10)
+	tracker.ProbeVisited(30)
+	tracker.ProbeVisited(31)
+	tracker.ProbeVisited(30)
+
+	return tracker
+}
+
+func TestCoverageTracker_FunctionHits(t *testing.T) {
+	tracker := newTestCoverageTracker()
+	tracker.TraceFunctionCall([]byte{0x01}, "init", []string{"init", "upgrade"})
+
+	report := tracker.Report()
+	require.Len(t, report, 2)
+
+	require.Equal(t, "01", report[0].CodeHash)
+	require.Equal(t, map[string]uint64{"init": 1, "upgrade": 0}, report[0].Endpoints)
+	require.Empty(t, report[0].Functions)
+	require.Equal(t, []string{"upgrade"}, report[0].Uncovered)
+
+	require.Equal(t, "abcd", report[1].CodeHash)
+	require.Equal(t, map[string]uint64{"add": 1, "getSum": 0}, report[1].Endpoints)
+	require.Equal(t, map[string]uint64{"add": 1, "getSum": 0, "func[5]": 2}, report[1].Functions)
+	require.Equal(t, []string{"getSum"}, report[1].Uncovered)
+}
+
+func TestCoverageTracker_WriteReport(t *testing.T) {
+	tracker := newTestCoverageTracker()
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "coverage.json")
+	require.Nil(t, tracker.WriteReport(jsonPath))
+	data, err := os.ReadFile(jsonPath)
+	require.Nil(t, err)
+	var report []*ContractCoverage
+	require.Nil(t, json.Unmarshal(data, &report))
+	require.Equal(t, tracker.Report(), report)
+
+	lcovPath := filepath.Join(dir, "coverage.info")
+	require.Nil(t, tracker.WriteReport(lcovPath))
+	data, err = os.ReadFile(lcovPath)
+	require.Nil(t, err)
+	expected := strings.Join([]string{
+		"TN:",
+		"SF:abcd",
+		"FN:1,add",
+		"FN:2,func[5]",
+		"FN:3,getSum",
+		"FNDA:1,add",
+		"FNDA:2,func[5]",
+		"FNDA:0,getSum",
+		"FNF:3",
+		"FNH:2",
+		"end_of_record",
+		"",
+	}, "\n")
+	require.Equal(t, expected, string(data))
+}
+
+func TestCoverageTracker_InternalFunctions(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	tracker, err := executor.EnableCoverage()
+	require.Nil(t, err)
+	defer executor.RemoveProbeHandlers()
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	err = runner.RunSingleJSONScenario(filepath.Join(getTestRoot(), "adder/scenarios/adder.scen.json"))
+	require.Nil(t, err)
+
+	report := tracker.Report()
+	require.Len(t, report, 1)
+	adder := report[0]
+	require.Equal(t, uint64(1), adder.Endpoints["add"])
+	require.Equal(t, uint64(1), adder.Functions["add"])
+	require.Equal(t, uint64(1), adder.Functions["init"])
+
+	internalHits := uint64(0)
+	for name, hits := range adder.Functions {
+		_, isEndpoint := adder.Endpoints[name]
+		if !isEndpoint {
+			internalHits += hits
+		}
+	}
+	require.Greater(t, len(adder.Functions), len(adder.Endpoints))
+	require.Greater(t, internalHits, uint64(0))
+}
+
+func TestCoverageTracker_ProbesKeepGasAndExecutorsApart(t *testing.T) {
+	scenarioPath := filepath.Join(getTestRoot(), "adder/scenarios/adder.scen.json")
+	runAdder := func(executor *VMTestExecutor) []uint64 {
+		gasRemaining := make([]uint64, 0)
+		executor.SetTxOutputObserver(func(_ *mj.TxStep, output *vmi.VMOutput) {
+			gasRemaining = append(gasRemaining, output.GasRemaining)
+		})
+		runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+		require.Nil(t, runner.RunSingleJSONScenario(scenarioPath))
+		return gasRemaining
+	}
+
+	covered, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	tracker, err := covered.EnableCoverage()
+	require.Nil(t, err)
+	defer covered.RemoveProbeHandlers()
+
+	// created last, so that any import set replacing the one of the covered executor is noticed
+	plain, err := NewVMTestExecutor()
+	require.Nil(t, err)
+
+	require.Equal(t, runAdder(plain), runAdder(covered))
+	require.Len(t, tracker.Report(), 1)
+	require.Greater(t, tracker.Report()[0].Functions["add"], uint64(0))
+}
+
+func getTestRoot() string {
+	exePath, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return filepath.Join(exePath, "../test")
+}
@@ -0,0 +1,170 @@
+package differential
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	am "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// Configuration describes one of the two VMs being compared.
+type Configuration struct {
+	Name                string
+	EnableEpochsHandler vmhost.EnableEpochsHandler
+
+	// GasSchedule replaces the gas schedule declared by each scenario, if OverrideGasSchedule is set.
+	GasSchedule         mj.GasSchedule
+	OverrideGasSchedule bool
+}
+
+// TxOutput is the output of a single transaction of a scenario.
+type TxOutput struct {
+	TxIdent string
+	Output  *vmi.VMOutput
+}
+
+// Difference is a single field that differs between the two configurations.
+type Difference struct {
+	Scenario string
+	TxIdent  string
+	Field    string
+	Left     string
+	Right    string
+}
+
+// String formats the difference on a single line.
+func (d *Difference) String() string {
+	return fmt.Sprintf("%s tx %s: %s: %s != %s", d.Scenario, d.TxIdent, d.Field, d.Left, d.Right)
+}
+
+// Harness runs scenarios against two VM configurations and compares the transaction outputs.
+// Expectations in the scenarios are not checked, so that a changed output does not stop the run.
+type Harness struct {
+	Left  *Configuration
+	Right *Configuration
+}
+
+// NewHarness creates a new Harness instance.
+func NewHarness(left *Configuration, right *Configuration) (*Harness, error) {
+	if left == nil || right == nil {
+		return nil, ErrNilConfiguration
+	}
+	if left.EnableEpochsHandler == nil || right.EnableEpochsHandler == nil {
+		return nil, vmhost.ErrNilEnableEpochsHandler
+	}
+
+	return &Harness{
+		Left:  left,
+		Right: right,
+	}, nil
+}
+
+// CompareDirectory compares all .scen.json scenarios in the directory, recursively.
+func (h *Harness) CompareDirectory(dirPath string) ([]*Difference, error) {
+	var scenarioPaths []string
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(path, ".scen.json") {
+			scenarioPaths = append(scenarioPaths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(scenarioPaths)
+
+	differences := make([]*Difference, 0)
+	for _, scenarioPath := range scenarioPaths {
+		differences = append(differences, h.CompareScenario(scenarioPath)...)
+	}
+
+	return differences, nil
+}
+
+// CompareScenario runs a single scenario with both configurations and compares the outputs of
+// its transactions, in execution order. Failures to run the scenario are reported as differences
+// of the "error" field when they only occur with one of the configurations.
+func (h *Harness) CompareScenario(scenarioPath string) []*Difference {
+	leftOutputs, leftErr := runScenario(h.Left, scenarioPath)
+	rightOutputs, rightErr := runScenario(h.Right, scenarioPath)
+
+	var differences []*Difference
+	addDifference := func(txIdent string, field string, left string, right string) {
+		differences = append(differences, &Difference{
+			Scenario: scenarioPath,
+			TxIdent:  txIdent,
+			Field:    field,
+			Left:     left,
+			Right:    right,
+		})
+	}
+
+	if errorString(leftErr) != errorString(rightErr) {
+		addDifference("", "error", errorString(leftErr), errorString(rightErr))
+	}
+	if len(leftOutputs) != len(rightOutputs) {
+		addDifference("", "numTransactions", fmt.Sprintf("%d", len(leftOutputs)), fmt.Sprintf("%d", len(rightOutputs)))
+	}
+
+	numOutputs := len(leftOutputs)
+	if len(rightOutputs) < numOutputs {
+		numOutputs = len(rightOutputs)
+	}
+	for i := 0; i < numOutputs; i++ {
+		txIdent := leftOutputs[i].TxIdent
+		if leftOutputs[i].TxIdent != rightOutputs[i].TxIdent {
+			addDifference(txIdent, "txId", leftOutputs[i].TxIdent, rightOutputs[i].TxIdent)
+			continue
+		}
+
+		for _, fieldDifference := range CompareVMOutputs(leftOutputs[i].Output, rightOutputs[i].Output) {
+			addDifference(txIdent, fieldDifference.Field, fieldDifference.Left, fieldDifference.Right)
+		}
+	}
+
+	return differences
+}
+
+func runScenario(config *Configuration, scenarioPath string) ([]*TxOutput, error) {
+	executor, err := am.NewVMTestExecutorWithEnableEpochsHandler(config.EnableEpochsHandler)
+	if err != nil {
+		return nil, err
+	}
+	if config.OverrideGasSchedule {
+		err = executor.SetScenariosGasSchedule(config.GasSchedule)
+		if err != nil {
+			return nil, err
+		}
+	}
+	executor.EnableExpectationGeneration(false)
+
+	var outputs []*TxOutput
+	executor.SetTxOutputObserver(func(step *mj.TxStep, output *vmi.VMOutput) {
+		outputs = append(outputs, &TxOutput{
+			TxIdent: step.TxIdent,
+			Output:  output,
+		})
+	})
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	err = runner.RunSingleJSONScenario(scenarioPath)
+
+	return outputs, err
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
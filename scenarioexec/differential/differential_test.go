@@ -0,0 +1,113 @@
+package differential
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/stretchr/testify/require"
+)
+
+func getTestRoot() string {
+	exePath, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	return filepath.Join(exePath, "../../test")
+}
+
+func allFlagsEnabled() *mock.EnableEpochsHandlerStub {
+	return &mock.EnableEpochsHandlerStub{
+		IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
+			return true
+		},
+	}
+}
+
+func TestHarness_NilConfiguration(t *testing.T) {
+	harness, err := NewHarness(nil, &Configuration{})
+	require.Nil(t, harness)
+	require.Equal(t, ErrNilConfiguration, err)
+}
+
+func TestHarness_SameConfigurationNoDifferences(t *testing.T) {
+	harness, err := NewHarness(
+		&Configuration{Name: "left", EnableEpochsHandler: allFlagsEnabled()},
+		&Configuration{Name: "right", EnableEpochsHandler: allFlagsEnabled()},
+	)
+	require.Nil(t, err)
+
+	differences, err := harness.CompareDirectory(filepath.Join(getTestRoot(), "adder/scenarios"))
+	require.Nil(t, err)
+	require.Empty(t, differences)
+}
+
+func TestHarness_GasScheduleDifferences(t *testing.T) {
+	harness, err := NewHarness(
+		&Configuration{
+			Name:                "v3",
+			EnableEpochsHandler: allFlagsEnabled(),
+			GasSchedule:         mj.GasScheduleV3,
+			OverrideGasSchedule: true,
+		},
+		&Configuration{
+			Name:                "dummy",
+			EnableEpochsHandler: allFlagsEnabled(),
+			GasSchedule:         mj.GasScheduleDummy,
+			OverrideGasSchedule: true,
+		},
+	)
+	require.Nil(t, err)
+
+	differences := harness.CompareScenario(filepath.Join(getTestRoot(), "adder/scenarios/adder.scen.json"))
+	require.NotEmpty(t, differences)
+	for _, difference := range differences {
+		isGasField := difference.Field == "gasRemaining" || strings.HasSuffix(difference.Field, ".gasUsed")
+		require.True(t, isGasField, difference.String())
+	}
+}
+
+func TestCompareVMOutputs(t *testing.T) {
+	newOutput := func() *vmi.VMOutput {
+		return &vmi.VMOutput{
+			ReturnData: [][]byte{{1}},
+			ReturnCode: vmi.Ok,
+			GasRefund:  big.NewInt(0),
+			OutputAccounts: map[string]*vmi.OutputAccount{
+				"sc": {
+					Address:      []byte("sc"),
+					BalanceDelta: big.NewInt(5),
+					StorageUpdates: map[string]*vmi.StorageUpdate{
+						"key": {Offset: []byte("key"), Data: []byte{2}, Written: true},
+					},
+					OutputTransfers: []vmi.OutputTransfer{{Value: big.NewInt(3)}},
+				},
+			},
+			Logs: []*vmi.LogEntry{{Identifier: []byte("event"), Topics: [][]byte{{4}}}},
+		}
+	}
+
+	require.Empty(t, CompareVMOutputs(newOutput(), newOutput()))
+
+	right := newOutput()
+	right.ReturnData[0] = []byte{9}
+	right.OutputAccounts["sc"].StorageUpdates["key"].Data = []byte{8}
+	right.OutputAccounts["sc"].OutputTransfers[0].Value = big.NewInt(7)
+	right.OutputAccounts["other"] = &vmi.OutputAccount{}
+	right.Logs[0].Topics = nil
+
+	differences := CompareVMOutputs(newOutput(), right)
+	require.Equal(t, []*FieldDifference{
+		{Field: "returnData[0]", Left: "01", Right: "09"},
+		{Field: "outputAccounts[6f74686572]", Left: "missing", Right: "present"},
+		{Field: "outputAccounts[7363].storageUpdates[6b6579].data", Left: "02", Right: "08"},
+		{Field: "outputAccounts[7363].outputTransfers[0].value", Left: "3", Right: "7"},
+		{Field: "logs[0].topics.length", Left: "1", Right: "0"},
+	}, differences)
+}
@@ -0,0 +1,6 @@
+package differential
+
+import "errors"
+
+// ErrNilConfiguration signals that one of the compared configurations is missing
+var ErrNilConfiguration = errors.New("nil configuration")
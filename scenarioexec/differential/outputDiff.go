@@ -0,0 +1,196 @@
+package differential
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// FieldDifference is a field that differs between two VMOutputs.
+// Byte slices are rendered as hex, numbers in decimal.
+type FieldDifference struct {
+	Field string
+	Left  string
+	Right string
+}
+
+type outputComparer struct {
+	differences []*FieldDifference
+}
+
+// CompareVMOutputs compares two VMOutputs field by field: return code, message and data,
+// gas, output accounts (balances, nonces, code, storage updates and transfers),
+// deleted and touched accounts and logs.
+func CompareVMOutputs(left *vmi.VMOutput, right *vmi.VMOutput) []*FieldDifference {
+	oc := &outputComparer{}
+
+	oc.compareString("returnCode", left.ReturnCode.String(), right.ReturnCode.String())
+	oc.compareString("returnMessage", left.ReturnMessage, right.ReturnMessage)
+	oc.compareBytesList("returnData", left.ReturnData, right.ReturnData)
+	oc.compareUint64("gasRemaining", left.GasRemaining, right.GasRemaining)
+	oc.compareBigInt("gasRefund", left.GasRefund, right.GasRefund)
+	oc.compareOutputAccounts(left.OutputAccounts, right.OutputAccounts)
+	oc.compareBytesList("deletedAccounts", left.DeletedAccounts, right.DeletedAccounts)
+	oc.compareBytesList("touchedAccounts", left.TouchedAccounts, right.TouchedAccounts)
+	oc.compareLogs(left.Logs, right.Logs)
+
+	return oc.differences
+}
+
+func (oc *outputComparer) add(field string, left string, right string) {
+	oc.differences = append(oc.differences, &FieldDifference{
+		Field: field,
+		Left:  left,
+		Right: right,
+	})
+}
+
+func (oc *outputComparer) compareString(field string, left string, right string) {
+	if left != right {
+		oc.add(field, left, right)
+	}
+}
+
+func (oc *outputComparer) compareUint64(field string, left uint64, right uint64) {
+	if left != right {
+		oc.add(field, fmt.Sprintf("%d", left), fmt.Sprintf("%d", right))
+	}
+}
+
+func (oc *outputComparer) compareBigInt(field string, left *big.Int, right *big.Int) {
+	if bigIntToString(left) != bigIntToString(right) {
+		oc.add(field, bigIntToString(left), bigIntToString(right))
+	}
+}
+
+func (oc *outputComparer) compareBytes(field string, left []byte, right []byte) {
+	if !bytes.Equal(left, right) {
+		oc.add(field, hex.EncodeToString(left), hex.EncodeToString(right))
+	}
+}
+
+func (oc *outputComparer) compareBytesList(field string, left [][]byte, right [][]byte) {
+	if len(left) != len(right) {
+		oc.add(field+".length", fmt.Sprintf("%d", len(left)), fmt.Sprintf("%d", len(right)))
+		return
+	}
+	for i := range left {
+		oc.compareBytes(fmt.Sprintf("%s[%d]", field, i), left[i], right[i])
+	}
+}
+
+func (oc *outputComparer) compareOutputAccounts(left map[string]*vmi.OutputAccount, right map[string]*vmi.OutputAccount) {
+	for _, address := range outputAccountAddresses(left, right) {
+		field := fmt.Sprintf("outputAccounts[%s]", hex.EncodeToString([]byte(address)))
+		leftAccount, leftFound := left[address]
+		rightAccount, rightFound := right[address]
+		if !leftFound || !rightFound {
+			oc.add(field, presence(leftFound), presence(rightFound))
+			continue
+		}
+
+		oc.compareUint64(field+".nonce", leftAccount.Nonce, rightAccount.Nonce)
+		oc.compareBigInt(field+".balance", leftAccount.Balance, rightAccount.Balance)
+		oc.compareBigInt(field+".balanceDelta", leftAccount.BalanceDelta, rightAccount.BalanceDelta)
+		oc.compareBytes(field+".code", leftAccount.Code, rightAccount.Code)
+		oc.compareBytes(field+".codeMetadata", leftAccount.CodeMetadata, rightAccount.CodeMetadata)
+		oc.compareBytes(field+".codeDeployerAddress", leftAccount.CodeDeployerAddress, rightAccount.CodeDeployerAddress)
+		oc.compareUint64(field+".gasUsed", leftAccount.GasUsed, rightAccount.GasUsed)
+		oc.compareStorageUpdates(field+".storageUpdates", leftAccount.StorageUpdates, rightAccount.StorageUpdates)
+		oc.compareOutputTransfers(field+".outputTransfers", leftAccount.OutputTransfers, rightAccount.OutputTransfers)
+	}
+}
+
+func (oc *outputComparer) compareStorageUpdates(field string, left map[string]*vmi.StorageUpdate, right map[string]*vmi.StorageUpdate) {
+	for _, key := range storageUpdateKeys(left, right) {
+		keyField := fmt.Sprintf("%s[%s]", field, hex.EncodeToString([]byte(key)))
+		leftUpdate, leftFound := left[key]
+		rightUpdate, rightFound := right[key]
+		if !leftFound || !rightFound {
+			oc.add(keyField, presence(leftFound), presence(rightFound))
+			continue
+		}
+
+		oc.compareBytes(keyField+".data", leftUpdate.Data, rightUpdate.Data)
+		oc.compareString(keyField+".written", fmt.Sprintf("%t", leftUpdate.Written), fmt.Sprintf("%t", rightUpdate.Written))
+	}
+}
+
+func (oc *outputComparer) compareOutputTransfers(field string, left []vmi.OutputTransfer, right []vmi.OutputTransfer) {
+	if len(left) != len(right) {
+		oc.add(field+".length", fmt.Sprintf("%d", len(left)), fmt.Sprintf("%d", len(right)))
+		return
+	}
+	for i := range left {
+		transferField := fmt.Sprintf("%s[%d]", field, i)
+		oc.compareBigInt(transferField+".value", left[i].Value, right[i].Value)
+		oc.compareBytes(transferField+".data", left[i].Data, right[i].Data)
+		oc.compareUint64(transferField+".gasLimit", left[i].GasLimit, right[i].GasLimit)
+		oc.compareUint64(transferField+".gasLocked", left[i].GasLocked, right[i].GasLocked)
+		oc.compareString(transferField+".callType", left[i].CallType.ToString(), right[i].CallType.ToString())
+		oc.compareBytes(transferField+".senderAddress", left[i].SenderAddress, right[i].SenderAddress)
+	}
+}
+
+func (oc *outputComparer) compareLogs(left []*vmi.LogEntry, right []*vmi.LogEntry) {
+	if len(left) != len(right) {
+		oc.add("logs.length", fmt.Sprintf("%d", len(left)), fmt.Sprintf("%d", len(right)))
+		return
+	}
+	for i := range left {
+		logField := fmt.Sprintf("logs[%d]", i)
+		oc.compareBytes(logField+".address", left[i].Address, right[i].Address)
+		oc.compareBytes(logField+".identifier", left[i].Identifier, right[i].Identifier)
+		oc.compareBytesList(logField+".topics", left[i].Topics, right[i].Topics)
+		oc.compareBytesList(logField+".data", left[i].Data, right[i].Data)
+	}
+}
+
+func outputAccountAddresses(left map[string]*vmi.OutputAccount, right map[string]*vmi.OutputAccount) []string {
+	addresses := make(map[string]struct{})
+	for address := range left {
+		addresses[address] = struct{}{}
+	}
+	for address := range right {
+		addresses[address] = struct{}{}
+	}
+	return sortedKeys(addresses)
+}
+
+func storageUpdateKeys(left map[string]*vmi.StorageUpdate, right map[string]*vmi.StorageUpdate) []string {
+	keys := make(map[string]struct{})
+	for key := range left {
+		keys[key] = struct{}{}
+	}
+	for key := range right {
+		keys[key] = struct{}{}
+	}
+	return sortedKeys(keys)
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func bigIntToString(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+	return value.String()
+}
+
+func presence(found bool) string {
+	if found {
+		return "present"
+	}
+	return "missing"
+}
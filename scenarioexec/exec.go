@@ -1,7 +1,9 @@
 package scenarioexec
 
 import (
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -10,6 +12,7 @@ import (
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
 	worldhook "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
 	gasSchedules "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec/gasSchedules"
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
 	er "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/reconstructor"
 	fr "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/fileresolver"
@@ -23,21 +26,73 @@ var log = logger.GetOrCreate("vm/scenarios")
 // TestVMType is the VM type argument we use in tests.
 var TestVMType = []byte{0, 0}
 
+// ErrFlagsSetByHandler signals that the flags of an executor cannot be changed, because they are
+// given by the enable epochs handler it was created with.
+var ErrFlagsSetByHandler = errors.New("the flags of the executor are set by its enable epochs handler")
+
+// defaultFlags are the flags enabled by NewVMTestExecutor. The flags introduced after them are
+// enabled by the scenarios which need them, or by the test runner, see EnableFlags.
+var defaultFlags = []core.EnableEpochFlag{
+	hostCore.SCDeployFlag,
+	hostCore.AheadOfTimeGasUsageFlag,
+	hostCore.RepairCallbackFlag,
+	hostCore.BuiltInFunctionsFlag,
+}
+
 // VMTestExecutor parses, interprets and executes both .test.json tests and .scen.json scenarios with VM.
 type VMTestExecutor struct {
 	World                 *worldhook.MockWorld
-	vm                    vmi.VMExecutionHandler
+	vm                    vmhost.VMHost
 	checkGas              bool
 	scenGasScheduleLoaded bool
 	fileResolver          fr.FileResolver
 	exprReconstructor     er.ExprReconstructor
+	generateExpectations  bool
+	saveExternalSteps     bool
+	txOutputObserver      TxOutputObserver
+	callGraph             *CallGraphTracker
+	storageDiffWriter     io.Writer
+	eventDecoder          *scenabi.EventDecoder
+	contractABI           *scenabi.ContractABI
+	enabledFlags          map[core.EnableEpochFlag]bool
+	scenarioFlags         map[core.EnableEpochFlag]bool
+
+	probeHandlers            probeHandlers
+	instanceWrapper          InstanceWrapper
+	instanceBuilderInstalled bool
 }
 
 var _ mc.TestExecutor = (*VMTestExecutor)(nil)
 var _ mc.ScenarioExecutor = (*VMTestExecutor)(nil)
 
-// NewVMTestExecutor prepares a new VMTestExecutor instance.
+// TxOutputObserver is notified of the output of every transaction executed by a VMTestExecutor.
+type TxOutputObserver func(step *mj.TxStep, output *vmi.VMOutput)
+
+// NewVMTestExecutor prepares a new VMTestExecutor instance, whose VM has the default flags enabled.
 func NewVMTestExecutor() (*VMTestExecutor, error) {
+	enabledFlags := make(map[core.EnableEpochFlag]bool)
+	for _, flag := range defaultFlags {
+		enabledFlags[flag] = true
+	}
+	scenarioFlags := make(map[core.EnableEpochFlag]bool)
+
+	executor, err := NewVMTestExecutorWithEnableEpochsHandler(&mock.EnableEpochsHandlerStub{
+		IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
+			return enabledFlags[flag] || scenarioFlags[flag]
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	executor.enabledFlags = enabledFlags
+	executor.scenarioFlags = scenarioFlags
+	return executor, nil
+}
+
+// NewVMTestExecutorWithEnableEpochsHandler prepares a new VMTestExecutor instance,
+// whose VM only has the flags enabled by the given handler.
+func NewVMTestExecutorWithEnableEpochsHandler(enableEpochsHandler vmhost.EnableEpochsHandler) (*VMTestExecutor, error) {
 	world := worldhook.NewMockWorld()
 
 	gasScheduleMap := config.MakeGasMapForTests()
@@ -53,11 +108,7 @@ func NewVMTestExecutor() (*VMTestExecutor, error) {
 		GasSchedule:          gasScheduleMap,
 		BuiltInFuncContainer: world.BuiltinFuncs.Container,
 		ProtectedKeyPrefix:   []byte(core.ProtectedKeyPrefix),
-		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-			},
-		},
+		EnableEpochsHandler:  enableEpochsHandler,
 	})
 	if err != nil {
 		return nil, err
@@ -70,14 +121,97 @@ func NewVMTestExecutor() (*VMTestExecutor, error) {
 		scenGasScheduleLoaded: false,
 		fileResolver:          nil,
 		exprReconstructor:     er.ExprReconstructor{},
+		eventDecoder:          scenabi.NewEventDecoder(),
+		contractABI:           nil,
 	}, nil
 }
 
+// EnableFlags enables the given flags in the VM, on top of the ones already enabled, for all
+// subsequent steps and scenarios. Only the executors created by NewVMTestExecutor support it.
+func (ae *VMTestExecutor) EnableFlags(flags []core.EnableEpochFlag) error {
+	return ae.addFlags(ae.enabledFlags, flags)
+}
+
+func (ae *VMTestExecutor) addFlags(target map[core.EnableEpochFlag]bool, flags []core.EnableEpochFlag) error {
+	if target == nil {
+		return ErrFlagsSetByHandler
+	}
+	for _, flag := range flags {
+		if !hostCore.IsKnownFlag(flag) {
+			return fmt.Errorf("unknown enable epoch flag: %s", flag)
+		}
+	}
+
+	for _, flag := range flags {
+		target[flag] = true
+	}
+	return nil
+}
+
 // GetVM yields a reference to the VMExecutionHandler used.
 func (ae *VMTestExecutor) GetVM() vmi.VMExecutionHandler {
 	return ae.vm
 }
 
+// EnableCoverage starts counting the contract functions executed by all subsequent steps,
+// both the endpoints and the internal functions. The contracts are instrumented with probes,
+// see AddProbeHandler.
+func (ae *VMTestExecutor) EnableCoverage() (*CoverageTracker, error) {
+	tracker := NewCoverageTracker()
+	err := ae.AddProbeHandler(tracker)
+	if err != nil {
+		return nil, err
+	}
+	ae.vm.Runtime().SetFunctionCallTracer(tracker)
+	return tracker, nil
+}
+
+// EnableCallGraph starts recording the tree of contract calls executed by all subsequent steps.
+func (ae *VMTestExecutor) EnableCallGraph() *CallGraphTracker {
+	ae.callGraph = NewCallGraphTracker()
+	ae.vm.SetCallTracer(ae.callGraph)
+	return ae.callGraph
+}
+
+// EnableStorageDiffs starts printing the storage changes made by each subsequent successful
+// transaction to the given writer.
+func (ae *VMTestExecutor) EnableStorageDiffs(writer io.Writer) {
+	ae.storageDiffWriter = writer
+}
+
+// LoadABI registers the endpoints, types and events of a contract ABI file,
+// so that expected logs can be checked by their decoded fields,
+// and results are shown by their ABI types when checks fail.
+// The ABI also needs to be set on the scenario parser, for "abi:" expressions, see ContractABI.
+func (ae *VMTestExecutor) LoadABI(abiPath string) error {
+	abi, err := scenabi.LoadABI(abiPath)
+	if err != nil {
+		return err
+	}
+	ae.eventDecoder.RegisterABI(abi)
+	if ae.contractABI == nil {
+		ae.contractABI = scenabi.NewContractABI()
+	}
+	ae.contractABI.Merge(abi)
+	ae.exprReconstructor.ABI = ae.contractABI
+	return nil
+}
+
+// ContractABI yields all loaded ABIs merged together, or nil if none was loaded.
+func (ae *VMTestExecutor) ContractABI() *scenabi.ContractABI {
+	return ae.contractABI
+}
+
+// EventDecoder yields the decoder holding the events of all loaded ABIs.
+func (ae *VMTestExecutor) EventDecoder() *scenabi.EventDecoder {
+	return ae.eventDecoder
+}
+
+// SetTxOutputObserver registers a function called with the output of every subsequent transaction.
+func (ae *VMTestExecutor) SetTxOutputObserver(observer TxOutputObserver) {
+	ae.txOutputObserver = observer
+}
+
 func (ae *VMTestExecutor) gasScheduleMapFromScenarios(scenGasSchedule mj.GasSchedule) (config.GasScheduleMap, error) {
 	switch scenGasSchedule {
 	case mj.GasScheduleDefault:
@@ -1,6 +1,9 @@
 package scenarioexec
 
 import (
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
 	vmi "github.com/kalyan3104/k-chain-vm-common-go"
 	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
 	fr "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/fileresolver"
@@ -11,6 +14,9 @@ import (
 // Is called in RunAllJSONScenariosInDirectory, but not in RunSingleJSONScenario.
 func (ae *VMTestExecutor) Reset() {
 	ae.World.Clear()
+	for flag := range ae.scenarioFlags {
+		delete(ae.scenarioFlags, flag)
+	}
 }
 
 // ExecuteScenario executes an individual test.
@@ -21,6 +27,10 @@ func (ae *VMTestExecutor) ExecuteScenario(scenario *mj.Scenario, fileResolver fr
 	if err != nil {
 		return err
 	}
+	err = ae.enableScenarioFlags(scenario.EnableEpochFlags)
+	if err != nil {
+		return err
+	}
 
 	txIndex := 0
 	for _, generalStep := range scenario.Steps {
@@ -65,6 +75,8 @@ func (ae *VMTestExecutor) ExecuteExternalStep(step *mj.ExternalStepsStep) error
 	fileResolverBackup := ae.fileResolver
 	clonedFileResolver := ae.fileResolver.Clone()
 	externalStepsRunner := mc.NewScenarioRunner(ae, clonedFileResolver)
+	externalStepsRunner.RewriteScenarios = ae.saveExternalSteps
+	externalStepsRunner.Parser.ExprInterpreter.ABI = ae.contractABI
 
 	extAbsPth := ae.fileResolver.ResolveAbsolutePath(step.Path)
 	err := externalStepsRunner.RunSingleJSONScenario(extAbsPth)
@@ -124,10 +136,20 @@ func (ae *VMTestExecutor) ExecuteTxStep(step *mj.TxStep) (*vmi.VMOutput, error)
 	if err != nil {
 		return nil, err
 	}
+	if ae.txOutputObserver != nil {
+		ae.txOutputObserver(step, output)
+	}
+
+	if ae.generateExpectations {
+		if step.Tx.Type.IsSmartContractTx() || step.ExpectedResult != nil {
+			step.ExpectedResult = ae.generateTxExpectation(step.ExpectedResult, output)
+		}
+		return output, nil
+	}
 
 	// check results
 	if step.ExpectedResult != nil {
-		err = ae.checkTxResults(step.TxIdent, step.ExpectedResult, ae.checkGas, output)
+		err = ae.checkTxResults(step.TxIdent, step.Tx.Function, step.ExpectedResult, ae.checkGas, output)
 		if err != nil {
 			return nil, err
 		}
@@ -135,3 +157,21 @@ func (ae *VMTestExecutor) ExecuteTxStep(step *mj.TxStep) (*vmi.VMOutput, error)
 
 	return output, nil
 }
+
+// enableScenarioFlags enables the flags required by the scenario, if any, until the next Reset
+func (ae *VMTestExecutor) enableScenarioFlags(flagNames []string) error {
+	if len(flagNames) == 0 {
+		return nil
+	}
+
+	flags := make([]core.EnableEpochFlag, 0, len(flagNames))
+	for _, flagName := range flagNames {
+		flags = append(flags, core.EnableEpochFlag(flagName))
+	}
+
+	err := ae.addFlags(ae.scenarioFlags, flags)
+	if err != nil {
+		return fmt.Errorf("bad scenario enableEpochFlags: %w", err)
+	}
+	return nil
+}
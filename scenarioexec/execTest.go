@@ -34,7 +34,7 @@ func (ae *VMTestExecutor) ExecuteTest(test *mj.Test) error {
 			blResult := block.Results[txIndex]
 
 			// check results
-			err = ae.checkTxResults(txName, blResult, test.CheckGas, output)
+			err = ae.checkTxResults(txName, tx.Function, blResult, test.CheckGas, output)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,59 @@
+package scenarioexec
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVMTestExecutor_DefaultFlags(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+
+	require.True(t, executor.vm.IsDCDTFunctionsEnabled())
+	require.False(t, executor.vm.IsStrictReadOnlyEnabled())
+	require.False(t, executor.vm.IsImportEnabled("dcdtLocalMint"))
+
+	err = executor.EnableFlags([]core.EnableEpochFlag{"UnknownFlag"})
+	require.NotNil(t, err)
+	require.False(t, executor.vm.IsStrictReadOnlyEnabled())
+
+	err = executor.EnableFlags([]core.EnableEpochFlag{hostCore.StrictReadOnlyFlag})
+	require.Nil(t, err)
+	require.True(t, executor.vm.IsStrictReadOnlyEnabled())
+}
+
+func TestVMTestExecutor_ScenarioFlags(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+
+	err = executor.ExecuteScenario(&mj.Scenario{
+		GasSchedule:      mj.GasScheduleDefault,
+		EnableEpochFlags: []string{string(hostCore.DCDTManagementFlag)},
+	}, nil)
+	require.Nil(t, err)
+	require.True(t, executor.vm.IsImportEnabled("dcdtLocalMint"))
+
+	executor.Reset()
+	require.False(t, executor.vm.IsImportEnabled("dcdtLocalMint"))
+
+	err = executor.ExecuteScenario(&mj.Scenario{
+		GasSchedule:      mj.GasScheduleDefault,
+		EnableEpochFlags: []string{"UnknownFlag"},
+	}, nil)
+	require.NotNil(t, err)
+}
+
+func TestVMTestExecutor_FlagsSetByHandler(t *testing.T) {
+	executor, err := NewVMTestExecutorWithEnableEpochsHandler(&mock.EnableEpochsHandlerStub{
+		IsFlagEnabledCalled: hostCore.IsKnownFlag,
+	})
+	require.Nil(t, err)
+
+	err = executor.EnableFlags([]core.EnableEpochFlag{hostCore.StrictReadOnlyFlag})
+	require.Equal(t, ErrFlagsSetByHandler, err)
+}
@@ -82,6 +82,11 @@ const (
     GetNumReturnData     = 100
     GetReturnDataSize    = 100
 
+[AsyncContextCost]
+    CreateAsyncCall         = 200000
+    SetAsyncContextCallback = 100000
+    MaxAsyncCallsPerContext = 100
+
 [EthAPICost]
     UseGas              = 100
     GetAddress          = 100
@@ -696,6 +701,11 @@ const (
     GetNumReturnData     = 100
     GetReturnDataSize    = 100
 
+[AsyncContextCost]
+    CreateAsyncCall         = 200000
+    SetAsyncContextCallback = 100000
+    MaxAsyncCallsPerContext = 100
+
 [EthAPICost]
     UseGas              = 100
     GetAddress          = 100000
@@ -1312,6 +1322,11 @@ const (
     GetNumReturnData     = 100
     GetReturnDataSize    = 100
 
+[AsyncContextCost]
+    CreateAsyncCall         = 200000
+    SetAsyncContextCallback = 100000
+    MaxAsyncCallsPerContext = 100
+
 [EthAPICost]
     UseGas              = 100
     GetAddress          = 100000
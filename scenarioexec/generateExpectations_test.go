@@ -0,0 +1,119 @@
+package scenarioexec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mc "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/controller"
+	fr "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/fileresolver"
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	mjparse "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/parse"
+	"github.com/stretchr/testify/require"
+)
+
+const blankAdderScenario = `{
+    "name": "adder",
+    "steps": [
+        {
+            "step": "setState",
+            "accounts": {
+                "address:owner": {
+                    "nonce": "5",
+                    "balance": "0",
+                    "storage": {},
+                    "code": ""
+                }
+            },
+            "newAddresses": [
+                {
+                    "creatorAddress": "address:owner",
+                    "creatorNonce": "5",
+                    "newAddress": "sc:adder"
+                }
+            ]
+        },
+        {
+            "step": "scDeploy",
+            "txId": "1",
+            "tx": {
+                "from": "address:owner",
+                "value": "0",
+                "contractCode": "file:adder.wasm",
+                "arguments": ["5"],
+                "gasLimit": "5,000,000",
+                "gasPrice": "0"
+            }
+        },
+        {
+            "step": "scCall",
+            "txId": "2",
+            "tx": {
+                "from": "address:owner",
+                "to": "sc:adder",
+                "value": "0",
+                "function": "add",
+                "arguments": ["7"],
+                "gasLimit": "5,000,000",
+                "gasPrice": "0"
+            }
+        },
+        {
+            "step": "scQuery",
+            "txId": "3",
+            "tx": {
+                "to": "sc:adder",
+                "function": "getSum",
+                "arguments": []
+            }
+        },
+        {
+            "step": "checkState",
+            "accounts": {}
+        }
+    ]
+}
+`
+
+func TestGenerateExpectations_RewriteScenario(t *testing.T) {
+	adderPath := filepath.Join(getTestRoot(), "adder/output/adder.wasm")
+	newFileResolver := func() fr.FileResolver {
+		return fr.NewDefaultFileResolver().ReplacePath("adder.wasm", adderPath)
+	}
+	scenarioPath := filepath.Join(t.TempDir(), "adder.scen.json")
+	err := os.WriteFile(scenarioPath, []byte(blankAdderScenario), 0644)
+	require.Nil(t, err)
+
+	checkingExecutor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	err = mc.NewScenarioRunner(checkingExecutor, newFileResolver()).RunSingleJSONScenario(scenarioPath)
+	require.NotNil(t, err, "the blank check state step expects no accounts")
+
+	generatingExecutor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	generatingExecutor.EnableExpectationGeneration(false)
+	runner := mc.NewScenarioRunner(generatingExecutor, newFileResolver())
+	runner.RewriteScenarios = true
+	err = runner.RunSingleJSONScenario(scenarioPath)
+	require.Nil(t, err)
+
+	contents, err := os.ReadFile(scenarioPath)
+	require.Nil(t, err)
+	parser := mjparse.NewParser(newFileResolver())
+	scenario, err := parser.ParseScenarioFile(contents)
+	require.Nil(t, err)
+	require.Len(t, scenario.Steps, 5)
+
+	query := scenario.Steps[3].(*mj.TxStep)
+	require.NotNil(t, query.ExpectedResult)
+	require.Len(t, query.ExpectedResult.Out, 1)
+	require.Equal(t, []byte{12}, query.ExpectedResult.Out[0].Value)
+
+	checkState := scenario.Steps[4].(*mj.CheckStateStep)
+	require.Len(t, checkState.CheckAccounts.Accounts, 2)
+
+	rerunExecutor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	err = mc.NewScenarioRunner(rerunExecutor, newFileResolver()).RunSingleJSONScenario(scenarioPath)
+	require.Nil(t, err)
+}
@@ -0,0 +1,42 @@
+package scenarioexec
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	vmi "github.com/kalyan3104/k-chain-vm-common-go"
+)
+
+// ComputeLogHash yields the hash that a scenario can expect instead of listing the logs,
+// e.g. "logs": "0x...". It is the SHA-256 of all log entries, where each byte field is
+// length-prefixed and each list is count-prefixed, so that different logs cannot collide
+// by shifting bytes between fields.
+func ComputeLogHash(logs []*vmi.LogEntry) string {
+	hasher := sha256.New()
+	writeCount := func(count int) {
+		var prefix [4]byte
+		binary.BigEndian.PutUint32(prefix[:], uint32(count))
+		_, _ = hasher.Write(prefix[:])
+	}
+	writeBytes := func(value []byte) {
+		writeCount(len(value))
+		_, _ = hasher.Write(value)
+	}
+
+	writeCount(len(logs))
+	for _, logEntry := range logs {
+		writeBytes(logEntry.Address)
+		writeBytes(logEntry.Identifier)
+		writeCount(len(logEntry.Topics))
+		for _, topic := range logEntry.Topics {
+			writeBytes(topic)
+		}
+		writeCount(len(logEntry.Data))
+		for _, data := range logEntry.Data {
+			writeBytes(data)
+		}
+	}
+
+	return "0x" + hex.EncodeToString(hasher.Sum(nil))
+}
@@ -0,0 +1,139 @@
+package scenarioexec
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+)
+
+// ErrNilProbeHandler signals that a nil probe handler was given to an executor.
+var ErrNilProbeHandler = errors.New("nil probe handler")
+
+// ProbeHandler is notified of the contracts instrumented by an executor, and of the probes
+// they execute. The probes of a contract are identified by the InstrumentedModule.
+type ProbeHandler interface {
+	ModuleInstrumented(codeHash []byte, module *wasmcov.InstrumentedModule)
+	ProbeVisited(probeID int32)
+}
+
+// InstanceWrapper decorates the Wasmer instances created by an executor, e.g. to observe
+// the results of their exported functions.
+type InstanceWrapper func(instance *wasmer.Instance) wasmer.InstanceHandler
+
+// probeHandlers forwards the probes traced by the VM of an executor to all its handlers
+type probeHandlers []ProbeHandler
+
+// TraceProbe notifies all handlers of the probe
+func (handlers probeHandlers) TraceProbe(probeID int32) {
+	for _, handler := range handlers {
+		handler.ProbeVisited(probeID)
+	}
+}
+
+// AddProbeHandler instruments the contracts instantiated from now on with probes, reported to the
+// given handler. The VM gives back the gas consumed by the probes, so gas checks are unaffected.
+func (ae *VMTestExecutor) AddProbeHandler(handler ProbeHandler) error {
+	if handler == nil {
+		return ErrNilProbeHandler
+	}
+
+	ae.installInstanceBuilder()
+	ae.probeHandlers = append(ae.probeHandlers, handler)
+	ae.vm.SetProbeTracer(ae.probeHandlers)
+
+	return nil
+}
+
+// RemoveProbeHandlers stops reporting probes to any handler.
+// Contracts already instrumented keep their probes, which are no longer reported.
+func (ae *VMTestExecutor) RemoveProbeHandlers() {
+	ae.vm.SetProbeTracer(nil)
+	ae.probeHandlers = nil
+}
+
+// SetInstanceWrapper decorates all Wasmer instances created from now on with the given wrapper.
+func (ae *VMTestExecutor) SetInstanceWrapper(wrapper InstanceWrapper) {
+	ae.installInstanceBuilder()
+	ae.instanceWrapper = wrapper
+}
+
+func (ae *VMTestExecutor) probesEnabled() bool {
+	return len(ae.probeHandlers) > 0
+}
+
+func (ae *VMTestExecutor) installInstanceBuilder() {
+	if ae.instanceBuilderInstalled {
+		return
+	}
+	ae.vm.Runtime().ReplaceInstanceBuilder(&executorInstanceBuilder{
+		executor: ae,
+	})
+	ae.instanceBuilderInstalled = true
+}
+
+// executorInstanceBuilder instruments the contracts with probes when the executor has probe handlers,
+// and applies the instance wrapper of the executor
+type executorInstanceBuilder struct {
+	executor *VMTestExecutor
+}
+
+// NewInstanceWithOptions creates a new Wasmer instance from the contract code
+func (builder *executorInstanceBuilder) NewInstanceWithOptions(
+	contractCode []byte,
+	options wasmer.CompilationOptions,
+) (wasmer.InstanceHandler, error) {
+	if builder.executor.probesEnabled() {
+		instrumentedCode, err := builder.instrument(contractCode)
+		if err != nil {
+			return nil, err
+		}
+		contractCode = instrumentedCode
+	}
+
+	instance, err := wasmer.NewInstanceWithOptions(contractCode, options)
+	if err != nil {
+		return instance, err
+	}
+	return builder.wrap(instance), nil
+}
+
+// NewInstanceFromCompiledCodeWithOptions creates a new Wasmer instance from compiled code,
+// which was compiled from the code given to NewInstanceWithOptions, instrumented if needed
+func (builder *executorInstanceBuilder) NewInstanceFromCompiledCodeWithOptions(
+	compiledCode []byte,
+	options wasmer.CompilationOptions,
+) (wasmer.InstanceHandler, error) {
+	instance, err := wasmer.NewInstanceFromCompiledCodeWithOptions(compiledCode, options)
+	if err != nil {
+		return instance, err
+	}
+	return builder.wrap(instance), nil
+}
+
+// instrument numbers the probes of each contract from its code hash, the same way the function
+// call tracer identifies the contract, so that the same code always yields the same probes
+func (builder *executorInstanceBuilder) instrument(contractCode []byte) ([]byte, error) {
+	codeHash, err := builder.executor.vm.Crypto().Sha256(contractCode)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := wasmcov.Instrument(contractCode, binary.BigEndian.Uint32(codeHash))
+	if err != nil {
+		return nil, err
+	}
+	for _, handler := range builder.executor.probeHandlers {
+		handler.ModuleInstrumented(codeHash, module)
+	}
+
+	return module.Code, nil
+}
+
+func (builder *executorInstanceBuilder) wrap(instance *wasmer.Instance) wasmer.InstanceHandler {
+	if builder.executor.instanceWrapper == nil {
+		return instance
+	}
+	return builder.executor.instanceWrapper(instance)
+}
@@ -21,6 +21,15 @@ func (ae *VMTestExecutor) ExecuteCheckStateStep(step *mj.CheckStateStep) error {
 		log.Trace("CheckStateStep", "comment", step.Comment)
 	}
 
+	if ae.generateExpectations {
+		checkAccounts, err := ae.generateCheckAccounts(step.CheckAccounts)
+		if err != nil {
+			return err
+		}
+		step.CheckAccounts = checkAccounts
+		return nil
+	}
+
 	return ae.checkAccounts(step.CheckAccounts)
 }
 
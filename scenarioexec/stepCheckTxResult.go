@@ -12,6 +12,7 @@ import (
 
 func (ae *VMTestExecutor) checkTxResults(
 	txIndex string,
+	function string,
 	blResult *mj.TransactionResult,
 	checkGas bool,
 	output *vmi.VMOutput,
@@ -28,18 +29,19 @@ func (ae *VMTestExecutor) checkTxResults(
 	}
 
 	// check result
-	if len(output.ReturnData) != len(blResult.Out) {
+	expectedOut := mj.ExpandMultiValueChecks(blResult.Out)
+	if len(output.ReturnData) != len(expectedOut) {
 		return fmt.Errorf("result length mismatch. Tx %s. Want: %s. Have: %s",
 			txIndex,
 			checkBytesListPretty(blResult.Out),
-			mj.ResultAsString(output.ReturnData))
+			ae.resultAsString(function, output.ReturnData))
 	}
-	for i, expected := range blResult.Out {
+	for i, expected := range expectedOut {
 		if !expected.Check(output.ReturnData[i]) {
 			return fmt.Errorf("result mismatch. Tx %s. Want: %s. Have: %s",
 				txIndex,
 				checkBytesListPretty(blResult.Out),
-				mj.ResultAsString(output.ReturnData))
+				ae.resultAsString(function, output.ReturnData))
 		}
 	}
 
@@ -64,6 +66,16 @@ func (ae *VMTestExecutor) checkTxResults(
 		return nil
 	}
 
+	// "logs": "0x..." is checked against the hash of all logs
+	if len(blResult.LogHash) > 0 {
+		logHash := ComputeLogHash(output.Logs)
+		if blResult.LogHash != logHash {
+			return fmt.Errorf("log hash mismatch. Tx %s. Want: %s. Have: %s",
+				txIndex, blResult.LogHash, logHash)
+		}
+		return nil
+	}
+
 	// this is the real log check
 	if len(blResult.Logs) != len(output.Logs) {
 		return fmt.Errorf("wrong number of logs. Tx %s. Want:%d. Got:%d",
@@ -85,31 +97,84 @@ func (ae *VMTestExecutor) checkTxResults(
 				mjwrite.LogToString(testLog),
 				mjwrite.LogToString(ae.convertLogToTestFormat(outLog)))
 		}
-		if len(outLog.Topics) != len(testLog.Topics) {
-			return fmt.Errorf("wrong number of log topics. Tx %s. Want:\n%s\nGot:\n%s",
-				txIndex,
-				mjwrite.LogToString(testLog),
-				mjwrite.LogToString(ae.convertLogToTestFormat(outLog)))
+
+		// when decoded fields are expected, raw topics and data are only checked if given explicitly
+		hasFields := len(testLog.Fields) > 0
+		if !hasFields || len(testLog.Topics) > 0 {
+			err := ae.checkLogTopics(txIndex, testLog, outLog)
+			if err != nil {
+				return err
+			}
 		}
-		for ti := range outLog.Topics {
-			if !testLog.Topics[ti].Check(outLog.Topics[ti]) {
-				return fmt.Errorf("bad log topic. Tx %s. Want:\n%s\nGot:\n%s",
+		if !hasFields || !testLog.Data.IsUnspecified() {
+			if !testLog.Data.Check(outLog.GetFirstDataItem()) {
+				return fmt.Errorf("bad log data. Tx %s. Want:\n%s\nGot:\n%s",
 					txIndex,
 					mjwrite.LogToString(testLog),
 					mjwrite.LogToString(ae.convertLogToTestFormat(outLog)))
 			}
 		}
-		if !testLog.Data.Check(outLog.GetFirstDataItem()) {
-			return fmt.Errorf("bad log data. Tx %s. Want:\n%s\nGot:\n%s",
+		if hasFields {
+			err := ae.checkLogFields(txIndex, testLog, outLog)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ae *VMTestExecutor) checkLogTopics(txIndex string, testLog *mj.LogEntry, outLog *vmi.LogEntry) error {
+	expectedTopics := mj.ExpandMultiValueChecks(testLog.Topics)
+	if len(outLog.Topics) != len(expectedTopics) {
+		return fmt.Errorf("wrong number of log topics. Tx %s. Want:\n%s\nGot:\n%s",
+			txIndex,
+			mjwrite.LogToString(testLog),
+			mjwrite.LogToString(ae.convertLogToTestFormat(outLog)))
+	}
+	for ti := range outLog.Topics {
+		if !expectedTopics[ti].Check(outLog.Topics[ti]) {
+			return fmt.Errorf("bad log topic. Tx %s. Want:\n%s\nGot:\n%s",
 				txIndex,
 				mjwrite.LogToString(testLog),
 				mjwrite.LogToString(ae.convertLogToTestFormat(outLog)))
 		}
 	}
+	return nil
+}
 
+func (ae *VMTestExecutor) checkLogFields(txIndex string, testLog *mj.LogEntry, outLog *vmi.LogEntry) error {
+	decoded, err := ae.eventDecoder.Decode(outLog)
+	if err != nil {
+		return fmt.Errorf("cannot decode log. Tx %s: %w", txIndex, err)
+	}
+	for _, expectedField := range testLog.Fields {
+		field := decoded.Field(expectedField.Name)
+		if field == nil {
+			return fmt.Errorf("unknown log field. Tx %s. Event %s has no field %s",
+				txIndex, decoded.Identifier, expectedField.Name)
+		}
+		if !expectedField.Value.Check(field.Raw) {
+			return fmt.Errorf("bad log field %s. Tx %s. Want: %s. Have: %s",
+				expectedField.Name,
+				txIndex,
+				oj.JSONString(expectedField.Value.Original),
+				field.Value)
+		}
+	}
 	return nil
 }
 
+// resultAsString shows the results by their ABI types, when the endpoint is known
+func (ae *VMTestExecutor) resultAsString(function string, results [][]byte) string {
+	typedResults, ok := ae.exprReconstructor.ReconstructResults(results, function)
+	if ok {
+		return fmt.Sprintf("%s %s", mj.ResultAsString(results), typedResults)
+	}
+	return mj.ResultAsString(results)
+}
+
 // JSONCheckBytesString formats a list of JSONCheckBytes for printing to console.
 // TODO: move somewhere else
 func checkBytesListPretty(jcbs []mj.JSONCheckBytes) string {
@@ -10,10 +10,14 @@ import (
 	"github.com/kalyan3104/k-chain-core-go/data/vm"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 )
 
 func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcommon.VMOutput, error) {
 	ae.World.CreateStateBackup()
+	if ae.callGraph != nil {
+		ae.callGraph.BeginTx(txIndex)
+	}
 
 	var err error
 	defer func() {
@@ -73,7 +77,10 @@ func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcomm
 			// gas restrictions waived during SC queries
 			tx.GasLimit.Value = math.MaxUint64
 			gasForExecution = math.MaxUint64
-			fallthrough
+			output, err = ae.scQuery(txIndex, tx, gasForExecution)
+			if err != nil {
+				return nil, err
+			}
 		case mj.ScCall:
 			output, err = ae.scCall(txIndex, tx, gasForExecution)
 			if err != nil {
@@ -92,6 +99,7 @@ func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcomm
 	}
 
 	if output.ReturnCode == vmcommon.Ok {
+		ae.printStorageDiff(txIndex, output)
 		err := ae.updateStateAfterTx(tx, output)
 		if err != nil {
 			return nil, err
@@ -105,6 +113,19 @@ func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcomm
 	return output, nil
 }
 
+func (ae *VMTestExecutor) printStorageDiff(txIndex string, output *vmcommon.VMOutput) {
+	if ae.storageDiffWriter == nil {
+		return
+	}
+
+	report := NewStorageDiffReport(ae.World, output)
+	if len(report) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(ae.storageDiffWriter, "tx %s storage changes:\n%s", txIndex, report.String())
+}
+
 func (ae *VMTestExecutor) senderHasEnoughBalance(tx *mj.Transaction) bool {
 	if !tx.Type.HasSender() {
 		return true
@@ -206,6 +227,41 @@ func (ae *VMTestExecutor) scCreate(txIndex string, tx *mj.Transaction, gasLimit
 }
 
 func (ae *VMTestExecutor) scCall(txIndex string, tx *mj.Transaction, gasLimit uint64) (*vmcommon.VMOutput, error) {
+	input, err := ae.scCallInput(txIndex, tx, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tx.AccessList) > 0 {
+		return ae.vm.RunSmartContractCallWithAccessList(input, accessListFromTx(tx))
+	}
+
+	return ae.vm.RunSmartContractCall(input)
+}
+
+func accessListFromTx(tx *mj.Transaction) vmhost.StorageAccessList {
+	accessList := make(vmhost.StorageAccessList, 0, len(tx.AccessList))
+	for _, entry := range tx.AccessList {
+		accessList = append(accessList, &vmhost.StorageAccessListEntry{
+			Address: entry.Address.Value,
+			Keys:    mj.JSONBytesFromStringValues(entry.Keys),
+		})
+	}
+
+	return accessList
+}
+
+// scQuery runs the call as a query, which fails instead of changing the state
+func (ae *VMTestExecutor) scQuery(txIndex string, tx *mj.Transaction, gasLimit uint64) (*vmcommon.VMOutput, error) {
+	input, err := ae.scCallInput(txIndex, tx, gasLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return ae.vm.RunSmartContractQuery(input)
+}
+
+func (ae *VMTestExecutor) scCallInput(txIndex string, tx *mj.Transaction, gasLimit uint64) (*vmcommon.ContractCallInput, error) {
 	recipient := ae.World.AcctMap.GetAccount(tx.To.Value)
 	if recipient == nil {
 		return nil, fmt.Errorf("tx recipient (address: %s) does not exist", hex.EncodeToString(tx.To.Value))
@@ -231,7 +287,7 @@ func (ae *VMTestExecutor) scCall(txIndex string, tx *mj.Transaction, gasLimit ui
 		VMInput:       vmInput,
 	}
 
-	return ae.vm.RunSmartContractCall(input)
+	return input, nil
 }
 
 func (ae *VMTestExecutor) directDCDTTransferFromTx(tx *mj.Transaction) (uint64, error) {
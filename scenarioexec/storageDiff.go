@@ -0,0 +1,134 @@
+package scenarioexec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	worldhook "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
+	er "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/reconstructor"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// StorageChange is the change of a single storage key, with the key and the values decoded
+// into a human-readable format.
+type StorageChange struct {
+	Key      string `json:"key"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+	Status   string `json:"status"`
+}
+
+// ContractStorageDiff holds the storage changes of a single account, sorted by key.
+type ContractStorageDiff struct {
+	Address    string           `json:"address"`
+	AddressHex string           `json:"addressHex"`
+	Changes    []*StorageChange `json:"changes"`
+}
+
+// StorageDiffReport holds the storage changes made by a transaction, grouped by account and
+// sorted by address. Storage updates which leave the value unchanged are not reported.
+type StorageDiffReport []*ContractStorageDiff
+
+// NewStorageDiffReport compares the storage updates of the given output with the storage of the
+// world. It must be called before the output is applied to the world.
+func NewStorageDiffReport(world *worldhook.MockWorld, output *vmcommon.VMOutput) StorageDiffReport {
+	report := make(StorageDiffReport, 0)
+	if output == nil {
+		return report
+	}
+
+	exprReconstructor := er.ExprReconstructor{}
+
+	outputAccounts := make([]*vmcommon.OutputAccount, 0, len(output.OutputAccounts))
+	for _, outputAccount := range output.OutputAccounts {
+		outputAccounts = append(outputAccounts, outputAccount)
+	}
+	sort.Slice(outputAccounts, func(i, j int) bool {
+		return bytes.Compare(outputAccounts[i].Address, outputAccounts[j].Address) < 0
+	})
+
+	for _, outputAccount := range outputAccounts {
+		account := world.AcctMap.GetAccount(outputAccount.Address)
+
+		keys := make([]string, 0, len(outputAccount.StorageUpdates))
+		for key := range outputAccount.StorageUpdates {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		changes := make([]*StorageChange, 0)
+		for _, key := range keys {
+			var oldValue []byte
+			if account != nil {
+				oldValue = account.StorageValue(key)
+			}
+			newValue := outputAccount.StorageUpdates[key].Data
+
+			status := storageStatus(oldValue, newValue)
+			if status == vmhost.StorageUnchanged {
+				continue
+			}
+
+			changes = append(changes, &StorageChange{
+				Key:      exprReconstructor.Reconstruct([]byte(key), er.NoHint),
+				OldValue: exprReconstructor.Reconstruct(oldValue, er.NoHint),
+				NewValue: exprReconstructor.Reconstruct(newValue, er.NoHint),
+				Status:   status.String(),
+			})
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		report = append(report, &ContractStorageDiff{
+			Address:    exprReconstructor.Reconstruct(outputAccount.Address, er.AddressHint),
+			AddressHex: hex.EncodeToString(outputAccount.Address),
+			Changes:    changes,
+		})
+	}
+
+	return report
+}
+
+func storageStatus(oldValue []byte, newValue []byte) vmhost.StorageStatus {
+	switch {
+	case bytes.Equal(oldValue, newValue):
+		return vmhost.StorageUnchanged
+	case len(oldValue) == 0:
+		return vmhost.StorageAdded
+	case len(newValue) == 0:
+		return vmhost.StorageDeleted
+	default:
+		return vmhost.StorageModified
+	}
+}
+
+// String formats the report as text, one line per account followed by one line per change.
+func (report StorageDiffReport) String() string {
+	var sb strings.Builder
+	for _, contractDiff := range report {
+		sb.WriteString(contractDiff.Address)
+		sb.WriteString("\n")
+		for _, change := range contractDiff.Changes {
+			sb.WriteString(fmt.Sprintf("  %s: %s -> %s (%s)\n",
+				quoteIfEmpty(change.Key),
+				quoteIfEmpty(change.OldValue),
+				quoteIfEmpty(change.NewValue),
+				change.Status))
+		}
+	}
+
+	return sb.String()
+}
+
+func quoteIfEmpty(value string) string {
+	if len(value) == 0 {
+		return `""`
+	}
+	return value
+}
@@ -0,0 +1,245 @@
+package scenabi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ContractABI describes the endpoints, custom types and events of a contract.
+// Only the parts of the ABI needed to encode scenario values and decode results and logs are modelled.
+type ContractABI struct {
+	Name      string              `json:"name"`
+	Endpoints []*EndpointABI      `json:"endpoints"`
+	Types     map[string]*TypeABI `json:"types"`
+	Events    []*EventABI         `json:"events"`
+}
+
+// EndpointABI describes the arguments and results of a contract endpoint.
+type EndpointABI struct {
+	Name    string      `json:"name"`
+	Inputs  []*ParamABI `json:"inputs"`
+	Outputs []*ParamABI `json:"outputs"`
+}
+
+// ParamABI describes an endpoint argument or result.
+type ParamABI struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// TypeABI describes a custom type, either a "struct" or an "enum".
+type TypeABI struct {
+	Type     string        `json:"type"`
+	Fields   []*FieldABI   `json:"fields,omitempty"`
+	Variants []*VariantABI `json:"variants,omitempty"`
+}
+
+// FieldABI describes a struct or enum variant field.
+type FieldABI struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// VariantABI describes an enum variant. Variants without fields are unit variants.
+type VariantABI struct {
+	Name         string      `json:"name"`
+	Discriminant int         `json:"discriminant"`
+	Fields       []*FieldABI `json:"fields,omitempty"`
+}
+
+// EventABI describes a single event.
+// Indexed inputs are found in the log topics, in order.
+// The remaining inputs are encoded in the log data.
+type EventABI struct {
+	Identifier string           `json:"identifier"`
+	Inputs     []*EventInputABI `json:"inputs"`
+}
+
+// EventInputABI describes one field of an event.
+type EventInputABI struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// NewContractABI creates an empty ContractABI, to which other ABIs can be merged.
+func NewContractABI() *ContractABI {
+	return &ContractABI{
+		Types: make(map[string]*TypeABI),
+	}
+}
+
+// Merge adds the endpoints, types and events of another ABI.
+// Definitions from the other ABI replace existing ones with the same name.
+func (abi *ContractABI) Merge(other *ContractABI) {
+	if abi.Types == nil {
+		abi.Types = make(map[string]*TypeABI)
+	}
+	for name, typeABI := range other.Types {
+		abi.Types[name] = typeABI
+	}
+	for _, endpoint := range other.Endpoints {
+		abi.removeEndpoint(endpoint.Name)
+		abi.Endpoints = append(abi.Endpoints, endpoint)
+	}
+	abi.Events = append(abi.Events, other.Events...)
+}
+
+// Endpoint returns the endpoint with the given name, or nil if there is none.
+func (abi *ContractABI) Endpoint(name string) *EndpointABI {
+	for _, endpoint := range abi.Endpoints {
+		if endpoint.Name == name {
+			return endpoint
+		}
+	}
+	return nil
+}
+
+// CustomType returns the definition of a custom type, or nil if there is none.
+func (abi *ContractABI) CustomType(name string) *TypeABI {
+	return abi.Types[name]
+}
+
+func (abi *ContractABI) removeEndpoint(name string) {
+	for i, endpoint := range abi.Endpoints {
+		if endpoint.Name == name {
+			abi.Endpoints = append(abi.Endpoints[:i], abi.Endpoints[i+1:]...)
+			return
+		}
+	}
+}
+
+// ParseABI parses a contract ABI from JSON.
+func ParseABI(abiJSON []byte) (*ContractABI, error) {
+	abi := &ContractABI{}
+	err := json.Unmarshal(abiJSON, abi)
+	if err != nil {
+		return nil, err
+	}
+
+	err = abi.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return abi, nil
+}
+
+// LoadABI reads and parses a contract ABI JSON file.
+func LoadABI(path string) (*ContractABI, error) {
+	abiJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	abi, err := ParseABI(abiJSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI file %s: %w", path, err)
+	}
+
+	return abi, nil
+}
+
+func (abi *ContractABI) validate() error {
+	for name, typeABI := range abi.Types {
+		err := abi.validateCustomType(name, typeABI)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, endpoint := range abi.Endpoints {
+		if len(endpoint.Name) == 0 {
+			return errors.New("endpoint without name")
+		}
+		params := append(append([]*ParamABI{}, endpoint.Inputs...), endpoint.Outputs...)
+		for _, param := range params {
+			err := abi.validateTypeName(param.Type)
+			if err != nil {
+				return fmt.Errorf("endpoint %s: %w", endpoint.Name, err)
+			}
+		}
+	}
+
+	identifiers := make(map[string]struct{})
+	for _, event := range abi.Events {
+		if len(event.Identifier) == 0 {
+			return errors.New("event without identifier")
+		}
+		if _, duplicate := identifiers[event.Identifier]; duplicate {
+			return fmt.Errorf("duplicate event identifier: %s", event.Identifier)
+		}
+		identifiers[event.Identifier] = struct{}{}
+
+		for _, input := range event.Inputs {
+			if len(input.Name) == 0 {
+				return fmt.Errorf("event %s: input without name", event.Identifier)
+			}
+			err := abi.validateTypeName(input.Type)
+			if err != nil {
+				return fmt.Errorf("event %s, input %s: %w", event.Identifier, input.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (abi *ContractABI) validateCustomType(name string, typeABI *TypeABI) error {
+	var fields []*FieldABI
+	switch typeABI.Type {
+	case "struct":
+		fields = typeABI.Fields
+	case "enum":
+		if len(typeABI.Variants) == 0 {
+			return fmt.Errorf("type %s: enum without variants", name)
+		}
+		discriminants := make(map[int]struct{})
+		for _, variant := range typeABI.Variants {
+			if variant.Discriminant < 0 || variant.Discriminant > 255 {
+				return fmt.Errorf("type %s: invalid discriminant for variant %s", name, variant.Name)
+			}
+			if _, duplicate := discriminants[variant.Discriminant]; duplicate {
+				return fmt.Errorf("type %s: duplicate discriminant %d", name, variant.Discriminant)
+			}
+			discriminants[variant.Discriminant] = struct{}{}
+			fields = append(fields, variant.Fields...)
+		}
+	default:
+		return fmt.Errorf("type %s: unknown kind %s", name, typeABI.Type)
+	}
+
+	for _, field := range fields {
+		err := abi.validateTypeName(field.Type)
+		if err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (abi *ContractABI) validateTypeName(typeName string) error {
+	typeRef, err := ParseTypeRef(typeName)
+	if err != nil {
+		return err
+	}
+	return abi.validateTypeRef(typeRef)
+}
+
+func (abi *ContractABI) validateTypeRef(typeRef *TypeRef) error {
+	if typeRef.IsGeneric() {
+		for _, arg := range typeRef.Args {
+			err := abi.validateTypeRef(arg)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if isKnownType(typeRef.Name) || abi.CustomType(typeRef.Name) != nil {
+		return nil
+	}
+	return fmt.Errorf("unknown type %s", typeRef.Name)
+}
@@ -0,0 +1,617 @@
+package scenabi
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+// LeafInterpreter evaluates a plain scenario expression, e.g. "5" or "str:abc", to bytes.
+type LeafInterpreter func(leaf string) ([]byte, error)
+
+// LeafFormatter yields a scenario expression for an Address, bytes or string value.
+type LeafFormatter func(typeName string, value []byte) string
+
+var errMultiValueNotAllowed = errors.New("multi-values are only allowed at the top level of argument and result lists")
+
+// Encode produces the top-level encoding of a value of the given type.
+func (abi *ContractABI) Encode(typeRef *TypeRef, node *ValueNode, interpretLeaf LeafInterpreter) ([]byte, error) {
+	return abi.encode(typeRef, node, false, interpretLeaf)
+}
+
+// EncodeMultiValue produces the top-level encodings of all the arguments or results a value stands for.
+// Multi-values yield one encoding per item, all other types yield a single encoding.
+// Optional values are written as None, which yields no encoding, or Some(...).
+func (abi *ContractABI) EncodeMultiValue(typeRef *TypeRef, node *ValueNode, interpretLeaf LeafInterpreter) ([][]byte, error) {
+	if !typeRef.IsMultiValue() {
+		encoded, err := abi.Encode(typeRef, node, interpretLeaf)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{encoded}, nil
+	}
+
+	if typeRef.Name == "optional" {
+		if node.Kind == LeafValue && node.Text == "None" {
+			return [][]byte{}, nil
+		}
+		if node.Kind != VariantValue || node.Text != "Some" || len(node.Items) != 1 {
+			return nil, fmt.Errorf("expected None or Some(...) for %s", typeRef)
+		}
+		return abi.EncodeMultiValue(typeRef.Args[0], node.Items[0], interpretLeaf)
+	}
+
+	if node.Kind != ListValue {
+		return nil, fmt.Errorf("expected [...] for %s", typeRef)
+	}
+	if typeRef.Name == "multi" && len(node.Items) != len(typeRef.Args) {
+		return nil, fmt.Errorf("%s expects %d items, got %d", typeRef, len(typeRef.Args), len(node.Items))
+	}
+
+	result := make([][]byte, 0, len(node.Items))
+	for i, item := range node.Items {
+		itemType := typeRef.Args[0]
+		if typeRef.Name == "multi" {
+			itemType = typeRef.Args[i]
+		}
+		encoded, err := abi.EncodeMultiValue(itemType, item, interpretLeaf)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, encoded...)
+	}
+	return result, nil
+}
+
+// Decode interprets the top-level encoding of a value of the given type.
+// The formatter is optional; by default bytes are formatted as hex and strings as "str:".
+func (abi *ContractABI) Decode(typeRef *TypeRef, data []byte, formatLeaf LeafFormatter) (*ValueNode, error) {
+	if formatLeaf == nil {
+		formatLeaf = defaultLeafFormatter
+	}
+	node, rest, err := abi.decode(typeRef, data, false, formatLeaf)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected trailing data: 0x%x", rest)
+	}
+	return node, nil
+}
+
+// ResultTypes yields the type of each result of an endpoint, with multi-values expanded.
+// A variadic or optional output, which must be the last one, takes all remaining results.
+func (abi *ContractABI) ResultTypes(outputs []*ParamABI, numResults int) ([]*TypeRef, error) {
+	var types []*TypeRef
+	for i, output := range outputs {
+		typeRef, err := ParseTypeRef(output.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if typeRef.Name == "variadic" || typeRef.Name == "optional" {
+			if i != len(outputs)-1 {
+				return nil, fmt.Errorf("%s output must be the last output", typeRef.Name)
+			}
+			for len(types) < numResults {
+				types = append(types, expandMultiTypes(typeRef.Args[0])...)
+				if typeRef.Name == "optional" {
+					break
+				}
+			}
+			break
+		}
+		types = append(types, expandMultiTypes(typeRef)...)
+	}
+	if len(types) != numResults {
+		return nil, fmt.Errorf("expected %d results, got %d", len(types), numResults)
+	}
+
+	return types, nil
+}
+
+func expandMultiTypes(typeRef *TypeRef) []*TypeRef {
+	if typeRef.Name != "multi" {
+		return []*TypeRef{typeRef}
+	}
+	var types []*TypeRef
+	for _, arg := range typeRef.Args {
+		types = append(types, expandMultiTypes(arg)...)
+	}
+	return types
+}
+
+func (abi *ContractABI) customType(name string) *TypeABI {
+	if abi == nil {
+		return nil
+	}
+	return abi.CustomType(name)
+}
+
+func (abi *ContractABI) encode(typeRef *TypeRef, node *ValueNode, nested bool, interpretLeaf LeafInterpreter) ([]byte, error) {
+	switch typeRef.Name {
+	case "Option":
+		if node.Kind == LeafValue && node.Text == "None" {
+			if nested {
+				return []byte{0}, nil
+			}
+			return []byte{}, nil
+		}
+		if node.Kind != VariantValue || node.Text != "Some" || len(node.Items) != 1 {
+			return nil, fmt.Errorf("expected None or Some(...) for %s", typeRef)
+		}
+		encoded, err := abi.encode(typeRef.Args[0], node.Items[0], true, interpretLeaf)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{1}, encoded...), nil
+	case "List":
+		if node.Kind != ListValue {
+			return nil, fmt.Errorf("expected [...] for %s", typeRef)
+		}
+		var result []byte
+		if nested {
+			result = lengthPrefix(len(node.Items))
+		}
+		for _, item := range node.Items {
+			encoded, err := abi.encode(typeRef.Args[0], item, true, interpretLeaf)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
+		return nonNil(result), nil
+	case "tuple":
+		if node.Kind != ListValue || len(node.Items) != len(typeRef.Args) {
+			return nil, fmt.Errorf("expected [...] with %d items for %s", len(typeRef.Args), typeRef)
+		}
+		var result []byte
+		for i, item := range node.Items {
+			encoded, err := abi.encode(typeRef.Args[i], item, true, interpretLeaf)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
+		return nonNil(result), nil
+	case "multi", "variadic", "optional":
+		return nil, errMultiValueNotAllowed
+	}
+
+	if length, isArray := arrayLength(typeRef.Name); isArray {
+		if node.Kind != ListValue || len(node.Items) != length {
+			return nil, fmt.Errorf("expected [...] with %d items for %s", length, typeRef)
+		}
+		result := make([]byte, 0)
+		for _, item := range node.Items {
+			encoded, err := abi.encode(typeRef.Args[0], item, true, interpretLeaf)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, encoded...)
+		}
+		return result, nil
+	}
+
+	if isKnownType(typeRef.Name) {
+		return encodePrimitive(typeRef.Name, node, nested, interpretLeaf)
+	}
+
+	custom := abi.customType(typeRef.Name)
+	if custom == nil {
+		return nil, fmt.Errorf("unknown type %s", typeRef.Name)
+	}
+	if custom.Type == "struct" {
+		if node.Kind != FieldsValue {
+			return nil, fmt.Errorf("expected {...} for %s", typeRef.Name)
+		}
+		return abi.encodeFields(typeRef.Name, custom.Fields, node, interpretLeaf)
+	}
+	return abi.encodeEnum(typeRef.Name, custom, node, nested, interpretLeaf)
+}
+
+func (abi *ContractABI) encodeFields(typeName string, fieldDefs []*FieldABI, node *ValueNode, interpretLeaf LeafInterpreter) ([]byte, error) {
+	values, err := matchFields(typeName, fieldDefs, node)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0)
+	for i, fieldDef := range fieldDefs {
+		fieldType, err := ParseTypeRef(fieldDef.Type)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := abi.encode(fieldType, values[i], true, interpretLeaf)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, fieldDef.Name, err)
+		}
+		result = append(result, encoded...)
+	}
+	return result, nil
+}
+
+// matchFields orders the given values as the field definitions, either by name or by position
+func matchFields(typeName string, fieldDefs []*FieldABI, node *ValueNode) ([]*ValueNode, error) {
+	if node.Fields == nil {
+		if len(node.Items) != len(fieldDefs) {
+			return nil, fmt.Errorf("%s expects %d fields, got %d", typeName, len(fieldDefs), len(node.Items))
+		}
+		return node.Items, nil
+	}
+
+	if len(node.Fields) != len(fieldDefs) {
+		return nil, fmt.Errorf("%s expects %d fields, got %d", typeName, len(fieldDefs), len(node.Fields))
+	}
+	values := make([]*ValueNode, len(fieldDefs))
+	for i, fieldDef := range fieldDefs {
+		for _, field := range node.Fields {
+			if field.Name == fieldDef.Name {
+				values[i] = field.Value
+			}
+		}
+		if values[i] == nil {
+			return nil, fmt.Errorf("%s: missing field %s", typeName, fieldDef.Name)
+		}
+	}
+	return values, nil
+}
+
+func (abi *ContractABI) encodeEnum(typeName string, custom *TypeABI, node *ValueNode, nested bool, interpretLeaf LeafInterpreter) ([]byte, error) {
+	if node.Kind != LeafValue && node.Kind != VariantValue {
+		return nil, fmt.Errorf("expected a variant of %s", typeName)
+	}
+	variant := findVariantByName(custom, node.Text)
+	if variant == nil {
+		return nil, fmt.Errorf("%s has no variant %s", typeName, node.Text)
+	}
+	if node.Kind == LeafValue && len(variant.Fields) > 0 {
+		return nil, fmt.Errorf("missing fields for %s::%s", typeName, variant.Name)
+	}
+
+	if !nested && isSimpleEnum(custom) {
+		return big.NewInt(int64(variant.Discriminant)).Bytes(), nil
+	}
+
+	result := []byte{byte(variant.Discriminant)}
+	if len(variant.Fields) == 0 {
+		return result, nil
+	}
+	encoded, err := abi.encodeFields(typeName+"::"+variant.Name, variant.Fields, node, interpretLeaf)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, encoded...), nil
+}
+
+func encodePrimitive(typeName string, node *ValueNode, nested bool, interpretLeaf LeafInterpreter) ([]byte, error) {
+	if node.Kind != LeafValue {
+		return nil, fmt.Errorf("expected a plain value for %s", typeName)
+	}
+	raw, err := interpretLeaf(node.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	size, isFixed := fixedSizes[typeName]
+	switch {
+	case isUnsignedType(typeName):
+		if strings.HasPrefix(node.Text, "-") {
+			return nil, fmt.Errorf("negative value %s for %s", node.Text, typeName)
+		}
+		encoded := big.NewInt(0).SetBytes(raw).Bytes()
+		if isFixed && len(encoded) > size {
+			return nil, fmt.Errorf("value %s does not fit in %s", node.Text, typeName)
+		}
+		return encodeNumber(encoded, size, isFixed, nested), nil
+	case isSignedType(typeName):
+		value := big.NewInt(0).SetBytes(raw)
+		if strings.HasPrefix(node.Text, "-") || strings.HasPrefix(node.Text, "+") {
+			value = twos.SetBytes(value, raw)
+		}
+		encoded := twos.ToBytes(value)
+		if isFixed && len(encoded) > size {
+			return nil, fmt.Errorf("value %s does not fit in %s", node.Text, typeName)
+		}
+		if nested && isFixed {
+			return twos.ToBytesOfLength(value, size)
+		}
+		return encodeNumber(encoded, size, isFixed, nested), nil
+	case typeName == "bool":
+		value := big.NewInt(0).SetBytes(raw)
+		if value.BitLen() > 1 {
+			return nil, fmt.Errorf("invalid bool value %s", node.Text)
+		}
+		if nested {
+			return []byte{byte(value.Uint64())}, nil
+		}
+		return value.Bytes(), nil
+	case isFixed:
+		if len(raw) != size {
+			return nil, fmt.Errorf("invalid %s %s", typeName, node.Text)
+		}
+		return raw, nil
+	}
+
+	if nested {
+		return append(lengthPrefix(len(raw)), raw...), nil
+	}
+	return raw, nil
+}
+
+func encodeNumber(encoded []byte, size int, isFixed bool, nested bool) []byte {
+	if !nested {
+		return encoded
+	}
+	if isFixed {
+		return twos.CopyAlignRight(encoded, size)
+	}
+	return append(lengthPrefix(len(encoded)), encoded...)
+}
+
+func (abi *ContractABI) decode(typeRef *TypeRef, data []byte, nested bool, formatLeaf LeafFormatter) (*ValueNode, []byte, error) {
+	switch typeRef.Name {
+	case "Option":
+		if !nested && len(data) == 0 {
+			return &ValueNode{Kind: LeafValue, Text: "None"}, nil, nil
+		}
+		if len(data) == 0 {
+			return nil, nil, errInputTooShort
+		}
+		switch data[0] {
+		case 0:
+			return &ValueNode{Kind: LeafValue, Text: "None"}, data[1:], nil
+		case 1:
+			item, rest, err := abi.decode(typeRef.Args[0], data[1:], true, formatLeaf)
+			if err != nil {
+				return nil, nil, err
+			}
+			return &ValueNode{Kind: VariantValue, Text: "Some", Items: []*ValueNode{item}}, rest, nil
+		}
+		return nil, nil, fmt.Errorf("invalid Option marker: 0x%02x", data[0])
+	case "List":
+		count := -1
+		if nested {
+			if len(data) < lengthPrefixSize {
+				return nil, nil, errInputTooShort
+			}
+			count = int(binary.BigEndian.Uint32(data[:lengthPrefixSize]))
+			data = data[lengthPrefixSize:]
+		}
+		node := &ValueNode{Kind: ListValue}
+		for (count < 0 && len(data) > 0) || len(node.Items) < count {
+			item, rest, err := abi.decode(typeRef.Args[0], data, true, formatLeaf)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.Items = append(node.Items, item)
+			data = rest
+		}
+		return node, data, nil
+	case "tuple":
+		node := &ValueNode{Kind: ListValue}
+		for _, arg := range typeRef.Args {
+			item, rest, err := abi.decode(arg, data, true, formatLeaf)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.Items = append(node.Items, item)
+			data = rest
+		}
+		return node, data, nil
+	case "multi", "variadic", "optional":
+		return nil, nil, errMultiValueNotAllowed
+	}
+
+	if length, isArray := arrayLength(typeRef.Name); isArray {
+		node := &ValueNode{Kind: ListValue}
+		for len(node.Items) < length {
+			item, rest, err := abi.decode(typeRef.Args[0], data, true, formatLeaf)
+			if err != nil {
+				return nil, nil, err
+			}
+			node.Items = append(node.Items, item)
+			data = rest
+		}
+		return node, data, nil
+	}
+
+	if isKnownType(typeRef.Name) {
+		encoded := data
+		var rest []byte
+		if nested {
+			var err error
+			encoded, rest, err = splitNestedPrimitive(typeRef.Name, data)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		text, err := formatPrimitive(typeRef.Name, encoded, formatLeaf)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &ValueNode{Kind: LeafValue, Text: text}, rest, nil
+	}
+
+	custom := abi.customType(typeRef.Name)
+	if custom == nil {
+		return nil, nil, fmt.Errorf("unknown type %s", typeRef.Name)
+	}
+	if custom.Type == "struct" {
+		fields, rest, err := abi.decodeFields(custom.Fields, data, formatLeaf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", typeRef.Name, err)
+		}
+		return &ValueNode{Kind: FieldsValue, Fields: fields}, rest, nil
+	}
+
+	if !nested && isSimpleEnum(custom) {
+		discriminant := big.NewInt(0).SetBytes(data)
+		variant := findVariantByDiscriminant(custom, discriminant)
+		if variant == nil {
+			return nil, nil, fmt.Errorf("%s: invalid discriminant 0x%x", typeRef.Name, data)
+		}
+		return &ValueNode{Kind: LeafValue, Text: variant.Name}, nil, nil
+	}
+	if len(data) == 0 {
+		return nil, nil, errInputTooShort
+	}
+	variant := findVariantByDiscriminant(custom, big.NewInt(int64(data[0])))
+	if variant == nil {
+		return nil, nil, fmt.Errorf("%s: invalid discriminant 0x%02x", typeRef.Name, data[0])
+	}
+	if len(variant.Fields) == 0 {
+		return &ValueNode{Kind: LeafValue, Text: variant.Name}, data[1:], nil
+	}
+	fields, rest, err := abi.decodeFields(variant.Fields, data[1:], formatLeaf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s::%s: %w", typeRef.Name, variant.Name, err)
+	}
+	node := &ValueNode{Kind: VariantValue, Text: variant.Name, Fields: fields}
+	if hasPositionalFields(variant.Fields) {
+		node.Fields = nil
+		for _, field := range fields {
+			node.Items = append(node.Items, field.Value)
+		}
+	}
+	return node, rest, nil
+}
+
+func (abi *ContractABI) decodeFields(fieldDefs []*FieldABI, data []byte, formatLeaf LeafFormatter) ([]*ValueField, []byte, error) {
+	fields := make([]*ValueField, 0, len(fieldDefs))
+	for _, fieldDef := range fieldDefs {
+		fieldType, err := ParseTypeRef(fieldDef.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		value, rest, err := abi.decode(fieldType, data, true, formatLeaf)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s: %w", fieldDef.Name, err)
+		}
+		fields = append(fields, &ValueField{Name: fieldDef.Name, Value: value})
+		data = rest
+	}
+	return fields, data, nil
+}
+
+// splitNestedPrimitive extracts the nested encoding of one primitive value from the beginning of data.
+// It returns the top-encoded content and the rest of the data.
+func splitNestedPrimitive(typeName string, data []byte) ([]byte, []byte, error) {
+	size, isFixed := fixedSizes[typeName]
+	if !isFixed {
+		if len(data) < lengthPrefixSize {
+			return nil, nil, errInputTooShort
+		}
+		size = int(binary.BigEndian.Uint32(data[:lengthPrefixSize]))
+		data = data[lengthPrefixSize:]
+	}
+	if len(data) < size {
+		return nil, nil, errInputTooShort
+	}
+
+	return data[:size], data[size:], nil
+}
+
+func formatPrimitive(typeName string, encoded []byte, formatLeaf LeafFormatter) (string, error) {
+	size, isFixed := fixedSizes[typeName]
+	if isFixed && len(encoded) > size {
+		return "", fmt.Errorf("value too long for %s: %d bytes", typeName, len(encoded))
+	}
+
+	switch {
+	case isUnsignedType(typeName):
+		return big.NewInt(0).SetBytes(encoded).String(), nil
+	case isSignedType(typeName):
+		return twos.SetBytes(big.NewInt(0), encoded).String(), nil
+	case typeName == "bool":
+		value := big.NewInt(0).SetBytes(encoded)
+		switch {
+		case value.Sign() == 0:
+			return "false", nil
+		case value.IsInt64() && value.Int64() == 1:
+			return "true", nil
+		}
+		return "", fmt.Errorf("invalid bool value: 0x%x", encoded)
+	case isFixed:
+		if len(encoded) != size {
+			return "", fmt.Errorf("invalid %s length: %d", typeName, len(encoded))
+		}
+	}
+	return formatLeaf(typeName, encoded), nil
+}
+
+func defaultLeafFormatter(typeName string, value []byte) string {
+	isString := typeName == "utf-8 string" || typeName == "TokenIdentifier"
+	if isString && CanFormatAsString(value) {
+		return "str:" + string(value)
+	}
+	return "0x" + hex.EncodeToString(value)
+}
+
+// CanFormatAsString returns true if a "str:" expression of the value can be written in an "abi:" value.
+func CanFormatAsString(value []byte) bool {
+	for _, b := range value {
+		if b < 32 || b > 126 || b == '"' || b == '\\' || b == '|' || strings.ContainsRune(valueDelimiters, rune(b)) {
+			return false
+		}
+	}
+	return len(value) == 0 || (value[0] != ' ' && value[len(value)-1] != ' ')
+}
+
+func isSimpleEnum(custom *TypeABI) bool {
+	for _, variant := range custom.Variants {
+		if len(variant.Fields) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func findVariantByName(custom *TypeABI, name string) *VariantABI {
+	for _, variant := range custom.Variants {
+		if variant.Name == name {
+			return variant
+		}
+	}
+	return nil
+}
+
+func findVariantByDiscriminant(custom *TypeABI, discriminant *big.Int) *VariantABI {
+	if !discriminant.IsInt64() {
+		return nil
+	}
+	for _, variant := range custom.Variants {
+		if int64(variant.Discriminant) == discriminant.Int64() {
+			return variant
+		}
+	}
+	return nil
+}
+
+// tuple-like variants have fields named "0", "1", ...
+func hasPositionalFields(fields []*FieldABI) bool {
+	for i, field := range fields {
+		if field.Name != fmt.Sprintf("%d", i) {
+			return false
+		}
+	}
+	return true
+}
+
+func lengthPrefix(length int) []byte {
+	prefix := make([]byte, lengthPrefixSize)
+	binary.BigEndian.PutUint32(prefix, uint32(length))
+	return prefix
+}
+
+func nonNil(value []byte) []byte {
+	if value == nil {
+		return []byte{}
+	}
+	return value
+}
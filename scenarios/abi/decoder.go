@@ -0,0 +1,247 @@
+package scenabi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
+)
+
+// DecodedField is a named event field, as decoded from a log entry.
+// Raw holds the normalized top encoding of the value; it is nil for multi-values,
+// which span several topics.
+type DecodedField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Raw   []byte `json:"raw"`
+}
+
+// DecodedEvent is a log entry decoded according to its event ABI.
+type DecodedEvent struct {
+	Identifier string          `json:"identifier"`
+	Fields     []*DecodedField `json:"fields"`
+}
+
+// Field returns the decoded field with the given name, or nil if there is none.
+func (event *DecodedEvent) Field(name string) *DecodedField {
+	for _, field := range event.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// EventDecoder decodes log entries using the events of one or more contract ABIs.
+type EventDecoder struct {
+	abi    *ContractABI
+	events map[string]*EventABI
+}
+
+// NewEventDecoder creates an empty EventDecoder.
+func NewEventDecoder() *EventDecoder {
+	return &EventDecoder{
+		abi:    NewContractABI(),
+		events: make(map[string]*EventABI),
+	}
+}
+
+// RegisterABI makes the events of the ABI available for decoding, along with its custom types.
+// Events and types registered later replace earlier ones with the same name.
+func (decoder *EventDecoder) RegisterABI(abi *ContractABI) {
+	decoder.abi.Merge(abi)
+	for _, event := range abi.Events {
+		decoder.events[event.Identifier] = event
+	}
+}
+
+// IsEmpty returns true if no events were registered.
+func (decoder *EventDecoder) IsEmpty() bool {
+	return len(decoder.events) == 0
+}
+
+// HasEvent returns true if the identifier matches a registered event.
+func (decoder *EventDecoder) HasEvent(identifier []byte) bool {
+	_, found := decoder.events[string(identifier)]
+	return found
+}
+
+// Decode interprets the topics and data of a log entry according to the event ABI
+// registered for its identifier.
+// Indexed inputs are top-encoded in the topics, multi-values taking one topic per item.
+// A single data input occupies the whole data, top-encoded, while multiple data inputs
+// are nested-encoded one after the other.
+func (decoder *EventDecoder) Decode(logEntry *vmcommon.LogEntry) (*DecodedEvent, error) {
+	event, found := decoder.events[string(logEntry.Identifier)]
+	if !found {
+		return nil, fmt.Errorf("no ABI registered for event %s", string(logEntry.Identifier))
+	}
+
+	inputTypes := make([]*TypeRef, len(event.Inputs))
+	var indexedTypes []*TypeRef
+	for i, input := range event.Inputs {
+		typeRef, err := ParseTypeRef(input.Type)
+		if err != nil {
+			return nil, fmt.Errorf("event %s, field %s: %w", event.Identifier, input.Name, err)
+		}
+		inputTypes[i] = typeRef
+		if input.Indexed {
+			indexedTypes = append(indexedTypes, typeRef)
+		}
+	}
+	topicCounts, err := countTopics(indexedTypes, len(logEntry.Topics))
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.Identifier, err)
+	}
+	numDataInputs := len(event.Inputs) - len(indexedTypes)
+
+	decoded := &DecodedEvent{
+		Identifier: event.Identifier,
+		Fields:     make([]*DecodedField, 0, len(event.Inputs)),
+	}
+	topics := logEntry.Topics
+	data := logEntry.GetFirstDataItem()
+	for i, input := range event.Inputs {
+		typeRef := inputTypes[i]
+
+		var value *ValueNode
+		switch {
+		case input.Indexed && typeRef.IsMultiValue():
+			value, err = decoder.decodeMultiValue(typeRef, topics[:topicCounts[0]])
+			topics = topics[topicCounts[0]:]
+			topicCounts = topicCounts[1:]
+		case input.Indexed:
+			value, err = decoder.abi.Decode(typeRef, topics[0], nil)
+			topics = topics[1:]
+			topicCounts = topicCounts[1:]
+		case numDataInputs == 1:
+			value, err = decoder.abi.Decode(typeRef, data, nil)
+			data = nil
+		default:
+			value, data, err = decoder.abi.decode(typeRef, data, true, defaultLeafFormatter)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event %s, field %s: %w", event.Identifier, input.Name, err)
+		}
+
+		field := &DecodedField{
+			Name:  input.Name,
+			Type:  input.Type,
+			Value: value.String(),
+		}
+		if !typeRef.IsMultiValue() {
+			// the normalized top encoding is what a scenario expression for the same value evaluates to
+			field.Raw, err = decoder.abi.Encode(typeRef, value, interpretFormattedLeaf)
+			if err != nil {
+				return nil, fmt.Errorf("event %s, field %s: %w", event.Identifier, input.Name, err)
+			}
+		}
+		decoded.Fields = append(decoded.Fields, field)
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("event %s: unexpected data: 0x%x", event.Identifier, data)
+	}
+
+	return decoded, nil
+}
+
+// decodeMultiValue decodes the topics of an indexed multi-value input into a list,
+// or into None or Some(...) for optional inputs.
+func (decoder *EventDecoder) decodeMultiValue(typeRef *TypeRef, topics [][]byte) (*ValueNode, error) {
+	itemTypes, err := decoder.abi.ResultTypes([]*ParamABI{{Type: typeRef.String()}}, len(topics))
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ValueNode{Kind: ListValue}
+	for i, topic := range topics {
+		item, err := decoder.abi.Decode(itemTypes[i], topic, nil)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	if typeRef.Name != "optional" {
+		return list, nil
+	}
+	if len(list.Items) == 0 {
+		return &ValueNode{Kind: LeafValue, Text: "None"}, nil
+	}
+	some := list
+	if len(list.Items) == 1 {
+		some = list.Items[0]
+	}
+	return &ValueNode{Kind: VariantValue, Text: "Some", Items: []*ValueNode{some}}, nil
+}
+
+// countTopics yields the number of topics of each indexed input. A variadic or optional input,
+// which must be the last indexed one, takes all the remaining topics.
+func countTopics(indexedTypes []*TypeRef, numTopics int) ([]int, error) {
+	counts := make([]int, len(indexedTypes))
+	numFixed := 0
+	for i, typeRef := range indexedTypes {
+		if typeRef.Name == "variadic" || typeRef.Name == "optional" {
+			if i != len(indexedTypes)-1 {
+				return nil, fmt.Errorf("indexed %s input must be the last indexed input", typeRef.Name)
+			}
+			if numTopics < numFixed {
+				return nil, fmt.Errorf("expected at least %d topics, got %d", numFixed, numTopics)
+			}
+			counts[i] = numTopics - numFixed
+			return counts, nil
+		}
+		counts[i] = len(expandMultiTypes(typeRef))
+		numFixed += counts[i]
+	}
+	if numTopics != numFixed {
+		return nil, fmt.Errorf("expected %d topics, got %d", numFixed, numTopics)
+	}
+
+	return counts, nil
+}
+
+// DecodeLogs decodes all log entries that have a registered event ABI.
+// Log entries without an ABI are skipped.
+func (decoder *EventDecoder) DecodeLogs(logs []*vmcommon.LogEntry) ([]*DecodedEvent, error) {
+	var decodedEvents []*DecodedEvent
+	for _, logEntry := range logs {
+		if !decoder.HasEvent(logEntry.Identifier) {
+			continue
+		}
+		decoded, err := decoder.Decode(logEntry)
+		if err != nil {
+			return nil, err
+		}
+		decodedEvents = append(decodedEvents, decoded)
+	}
+
+	return decodedEvents, nil
+}
+
+// interpretFormattedLeaf evaluates the leaves yielded by defaultLeafFormatter and formatPrimitive.
+func interpretFormattedLeaf(leaf string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(leaf, "str:"):
+		return []byte(leaf[len("str:"):]), nil
+	case strings.HasPrefix(leaf, "0x"):
+		return hex.DecodeString(leaf[len("0x"):])
+	case leaf == "true":
+		return []byte{1}, nil
+	case leaf == "false":
+		return []byte{}, nil
+	}
+
+	value, ok := big.NewInt(0).SetString(leaf, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %s", leaf)
+	}
+	if value.Sign() < 0 {
+		return twos.ToBytes(value), nil
+	}
+	return value.Bytes(), nil
+}
@@ -0,0 +1,226 @@
+package scenabi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `{
+	"name": "Tokens",
+	"events": [
+		{
+			"identifier": "transfer",
+			"inputs": [
+				{ "name": "from", "type": "Address", "indexed": true },
+				{ "name": "to", "type": "Address", "indexed": true },
+				{ "name": "amount", "type": "BigUint" }
+			]
+		},
+		{
+			"identifier": "swap",
+			"inputs": [
+				{ "name": "token", "type": "TokenIdentifier", "indexed": true },
+				{ "name": "nonce", "type": "u64" },
+				{ "name": "delta", "type": "BigInt" },
+				{ "name": "done", "type": "bool" }
+			]
+		}
+	]
+}`
+
+func makeDecoder(t *testing.T) *EventDecoder {
+	abi, err := ParseABI([]byte(testABI))
+	require.Nil(t, err)
+
+	decoder := NewEventDecoder()
+	decoder.RegisterABI(abi)
+	return decoder
+}
+
+func TestParseABI_Invalid(t *testing.T) {
+	_, err := ParseABI([]byte(`{"events": [{"identifier": "e", "inputs": [{"name": "x", "type": "u256"}]}]}`))
+	require.NotNil(t, err)
+
+	_, err = ParseABI([]byte(`{"events": [{"identifier": "e"}, {"identifier": "e"}]}`))
+	require.NotNil(t, err)
+}
+
+func TestEventDecoder_SingleDataField(t *testing.T) {
+	decoder := makeDecoder(t)
+	from := bytes.Repeat([]byte{1}, 32)
+	to := bytes.Repeat([]byte{2}, 32)
+
+	decoded, err := decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("transfer"),
+		Topics:     [][]byte{from, to},
+		Data:       [][]byte{{0x00, 0x03, 0xe8}},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "transfer", decoded.Identifier)
+	require.Len(t, decoded.Fields, 3)
+	require.Equal(t, from, decoded.Field("from").Raw)
+	require.Equal(t, to, decoded.Field("to").Raw)
+	require.Equal(t, "1000", decoded.Field("amount").Value)
+	require.Equal(t, []byte{0x03, 0xe8}, decoded.Field("amount").Raw)
+	require.Nil(t, decoded.Field("missing"))
+}
+
+func TestEventDecoder_NestedDataFields(t *testing.T) {
+	decoder := makeDecoder(t)
+
+	data := []byte{
+		0, 0, 0, 0, 0, 0, 0, 7, // nonce
+		0, 0, 0, 1, 0xff, // delta
+		1, // done
+	}
+	decoded, err := decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("swap"),
+		Topics:     [][]byte{[]byte("TOK-123456")},
+		Data:       [][]byte{data},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "str:TOK-123456", decoded.Field("token").Value)
+	require.Equal(t, "7", decoded.Field("nonce").Value)
+	require.Equal(t, []byte{7}, decoded.Field("nonce").Raw)
+	require.Equal(t, "-1", decoded.Field("delta").Value)
+	require.Equal(t, []byte{0xff}, decoded.Field("delta").Raw)
+	require.Equal(t, "true", decoded.Field("done").Value)
+
+	_, err = decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("swap"),
+		Topics:     [][]byte{[]byte("TOK-123456")},
+		Data:       [][]byte{data[:10]},
+	})
+	require.NotNil(t, err)
+}
+
+func TestEventDecoder_CustomTypes(t *testing.T) {
+	abi, err := ParseABI([]byte(`{
+		"types": {
+			"Payment": {
+				"type": "struct",
+				"fields": [
+					{ "name": "token", "type": "TokenIdentifier" },
+					{ "name": "amount", "type": "BigUint" }
+				]
+			},
+			"Status": {
+				"type": "enum",
+				"variants": [
+					{ "name": "Pending", "discriminant": 0 },
+					{ "name": "Done", "discriminant": 1 }
+				]
+			}
+		},
+		"events": [
+			{
+				"identifier": "paid",
+				"inputs": [
+					{ "name": "status", "type": "Status", "indexed": true },
+					{ "name": "payments", "type": "List<Payment>" },
+					{ "name": "memo", "type": "Option<bytes>" }
+				]
+			}
+		]
+	}`))
+	require.Nil(t, err)
+	decoder := NewEventDecoder()
+	decoder.RegisterABI(abi)
+
+	data := []byte{
+		0, 0, 0, 1, // payments length
+		0, 0, 0, 3, 'T', 'O', 'K', // token
+		0, 0, 0, 1, 5, // amount
+		1, 0, 0, 0, 1, 0xaa, // memo
+	}
+	decoded, err := decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("paid"),
+		Topics:     [][]byte{{1}},
+		Data:       [][]byte{data},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "Done", decoded.Field("status").Value)
+	require.Equal(t, []byte{1}, decoded.Field("status").Raw)
+	require.Equal(t, "[{token: str:TOK, amount: 5}]", decoded.Field("payments").Value)
+	require.Equal(t, data[4:16], decoded.Field("payments").Raw)
+	require.Equal(t, "Some(0xaa)", decoded.Field("memo").Value)
+	require.Equal(t, data[16:], decoded.Field("memo").Raw)
+
+	_, err = decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("paid"),
+		Topics:     [][]byte{{1}},
+		Data:       [][]byte{append(data, 0)},
+	})
+	require.NotNil(t, err)
+}
+
+func TestEventDecoder_DecodeLogs(t *testing.T) {
+	decoder := makeDecoder(t)
+
+	logs := []*vmcommon.LogEntry{
+		{Identifier: []byte("unknown")},
+		{Identifier: []byte("transfer"), Topics: [][]byte{make([]byte, 32), make([]byte, 32)}},
+	}
+	decoded, err := decoder.DecodeLogs(logs)
+	require.Nil(t, err)
+	require.Len(t, decoded, 1)
+	require.Equal(t, "0", decoded[0].Field("amount").Value)
+	require.Equal(t, []byte{}, decoded[0].Field("amount").Raw)
+
+	logs[1].Topics = logs[1].Topics[:1]
+	_, err = decoder.DecodeLogs(logs)
+	require.NotNil(t, err)
+}
+
+func TestLoadABI_FrameworkGenerated(t *testing.T) {
+	abi, err := LoadABI("../../test/multisig/output/multisig.abi.json")
+	require.Nil(t, err)
+	require.Equal(t, "Multisig", abi.Name)
+
+	performAction := abi.Endpoint("performAction")
+	require.NotNil(t, performAction)
+	types, err := abi.ResultTypes(performAction.Outputs, 0)
+	require.Nil(t, err)
+	require.Len(t, types, 0)
+	types, err = abi.ResultTypes(performAction.Outputs, 1)
+	require.Nil(t, err)
+	require.Equal(t, "Address", types[0].String())
+	_, err = abi.ResultTypes(performAction.Outputs, 2)
+	require.NotNil(t, err)
+
+	decoder := NewEventDecoder()
+	decoder.RegisterABI(abi)
+
+	board := bytes.Repeat([]byte{1}, 32)
+	data := []byte{
+		0, 0, 0, 3, // action_id
+		1, // action_data: AddBoardMember
+	}
+	data = append(data, board...)
+	data = append(data, 0, 0, 0, 1) // signers length
+	data = append(data, board...)
+	decoded, err := decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("startPerformAction"),
+		Data:       [][]byte{data},
+	})
+	require.Nil(t, err)
+	hexBoard := "0x" + strings.Repeat("01", 32)
+	require.Equal(t,
+		"{action_id: 3, action_data: AddBoardMember("+hexBoard+"), signers: ["+hexBoard+"]}",
+		decoded.Field("data").Value)
+
+	decoded, err = decoder.Decode(&vmcommon.LogEntry{
+		Identifier: []byte("performAsyncCall"),
+		Topics: [][]byte{
+			{3}, board, {0x0a}, {0x01, 0x00}, []byte("add"), {0x05}, []byte("x"),
+		},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "0x616464", decoded.Field("endpoint").Value)
+	require.Equal(t, "[0x05, 0x78]", decoded.Field("arguments").Value)
+	require.Nil(t, decoded.Field("arguments").Raw)
+}
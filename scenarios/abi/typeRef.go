@@ -0,0 +1,116 @@
+package scenabi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// generic type names, with their number of type arguments (0 = one or more);
+// the fixed-size arrays "arrayN<T>" also take one type argument
+var genericArity = map[string]int{
+	"Option":   1,
+	"List":     1,
+	"tuple":    0,
+	"multi":    0,
+	"variadic": 1,
+	"optional": 1,
+}
+
+func typeArity(typeName string) (int, bool) {
+	if _, isArray := arrayLength(typeName); isArray {
+		return 1, true
+	}
+	arity, isGeneric := genericArity[typeName]
+	return arity, isGeneric
+}
+
+// TypeRef is a parsed ABI type name, e.g. "Option<List<u32>>".
+type TypeRef struct {
+	Name string
+	Args []*TypeRef
+}
+
+// ParseTypeRef parses an ABI type name.
+// Generic types are "Option<T>", "List<T>", "arrayN<T>", "tuple<T1,T2,...>",
+// and the multi-values "multi<T1,T2,...>", "variadic<T>" and "optional<T>".
+func ParseTypeRef(typeName string) (*TypeRef, error) {
+	typeRef, rest, err := parseTypeRef(typeName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type %s: %w", typeName, err)
+	}
+	if len(strings.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("invalid type %s: unexpected %s", typeName, rest)
+	}
+	return typeRef, nil
+}
+
+func parseTypeRef(str string) (*TypeRef, string, error) {
+	str = strings.TrimLeft(str, " ")
+	end := strings.IndexAny(str, "<>,")
+	if end < 0 {
+		end = len(str)
+	}
+	typeRef := &TypeRef{
+		Name: strings.TrimSpace(str[:end]),
+	}
+	if len(typeRef.Name) == 0 {
+		return nil, "", fmt.Errorf("missing type name")
+	}
+	rest := str[end:]
+
+	arity, isGeneric := typeArity(typeRef.Name)
+	if !isGeneric {
+		return typeRef, rest, nil
+	}
+	if !strings.HasPrefix(rest, "<") {
+		return nil, "", fmt.Errorf("missing type arguments for %s", typeRef.Name)
+	}
+	rest = rest[1:]
+	for {
+		var arg *TypeRef
+		var err error
+		arg, rest, err = parseTypeRef(rest)
+		if err != nil {
+			return nil, "", err
+		}
+		typeRef.Args = append(typeRef.Args, arg)
+
+		rest = strings.TrimLeft(rest, " ")
+		if strings.HasPrefix(rest, ">") {
+			rest = rest[1:]
+			break
+		}
+		if !strings.HasPrefix(rest, ",") {
+			return nil, "", fmt.Errorf("unterminated type arguments for %s", typeRef.Name)
+		}
+		rest = rest[1:]
+	}
+	if arity > 0 && len(typeRef.Args) != arity {
+		return nil, "", fmt.Errorf("%s expects %d type arguments, got %d", typeRef.Name, arity, len(typeRef.Args))
+	}
+
+	return typeRef, rest, nil
+}
+
+// IsGeneric returns true for types with type arguments.
+func (typeRef *TypeRef) IsGeneric() bool {
+	_, isGeneric := typeArity(typeRef.Name)
+	return isGeneric
+}
+
+// IsMultiValue returns true for types that stand for several arguments or results.
+func (typeRef *TypeRef) IsMultiValue() bool {
+	return typeRef.Name == "multi" || typeRef.Name == "variadic" || typeRef.Name == "optional"
+}
+
+// String yields the type name, in the canonical form.
+func (typeRef *TypeRef) String() string {
+	if len(typeRef.Args) == 0 {
+		return typeRef.Name
+	}
+	args := make([]string, len(typeRef.Args))
+	for i, arg := range typeRef.Args {
+		args[i] = arg.String()
+	}
+	return typeRef.Name + "<" + strings.Join(args, ",") + ">"
+}
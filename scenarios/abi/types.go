@@ -0,0 +1,78 @@
+package scenabi
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const lengthPrefixSize = 4
+
+const addressLength = 32
+
+var errInputTooShort = errors.New("input too short")
+
+// fixedSizes holds the nested-encoded size of all fixed width types.
+var fixedSizes = map[string]int{
+	"u8":           1,
+	"u16":          2,
+	"u32":          4,
+	"u64":          8,
+	"u128":         16,
+	"usize":        4,
+	"i8":           1,
+	"i16":          2,
+	"i32":          4,
+	"i64":          8,
+	"i128":         16,
+	"isize":        4,
+	"bool":         1,
+	"Address":      addressLength,
+	"H256":         32,
+	"CodeMetadata": 2,
+}
+
+// variableTypes are nested-encoded with a 4-byte length prefix.
+var variableTypes = map[string]struct{}{
+	"BigUint":         {},
+	"BigInt":          {},
+	"bytes":           {},
+	"utf-8 string":    {},
+	"TokenIdentifier": {},
+}
+
+func isKnownType(typeName string) bool {
+	if _, isFixed := fixedSizes[typeName]; isFixed {
+		return true
+	}
+	_, isVariable := variableTypes[typeName]
+	return isVariable
+}
+
+func isUnsignedType(typeName string) bool {
+	switch typeName {
+	case "BigUint", "u8", "u16", "u32", "u64", "u128", "usize":
+		return true
+	}
+	return false
+}
+
+func isSignedType(typeName string) bool {
+	switch typeName {
+	case "BigInt", "i8", "i16", "i32", "i64", "i128", "isize":
+		return true
+	}
+	return false
+}
+
+// arrayLength returns the number of items of a fixed-size array type, e.g. 32 for "array32".
+func arrayLength(typeName string) (int, bool) {
+	if !strings.HasPrefix(typeName, "array") {
+		return 0, false
+	}
+	length, err := strconv.Atoi(typeName[len("array"):])
+	if err != nil || length <= 0 {
+		return 0, false
+	}
+	return length, true
+}
@@ -0,0 +1,216 @@
+package scenabi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ValueKind tells how a ValueNode was written.
+type ValueKind int
+
+const (
+	// LeafValue is a plain scenario expression, e.g. "5", "str:abc", "None", or a unit enum variant.
+	LeafValue ValueKind = iota
+
+	// ListValue is written "[a, b, ...]" and is used for lists, tuples and multi-values.
+	ListValue
+
+	// FieldsValue is written "{name: a, ...}" and is used for structs.
+	FieldsValue
+
+	// VariantValue is written "Name(a, ...)" or "Name{field: a, ...}" and is used for enum variants and "Some".
+	VariantValue
+)
+
+// valueDelimiters cannot appear in leaf expressions
+const valueDelimiters = ",()[]{}"
+
+// ValueField is a named value inside a FieldsValue, or inside a VariantValue with named fields.
+type ValueField struct {
+	Name  string
+	Value *ValueNode
+}
+
+// ValueNode is a parsed "abi:" value, before it is matched against a type.
+type ValueNode struct {
+	Kind   ValueKind
+	Text   string
+	Items  []*ValueNode
+	Fields []*ValueField
+}
+
+// ParseValue parses the value part of an "abi:" expression.
+func ParseValue(str string) (*ValueNode, error) {
+	parser := &valueParser{input: str}
+	node, err := parser.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpaces()
+	if !parser.done() {
+		return nil, fmt.Errorf("unexpected %s", parser.input[parser.pos:])
+	}
+	return node, nil
+}
+
+// String formats the value so that ParseValue yields the same value.
+func (node *ValueNode) String() string {
+	switch node.Kind {
+	case ListValue:
+		return "[" + joinItems(node.Items) + "]"
+	case FieldsValue:
+		return "{" + joinFields(node.Fields) + "}"
+	case VariantValue:
+		if node.Fields != nil {
+			return node.Text + "{" + joinFields(node.Fields) + "}"
+		}
+		return node.Text + "(" + joinItems(node.Items) + ")"
+	}
+	return node.Text
+}
+
+func joinItems(items []*ValueNode) string {
+	strs := make([]string, len(items))
+	for i, item := range items {
+		strs[i] = item.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+func joinFields(fields []*ValueField) string {
+	strs := make([]string, len(fields))
+	for i, field := range fields {
+		strs[i] = field.Name + ": " + field.Value.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+type valueParser struct {
+	input string
+	pos   int
+}
+
+func (vp *valueParser) done() bool {
+	return vp.pos >= len(vp.input)
+}
+
+func (vp *valueParser) peek() byte {
+	if vp.done() {
+		return 0
+	}
+	return vp.input[vp.pos]
+}
+
+func (vp *valueParser) skipSpaces() {
+	for !vp.done() && vp.peek() == ' ' {
+		vp.pos++
+	}
+}
+
+func (vp *valueParser) parseValue() (*ValueNode, error) {
+	vp.skipSpaces()
+	switch vp.peek() {
+	case '[':
+		items, err := vp.parseItems('[', ']')
+		return &ValueNode{Kind: ListValue, Items: items}, err
+	case '{':
+		fields, err := vp.parseFields()
+		return &ValueNode{Kind: FieldsValue, Fields: fields}, err
+	}
+
+	start := vp.pos
+	for !vp.done() && !strings.ContainsRune(valueDelimiters, rune(vp.peek())) {
+		vp.pos++
+	}
+	text := strings.TrimSpace(vp.input[start:vp.pos])
+
+	switch vp.peek() {
+	case '(':
+		if len(text) == 0 {
+			return nil, errors.New("missing variant name")
+		}
+		items, err := vp.parseItems('(', ')')
+		if items == nil {
+			items = []*ValueNode{}
+		}
+		return &ValueNode{Kind: VariantValue, Text: text, Items: items}, err
+	case '{':
+		if len(text) == 0 {
+			return nil, errors.New("missing variant name")
+		}
+		fields, err := vp.parseFields()
+		return &ValueNode{Kind: VariantValue, Text: text, Fields: fields}, err
+	}
+
+	return &ValueNode{Kind: LeafValue, Text: text}, nil
+}
+
+func (vp *valueParser) parseItems(open byte, closing byte) ([]*ValueNode, error) {
+	vp.pos++ // open
+	vp.skipSpaces()
+	if vp.peek() == closing {
+		vp.pos++
+		return nil, nil
+	}
+
+	var items []*ValueNode
+	for {
+		item, err := vp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		vp.skipSpaces()
+		switch vp.peek() {
+		case ',':
+			vp.pos++
+		case closing:
+			vp.pos++
+			return items, nil
+		default:
+			return nil, fmt.Errorf("expected ',' or '%c' after %c", closing, open)
+		}
+	}
+}
+
+func (vp *valueParser) parseFields() ([]*ValueField, error) {
+	vp.pos++ // {
+	vp.skipSpaces()
+	fields := make([]*ValueField, 0)
+	if vp.peek() == '}' {
+		vp.pos++
+		return fields, nil
+	}
+
+	for {
+		vp.skipSpaces()
+		colon := strings.IndexByte(vp.input[vp.pos:], ':')
+		if colon < 0 {
+			return nil, errors.New("expected field name followed by ':'")
+		}
+		name := strings.TrimSpace(vp.input[vp.pos : vp.pos+colon])
+		if len(name) == 0 || strings.ContainsAny(name, valueDelimiters) {
+			return nil, fmt.Errorf("invalid field name: %s", name)
+		}
+		vp.pos += colon + 1
+
+		value, err := vp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &ValueField{Name: name, Value: value})
+
+		vp.skipSpaces()
+		switch vp.peek() {
+		case ',':
+			vp.pos++
+		case '}':
+			vp.pos++
+			return fields, nil
+		default:
+			return nil, errors.New("expected ',' or '}' after field")
+		}
+	}
+}
@@ -39,20 +39,26 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string) error {
 		return parseErr
 	}
 
-	return r.Executor.ExecuteScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	err = r.Executor.ExecuteScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	if err != nil {
+		return err
+	}
+
+	if r.RewriteScenarios {
+		return saveModifiedScenario(contextPath, scenario)
+	}
+
+	return nil
 }
 
 // tool to modify scenarios
 // use with extreme caution
-func saveModifiedScenario(toPath string, scenario *mj.Scenario) {
+func saveModifiedScenario(toPath string, scenario *mj.Scenario) error {
 	resultJSON := mjwrite.ScenarioToJSONString(scenario)
 
 	err := os.MkdirAll(filepath.Dir(toPath), os.ModePerm)
 	if err != nil {
-		panic(err)
-	}
-	err = ioutil.WriteFile(toPath, []byte(resultJSON), 0644)
-	if err != nil {
-		panic(err)
+		return err
 	}
+	return ioutil.WriteFile(toPath, []byte(resultJSON), 0644)
 }
@@ -21,6 +21,10 @@ type ScenarioExecutor interface {
 type ScenarioRunner struct {
 	Executor ScenarioExecutor
 	Parser   mjparse.Parser
+
+	// RewriteScenarios causes every successfully executed scenario to be saved back to its file,
+	// including any changes the executor made to it (e.g. generated expectations).
+	RewriteScenarios bool
 }
 
 // NewScenarioRunner creates new ScenarioRunner instance.
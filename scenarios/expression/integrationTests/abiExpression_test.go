@@ -0,0 +1,167 @@
+package scenjsontest
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
+	mei "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/interpreter"
+	mer "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/reconstructor"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `{
+	"name": "Auction",
+	"types": {
+		"Bid": {
+			"type": "struct",
+			"fields": [
+				{ "name": "bidder", "type": "Address" },
+				{ "name": "amount", "type": "BigUint" },
+				{ "name": "token", "type": "Option<TokenIdentifier>" }
+			]
+		},
+		"Status": {
+			"type": "enum",
+			"variants": [
+				{ "name": "Open", "discriminant": 0 },
+				{ "name": "Closed", "discriminant": 1 }
+			]
+		},
+		"Action": {
+			"type": "enum",
+			"variants": [
+				{ "name": "None", "discriminant": 0 },
+				{ "name": "Pay", "discriminant": 1, "fields": [{ "name": "0", "type": "u32" }] },
+				{ "name": "Move", "discriminant": 2, "fields": [{ "name": "x", "type": "i8" }, { "name": "y", "type": "i8" }] }
+			]
+		}
+	},
+	"endpoints": [
+		{
+			"name": "getBids",
+			"inputs": [],
+			"outputs": [{ "type": "Status" }, { "type": "variadic<Bid>" }]
+		}
+	]
+}`
+
+func makeABIInterpreter(t *testing.T) (mei.ExprInterpreter, mer.ExprReconstructor) {
+	abi, err := scenabi.ParseABI([]byte(testABI))
+	require.Nil(t, err)
+	return mei.ExprInterpreter{ABI: abi}, mer.ExprReconstructor{ABI: abi}
+}
+
+func requireABIRoundTrip(t *testing.T, typeName string, value string, expectedHex string) {
+	ei, er := makeABIInterpreter(t)
+	expr := "abi:" + typeName + ":" + value
+	result, err := ei.InterpretString(expr)
+	require.Nil(t, err)
+	require.Equal(t, expectedHex, hex.EncodeToString(result))
+	require.Equal(t, expr, er.ReconstructABI(result, typeName))
+}
+
+func TestABIPrimitives(t *testing.T) {
+	requireABIRoundTrip(t, "u32", "5", "05")
+	requireABIRoundTrip(t, "u64", "0", "")
+	requireABIRoundTrip(t, "i16", "-2", "fe")
+	requireABIRoundTrip(t, "BigInt", "128", "0080")
+	requireABIRoundTrip(t, "bool", "true", "01")
+	requireABIRoundTrip(t, "TokenIdentifier", "str:TOK-123456", hex.EncodeToString([]byte("TOK-123456")))
+	requireABIRoundTrip(t, "usize", "300", "012c")
+	requireABIRoundTrip(t, "isize", "-1", "ff")
+	requireABIRoundTrip(t, "u128", "18446744073709551616", "010000000000000000")
+	requireABIRoundTrip(t, "i128", "-129", "ff7f")
+	requireABIRoundTrip(t, "H256", "0x"+strings.Repeat("ab", 32), strings.Repeat("ab", 32))
+	requireABIRoundTrip(t, "CodeMetadata", "0x0102", "0102")
+
+	ei, _ := makeABIInterpreter(t)
+	_, err := ei.InterpretString("abi:u8:256")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:u8:-1")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:Unknown:1")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:H256:0x01")
+	require.NotNil(t, err)
+}
+
+func TestABIGenerics(t *testing.T) {
+	requireABIRoundTrip(t, "Option<u32>", "None", "")
+	requireABIRoundTrip(t, "Option<u32>", "Some(5)", "0100000005")
+	requireABIRoundTrip(t, "List<u16>", "[1, 2]", "00010002")
+	requireABIRoundTrip(t, "List<List<u8>>", "[[1], []]", "000000010100000000")
+	requireABIRoundTrip(t, "tuple<u8,BigUint>", "[7, 1000]", "070000000203e8")
+	requireABIRoundTrip(t, "array2<u16>", "[1, 2]", "00010002")
+	requireABIRoundTrip(t, "List<array3<u8>>", "[[1, 2, 3], [4, 5, 6]]", "010203040506")
+	requireABIRoundTrip(t, "tuple<usize,u128>", "[1, 2]", "0000000100000000000000000000000000000002")
+
+	ei, _ := makeABIInterpreter(t)
+	_, err := ei.InterpretString("abi:multi<u8,u8>:[1, 2]")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:optional<u8>:Some(1)")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:array2<u8>:[1]")
+	require.NotNil(t, err)
+}
+
+func TestABICustomTypes(t *testing.T) {
+	requireABIRoundTrip(t, "Bid", "{bidder: address:alice, amount: 1000, token: Some(str:TOK-123456)}",
+		hex.EncodeToString([]byte("alice___________________________"))+
+			"0000000203e8"+
+			"01"+"0000000a"+hex.EncodeToString([]byte("TOK-123456")))
+	requireABIRoundTrip(t, "Status", "Open", "")
+	requireABIRoundTrip(t, "Status", "Closed", "01")
+	requireABIRoundTrip(t, "Action", "None", "00")
+	requireABIRoundTrip(t, "Action", "Pay(3)", "0100000003")
+	requireABIRoundTrip(t, "Action", "Move{x: -1, y: 2}", "02ff02")
+	requireABIRoundTrip(t, "List<Status>", "[Closed, Open]", "0100")
+
+	ei, _ := makeABIInterpreter(t)
+	result, err := ei.InterpretString("abi:Bid:{amount: 1000, token: None, bidder: address:alice}")
+	require.Nil(t, err)
+	require.Equal(t, hex.EncodeToString([]byte("alice___________________________"))+"0000000203e800", hex.EncodeToString(result))
+
+	_, err = ei.InterpretString("abi:Bid:{bidder: address:alice, amount: 1000}")
+	require.NotNil(t, err)
+	_, err = ei.InterpretString("abi:Status:Pending")
+	require.NotNil(t, err)
+}
+
+func TestABIMultiValue(t *testing.T) {
+	ei, er := makeABIInterpreter(t)
+
+	values, err := ei.InterpretMultiValue("abi:multi<u8,Status>:[1, Closed]")
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{{1}, {1}}, values)
+
+	values, err = ei.InterpretMultiValue("abi:variadic<u32>:[]")
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{}, values)
+
+	values, err = ei.InterpretMultiValue("abi:optional<u32>:None")
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{}, values)
+
+	values, err = ei.InterpretMultiValue("abi:optional<multi<u8,Status>>:Some([1, Closed])")
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{{1}, {1}}, values)
+
+	values, err = ei.InterpretMultiValue("abi:u32:5")
+	require.Nil(t, err)
+	require.Nil(t, values)
+
+	values, err = ei.InterpretMultiValue("5")
+	require.Nil(t, err)
+	require.Nil(t, values)
+
+	results, ok := er.ReconstructResults([][]byte{{1}, []byte("bob_____________________________\x00\x00\x00\x01\x05\x00")}, "getBids")
+	require.True(t, ok)
+	require.Equal(t, "[abi:Status:Closed, abi:Bid:{bidder: address:bob, amount: 5, token: None}]", results)
+
+	_, ok = er.ReconstructResults([][]byte{{1}, {2}}, "getBids")
+	require.False(t, ok)
+	_, ok = er.ReconstructResults([][]byte{{1}}, "unknownEndpoint")
+	require.False(t, ok)
+}
@@ -564,3 +564,38 @@ func TestInterpretSubTree2(t *testing.T) {
 	expected = append(expected, []byte("field2elem3b")...)
 	require.Equal(t, expected, result)
 }
+
+func TestReconstructExpressionRoundTrip(t *testing.T) {
+	ei := mei.ExprInterpreter{}
+	er := mer.ExprReconstructor{}
+
+	checkRoundTrip := func(value []byte, hint mer.ExprReconstructorHint, expectedExpr string) {
+		expr := er.ReconstructExpression(value, hint)
+		require.Equal(t, expectedExpr, expr)
+		result, err := ei.InterpretString(expr)
+		require.Nil(t, err)
+		require.Equal(t, value, result)
+	}
+
+	checkRoundTrip([]byte{}, mer.NoHint, "")
+	checkRoundTrip([]byte("abc"), mer.NoHint, "str:abc")
+	checkRoundTrip([]byte("abc"), mer.StrHint, "str:abc")
+	checkRoundTrip([]byte{0x05}, mer.NoHint, "5")
+	checkRoundTrip([]byte{0x00, 0x05}, mer.NoHint, "0x0005")
+	checkRoundTrip([]byte{0x01, 0x00}, mer.NumberHint, "256")
+	checkRoundTrip([]byte{0x00, 0x01}, mer.NumberHint, "0x0001")
+	checkRoundTrip([]byte("a\"b"), mer.StrHint, "0x612262")
+
+	addr, _ := ei.InterpretString("address:owner")
+	checkRoundTrip(addr, mer.AddressHint, "address:owner")
+
+	addr, _ = ei.InterpretString("address:owner#05")
+	checkRoundTrip(addr, mer.AddressHint, "address:owner#05")
+
+	addr, _ = ei.InterpretString("sc:contract#0a")
+	checkRoundTrip(addr, mer.AddressHint, "sc:contract#0a")
+
+	addr = make([]byte, 32)
+	addr[20] = 0x01
+	checkRoundTrip(addr, mer.AddressHint, "0x"+hex.EncodeToString(addr))
+}
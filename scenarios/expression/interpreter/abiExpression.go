@@ -0,0 +1,82 @@
+package scenexpressioninterpreter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
+)
+
+const abiPrefix = "abi:"
+
+// InterpretMultiValue resolves "abi:" expressions of multi-value types, i.e. "multi<...>" and "variadic<...>",
+// which stand for several arguments or results.
+// It yields nil for all other expressions, which are resolved by InterpretString.
+func (ei *ExprInterpreter) InterpretMultiValue(strRaw string) ([][]byte, error) {
+	if !strings.HasPrefix(strRaw, abiPrefix) {
+		return nil, nil
+	}
+
+	typeRef, valueNode, err := parseABIExpression(strRaw[len(abiPrefix):])
+	if err != nil {
+		return nil, err
+	}
+	if !typeRef.IsMultiValue() {
+		return nil, nil
+	}
+	values, err := ei.ABI.EncodeMultiValue(typeRef, valueNode, ei.InterpretString)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s: %w", strRaw, err)
+	}
+	return values, nil
+}
+
+// "abi:" expressions are of the form "abi:<type>:<value>",
+// the value syntax is described in scenabi.ValueNode
+func (ei *ExprInterpreter) interpretABI(expr string) ([]byte, error) {
+	typeRef, valueNode, err := parseABIExpression(expr)
+	if err != nil {
+		return []byte{}, err
+	}
+	encoded, err := ei.ABI.Encode(typeRef, valueNode, ei.InterpretString)
+	if err != nil {
+		return []byte{}, fmt.Errorf("cannot encode abi:%s: %w", expr, err)
+	}
+	return encoded, nil
+}
+
+func parseABIExpression(expr string) (*scenabi.TypeRef, *scenabi.ValueNode, error) {
+	separator := findTypeSeparator(expr)
+	if separator < 0 {
+		return nil, nil, errors.New("abi expression must be of the form abi:<type>:<value>")
+	}
+
+	typeRef, err := scenabi.ParseTypeRef(expr[:separator])
+	if err != nil {
+		return nil, nil, err
+	}
+	valueNode, err := scenabi.ParseValue(expr[separator+1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid value for %s: %w", typeRef, err)
+	}
+	return typeRef, valueNode, nil
+}
+
+// the type ends at the first ':' outside of type arguments
+func findTypeSeparator(expr string) int {
+	depth := 0
+	for i, c := range expr {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ':':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -7,6 +7,7 @@ import (
 	"math/big"
 	"strings"
 
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 	fr "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/fileresolver"
 	oj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/orderedjson"
 	twos "github.com/kalyan3104/k-components-big-int/twos-complement"
@@ -35,6 +36,9 @@ const nestedPrefix = "nested:"
 // ExprInterpreter provides context for computing scenario values.
 type ExprInterpreter struct {
 	FileResolver fr.FileResolver
+
+	// ABI provides the custom types for "abi:" expressions, it is optional.
+	ABI *scenabi.ContractABI
 }
 
 // InterpretSubTree attempts to produce a value based on a JSON subtree.
@@ -90,6 +94,7 @@ func (ei *ExprInterpreter) InterpretSubTree(obj oj.OJsonObject) ([]byte, error)
 // - "sc:..." (also an address)
 // - "file:..."
 // - "keccak256:..."
+// - "abi:<type>:<value>", e.g. "abi:Option<u32>:Some(5)"
 // - concatenation using |
 func (ei *ExprInterpreter) InterpretString(strRaw string) ([]byte, error) {
 	if len(strRaw) == 0 {
@@ -109,6 +114,11 @@ func (ei *ExprInterpreter) InterpretString(strRaw string) ([]byte, error) {
 		return fileContents, nil
 	}
 
+	// contract ABI types
+	if strings.HasPrefix(strRaw, abiPrefix) {
+		return ei.interpretABI(strRaw[len(abiPrefix):])
+	}
+
 	// keccak256
 	// TODO: make this part of a proper parser
 	if strings.HasPrefix(strRaw, keccak256Prefix) {
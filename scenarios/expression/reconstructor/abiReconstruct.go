@@ -0,0 +1,75 @@
+package scenexpressionreconstructor
+
+import (
+	"encoding/hex"
+	"strings"
+
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
+)
+
+// ReconstructABI converts the encoding of a value of the given ABI type into an "abi:" expression.
+// If the value cannot be decoded as the given type, it falls back to the untyped representation.
+func (er *ExprReconstructor) ReconstructABI(value []byte, typeName string) string {
+	typeRef, err := scenabi.ParseTypeRef(typeName)
+	if err != nil {
+		return er.Reconstruct(value, NoHint)
+	}
+	expr, ok := er.reconstructABI(value, typeRef)
+	if !ok {
+		return er.Reconstruct(value, NoHint)
+	}
+	return expr
+}
+
+// ReconstructResults formats the results of an endpoint call using the output types from the ABI.
+// It returns false if there is no ABI for the endpoint, or if the results do not match its outputs.
+func (er *ExprReconstructor) ReconstructResults(results [][]byte, endpointName string) (string, bool) {
+	if er.ABI == nil {
+		return "", false
+	}
+	endpoint := er.ABI.Endpoint(endpointName)
+	if endpoint == nil {
+		return "", false
+	}
+	resultTypes, err := er.ABI.ResultTypes(endpoint.Outputs, len(results))
+	if err != nil {
+		return "", false
+	}
+
+	exprs := make([]string, len(results))
+	for i, result := range results {
+		expr, ok := er.reconstructABI(result, resultTypes[i])
+		if !ok {
+			return "", false
+		}
+		exprs[i] = expr
+	}
+	return "[" + strings.Join(exprs, ", ") + "]", true
+}
+
+func (er *ExprReconstructor) reconstructABI(value []byte, typeRef *scenabi.TypeRef) (string, bool) {
+	valueNode, err := er.ABI.Decode(typeRef, value, formatABILeaf)
+	if err != nil {
+		return "", false
+	}
+	return abiPrefix + typeRef.String() + ":" + valueNode.String(), true
+}
+
+const abiPrefix = "abi:"
+
+// leaves must parse back to the same bytes, and must not contain the "abi:" value delimiters
+func formatABILeaf(typeName string, value []byte) string {
+	if typeName == "Address" {
+		addrExpr, ok := addressExpression(value)
+		if ok && scenabi.CanFormatAsString([]byte(addrExpr)) {
+			return addrExpr
+		}
+	}
+	if len(value) == 0 {
+		return "str:"
+	}
+	if canWriteAsString(value) && scenabi.CanFormatAsString(value) {
+		return "str:" + string(value)
+	}
+	return "0x" + hex.EncodeToString(value)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 	ei "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/expression/interpreter"
 )
 
@@ -33,7 +34,10 @@ const (
 const maxBytesInterpretedAsNumber = 15
 
 // ExprReconstructor is a component that attempts to convert raw bytes to a human-readable format.
-type ExprReconstructor struct{}
+type ExprReconstructor struct {
+	// ABI allows reconstructing values of known types as "abi:" expressions, it is optional.
+	ABI *scenabi.ContractABI
+}
 
 func (er *ExprReconstructor) Reconstruct(value []byte, hint ExprReconstructorHint) string {
 	switch hint {
@@ -50,6 +54,39 @@ func (er *ExprReconstructor) Reconstruct(value []byte, hint ExprReconstructorHin
 	}
 }
 
+// ReconstructExpression converts raw bytes into a scenario expression that the interpreter
+// evaluates back to exactly the same bytes, so the result can be written into scenario files.
+// Unlike Reconstruct, it never adds explanatory annotations.
+func (er *ExprReconstructor) ReconstructExpression(value []byte, hint ExprReconstructorHint) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	switch hint {
+	case AddressHint:
+		if addrExpr, ok := addressExpression(value); ok {
+			return addrExpr
+		}
+	case StrHint:
+		if canWriteAsString(value) {
+			return fmt.Sprintf("str:%s", string(value))
+		}
+	case NumberHint:
+		if value[0] != 0 {
+			return fmt.Sprintf("%d", big.NewInt(0).SetBytes(value))
+		}
+	case NoHint:
+		if canWriteAsString(value) {
+			return fmt.Sprintf("str:%s", string(value))
+		}
+		if value[0] != 0 && len(value) < maxBytesInterpretedAsNumber {
+			return fmt.Sprintf("%d", big.NewInt(0).SetBytes(value))
+		}
+	}
+
+	return fmt.Sprintf("0x%s", hex.EncodeToString(value))
+}
+
 func (er *ExprReconstructor) ReconstructFromBigInt(value *big.Int) string {
 	return er.Reconstruct(value.Bytes(), NumberHint)
 }
@@ -113,6 +150,51 @@ func addressPretty(value []byte) string {
 	}
 }
 
+// addressExpression only yields "address:"/"sc:" expressions when they parse back to the same value
+func addressExpression(value []byte) (string, bool) {
+	if len(value) != 32 {
+		return "", false
+	}
+
+	prefix := "address"
+	name := value[:31]
+	leadingZeros := make([]byte, ei.SCAddressNumLeadingZeros)
+	if bytes.Equal(value[:ei.SCAddressNumLeadingZeros], leadingZeros) {
+		prefix = "sc"
+		name = value[ei.SCAddressNumLeadingZeros:31]
+	}
+
+	for _, b := range name {
+		if !isWritableStringByte(b) || b == '#' {
+			return "", false
+		}
+	}
+	nameStr := strings.TrimRight(string(name), "_")
+
+	if value[31] == byte('_') {
+		return fmt.Sprintf("%s:%s", prefix, nameStr), true
+	}
+
+	return fmt.Sprintf("%s:%s#%02x", prefix, nameStr, value[31]), true
+}
+
+// the JSON writer does not escape strings, so quotes and backslashes are avoided
+func canWriteAsString(bytes []byte) bool {
+	if len(bytes) == 0 {
+		return false
+	}
+	for _, b := range bytes {
+		if !isWritableStringByte(b) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWritableStringByte(b byte) bool {
+	return b >= 32 && b <= 126 && b != '"' && b != '\\'
+}
+
 func canInterpretAsString(bytes []byte) bool {
 	if len(bytes) == 0 {
 		return false
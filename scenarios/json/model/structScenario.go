@@ -2,11 +2,12 @@ package scenjsonmodel
 
 // Scenario is a json object representing a test scenario with steps.
 type Scenario struct {
-	Name        string
-	Comment     string
-	CheckGas    bool
-	GasSchedule GasSchedule
-	Steps       []Step
+	Name             string
+	Comment          string
+	CheckGas         bool
+	GasSchedule      GasSchedule
+	EnableEpochFlags []string
+	Steps            []Step
 }
 
 // Step is the basic block of a scenario.
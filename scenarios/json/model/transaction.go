@@ -59,17 +59,25 @@ func (tt TransactionType) HasGas() bool {
 
 // Transaction is a json object representing a transaction.
 type Transaction struct {
-	Type      TransactionType
-	Nonce     JSONUint64
-	Value     JSONBigInt
-	DCDTValue *DCDTTxData
-	From      JSONBytesFromString
-	To        JSONBytesFromString
-	Function  string
-	Code      JSONBytesFromString
-	Arguments []JSONBytesFromTree
-	GasPrice  JSONUint64
-	GasLimit  JSONUint64
+	Type       TransactionType
+	Nonce      JSONUint64
+	Value      JSONBigInt
+	DCDTValue  *DCDTTxData
+	From       JSONBytesFromString
+	To         JSONBytesFromString
+	Function   string
+	Code       JSONBytesFromString
+	Arguments  []JSONBytesFromTree
+	GasPrice   JSONUint64
+	GasLimit   JSONUint64
+	AccessList []*AccessListEntry
+}
+
+// AccessListEntry is a json object declaring the storage keys of an account which a
+// transaction intends to access, so that they are charged at the warm cost.
+type AccessListEntry struct {
+	Address JSONBytesFromString
+	Keys    []JSONBytesFromString
 }
 
 // TransactionResult is a json object representing an expected transaction result.
@@ -91,4 +99,11 @@ type LogEntry struct {
 	Identifier JSONCheckBytes
 	Topics     []JSONCheckBytes
 	Data       JSONCheckBytes
+	Fields     []*LogField
+}
+
+// LogField is an expected log entry field, checked against the log decoded with the contract ABI.
+type LogField struct {
+	Name  string
+	Value JSONCheckBytes
 }
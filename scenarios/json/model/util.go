@@ -36,11 +36,35 @@ func JSONBytesFromStringValues(jbs []JSONBytesFromString) [][]byte {
 	return result
 }
 
-// JSONBytesFromTreeValues extracts values from a slice of JSONBytesFromTree into a list
+// JSONBytesFromTreeValues extracts values from a slice of JSONBytesFromTree into a list.
+// Multi-values are expanded into all the values they stand for.
 func JSONBytesFromTreeValues(jbs []JSONBytesFromTree) [][]byte {
-	result := make([][]byte, len(jbs))
-	for i, jb := range jbs {
-		result[i] = jb.Value
+	result := make([][]byte, 0, len(jbs))
+	for _, jb := range jbs {
+		if jb.MultiValue != nil {
+			result = append(result, jb.MultiValue...)
+			continue
+		}
+		result = append(result, jb.Value)
+	}
+	return result
+}
+
+// ExpandMultiValueChecks yields one check per value, by expanding the multi-values in the list.
+// The expanded checks all keep the original multi-value expression.
+func ExpandMultiValueChecks(jcbs []JSONCheckBytes) []JSONCheckBytes {
+	result := make([]JSONCheckBytes, 0, len(jcbs))
+	for _, jcb := range jcbs {
+		if jcb.MultiValue == nil {
+			result = append(result, jcb)
+			continue
+		}
+		for _, value := range jcb.MultiValue {
+			result = append(result, JSONCheckBytes{
+				Value:    value,
+				Original: jcb.Original,
+			})
+		}
 	}
 	return result
 }
@@ -15,6 +15,9 @@ type JSONCheckBytes struct {
 	IsStar      bool
 	Original    oj.OJsonObject
 	Unspecified bool
+
+	// MultiValue is set when the original is a multi-value expression, standing for several results.
+	MultiValue [][]byte
 }
 
 // JSONCheckBytesUnspecified yields JSONCheckBytes that check that value is empty.
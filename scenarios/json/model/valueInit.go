@@ -27,6 +27,9 @@ func NewJSONBytesFromString(value []byte, originalStr string) JSONBytesFromStrin
 type JSONBytesFromTree struct {
 	Value    []byte
 	Original oj.OJsonObject
+
+	// MultiValue is set when the original is a multi-value expression, standing for several arguments.
+	MultiValue [][]byte
 }
 
 // OriginalEmpty returns true if the object originates from "".
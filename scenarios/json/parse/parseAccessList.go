@@ -0,0 +1,48 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	oj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/orderedjson"
+)
+
+func (p *Parser) processAccessList(accessListRaw oj.OJsonObject) ([]*mj.AccessListEntry, error) {
+	accessList, isList := accessListRaw.(*oj.OJsonList)
+	if !isList {
+		return nil, errors.New("access list is not a list")
+	}
+	var entries []*mj.AccessListEntry
+	var err error
+	for _, entryRaw := range accessList.AsList() {
+		entryMap, isMap := entryRaw.(*oj.OJsonMap)
+		if !isMap {
+			return nil, errors.New("access list entry is not a map")
+		}
+		entry := mj.AccessListEntry{}
+		for _, kvp := range entryMap.OrderedKV {
+			switch kvp.Key {
+			case "address":
+				addressStr, err := p.parseString(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("access list address is not a json string: %w", err)
+				}
+				entry.Address, err = p.parseAccountAddress(addressStr)
+				if err != nil {
+					return nil, err
+				}
+			case "keys":
+				entry.Keys, err = p.parseByteArrayList(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid access list keys: %w", err)
+				}
+			default:
+				return nil, fmt.Errorf("unknown access list field: %s", kvp.Key)
+			}
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
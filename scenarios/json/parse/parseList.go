@@ -46,6 +46,19 @@ func (p *Parser) parseSubTreeList(obj interface{}) ([]mj.JSONBytesFromTree, erro
 	}
 	var result []mj.JSONBytesFromTree
 	for _, elemRaw := range listRaw.AsList() {
+		multiValue, err := p.processMultiValue(elemRaw)
+		if err != nil {
+			return nil, err
+		}
+		if multiValue != nil {
+			result = append(result, mj.JSONBytesFromTree{
+				Value:      []byte{},
+				Original:   elemRaw,
+				MultiValue: multiValue,
+			})
+			continue
+		}
+
 		ba, err := p.processSubTreeAsByteArray(elemRaw)
 		if err != nil {
 			return nil, err
@@ -62,6 +75,19 @@ func (p *Parser) parseCheckBytesList(obj interface{}) ([]mj.JSONCheckBytes, erro
 	}
 	var result []mj.JSONCheckBytes
 	for _, elemRaw := range listRaw.AsList() {
+		multiValue, err := p.processMultiValue(elemRaw)
+		if err != nil {
+			return nil, err
+		}
+		if multiValue != nil {
+			result = append(result, mj.JSONCheckBytes{
+				Value:      []byte{},
+				Original:   elemRaw,
+				MultiValue: multiValue,
+			})
+			continue
+		}
+
 		checkBytes, err := p.parseCheckBytes(elemRaw)
 		if err != nil {
 			return nil, err
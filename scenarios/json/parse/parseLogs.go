@@ -20,7 +20,9 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 		if !isMap {
 			return nil, errors.New("unmarshalled log entry is not a map")
 		}
-		logEntry := mj.LogEntry{}
+		logEntry := mj.LogEntry{
+			Data: mj.JSONCheckBytesUnspecified(),
+		}
 		for _, kvp := range logMap.OrderedKV {
 			switch kvp.Key {
 			case "address":
@@ -43,6 +45,11 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 				if err != nil {
 					return nil, fmt.Errorf("invalid log data: %w", err)
 				}
+			case "fields":
+				logEntry.Fields, err = p.processLogFields(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid log fields: %w", err)
+				}
 			default:
 				return nil, fmt.Errorf("unknown log field: %s", kvp.Key)
 			}
@@ -52,3 +59,23 @@ func (p *Parser) processLogList(logsRaw oj.OJsonObject) ([]*mj.LogEntry, error)
 
 	return logEntries, nil
 }
+
+func (p *Parser) processLogFields(fieldsRaw oj.OJsonObject) ([]*mj.LogField, error) {
+	fieldsMap, isMap := fieldsRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("unmarshalled log fields object is not a map")
+	}
+	var fields []*mj.LogField
+	for _, kvp := range fieldsMap.OrderedKV {
+		value, err := p.parseCheckBytes(kvp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field %s: %w", kvp.Key, err)
+		}
+		fields = append(fields, &mj.LogField{
+			Name:  kvp.Key,
+			Value: value,
+		})
+	}
+
+	return fields, nil
+}
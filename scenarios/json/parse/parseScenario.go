@@ -48,6 +48,11 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*mj.Scenario, error) {
 			if err != nil {
 				return nil, fmt.Errorf("bad scenario gasSchedule: %w", err)
 			}
+		case "enableEpochFlags":
+			scenario.EnableEpochFlags, err = p.processStringList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario enableEpochFlags: %w", err)
+			}
 		case "steps":
 			scenario.Steps, err = p.processScenarioStepList(kvp.Value)
 			if err != nil {
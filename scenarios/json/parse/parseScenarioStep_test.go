@@ -3,6 +3,8 @@ package scenjsonparse
 import (
 	"testing"
 
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	scenjsonwrite "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/write"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,3 +53,61 @@ func TestParseScenario(t *testing.T) {
 	require.NotNil(t, step)
 	require.Equal(t, "scCall", step.StepTypeName())
 }
+
+func TestParseScenario_MultiValueArguments(t *testing.T) {
+	snippet := `
+	{
+		"step": "scCall",
+		"txId": "1",
+		"tx": {
+			"from": "address:owner",
+			"to": "sc:contract",
+			"function": "addAll",
+			"arguments": [
+				"abi:u32:7",
+				"abi:variadic<u8>:[1, 2]",
+				"abi:variadic<u8>:[]"
+			],
+			"gasLimit": "0x100000",
+			"gasPrice": "0x01"
+		},
+		"expect": {
+			"out": [ "abi:multi<u8,bool>:[3, true]" ]
+		}
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+
+	txStep, isTx := step.(*mj.TxStep)
+	require.True(t, isTx)
+	require.Len(t, txStep.Tx.Arguments, 3)
+	require.Equal(t, [][]byte{{7}, {1}, {2}}, mj.JSONBytesFromTreeValues(txStep.Tx.Arguments))
+
+	expectedOut := mj.ExpandMultiValueChecks(txStep.ExpectedResult.Out)
+	require.Len(t, expectedOut, 2)
+	require.True(t, expectedOut[0].Check([]byte{3}))
+	require.True(t, expectedOut[1].Check([]byte{1}))
+}
+
+func TestParseScenarioFile_EnableEpochFlags(t *testing.T) {
+	snippet := `
+	{
+		"name": "flags",
+		"enableEpochFlags": [ "DCDTManagementFlag", "StorageSizeFlag" ],
+		"steps": []
+	}`
+
+	p := Parser{}
+	scenario, parseErr := p.ParseScenarioFile([]byte(snippet))
+	require.Nil(t, parseErr)
+	require.Equal(t, []string{"DCDTManagementFlag", "StorageSizeFlag"}, scenario.EnableEpochFlags)
+
+	rewritten, parseErr := p.ParseScenarioFile([]byte(scenjsonwrite.ScenarioToJSONString(scenario)))
+	require.Nil(t, parseErr)
+	require.Equal(t, scenario.EnableEpochFlags, rewritten.EnableEpochFlags)
+
+	_, parseErr = p.ParseScenarioFile([]byte(`{ "enableEpochFlags": "DCDTManagementFlag" }`))
+	require.NotNil(t, parseErr)
+}
@@ -90,6 +90,14 @@ func (p *Parser) processTx(txType mj.TransactionType, blrRaw oj.OJsonObject) (*m
 			if txType == mj.Transfer && len(blt.Arguments) > 0 {
 				return nil, errors.New("function arguments not allowed for transfer transactions")
 			}
+		case "accessList":
+			if txType != mj.ScCall {
+				return nil, errors.New("`accessList` only allowed in scCall transactions")
+			}
+			blt.AccessList, err = p.processAccessList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid transaction access list: %w", err)
+			}
 		case "contractCode":
 			blt.Code, err = p.processStringAsByteArray(kvp.Value)
 			if err != nil {
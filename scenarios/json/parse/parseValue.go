@@ -125,6 +125,15 @@ func (p *Parser) processStringAsByteArray(obj oj.OJsonObject) (mj.JSONBytesFromS
 	return mj.NewJSONBytesFromString(result, strVal), err
 }
 
+// processMultiValue only yields values for multi-value expressions, which are only allowed in argument and result lists
+func (p *Parser) processMultiValue(obj oj.OJsonObject) ([][]byte, error) {
+	str, isStr := obj.(*oj.OJsonString)
+	if !isStr {
+		return nil, nil
+	}
+	return p.ExprInterpreter.InterpretMultiValue(str.Value)
+}
+
 func (p *Parser) processSubTreeAsByteArray(obj oj.OJsonObject) (mj.JSONBytesFromTree, error) {
 	value, err := p.ExprInterpreter.InterpretSubTree(obj)
 	return mj.JSONBytesFromTree{
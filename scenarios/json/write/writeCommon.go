@@ -65,14 +65,26 @@ func logToOJ(logEntry *mj.LogEntry) oj.OJsonObject {
 	logOJ.Put("address", checkBytesToOJ(logEntry.Address))
 	logOJ.Put("identifier", checkBytesToOJ(logEntry.Identifier))
 
-	var topicsList []oj.OJsonObject
-	for _, topic := range logEntry.Topics {
-		topicsList = append(topicsList, checkBytesToOJ(topic))
+	if len(logEntry.Fields) == 0 || len(logEntry.Topics) > 0 {
+		var topicsList []oj.OJsonObject
+		for _, topic := range logEntry.Topics {
+			topicsList = append(topicsList, checkBytesToOJ(topic))
+		}
+		topicsOJ := oj.OJsonList(topicsList)
+		logOJ.Put("topics", &topicsOJ)
+	}
+
+	if !logEntry.Data.IsUnspecified() {
+		logOJ.Put("data", checkBytesToOJ(logEntry.Data))
 	}
-	topicsOJ := oj.OJsonList(topicsList)
-	logOJ.Put("topics", &topicsOJ)
 
-	logOJ.Put("data", checkBytesToOJ(logEntry.Data))
+	if len(logEntry.Fields) > 0 {
+		fieldsOJ := oj.NewMap()
+		for _, field := range logEntry.Fields {
+			fieldsOJ.Put(field.Name, checkBytesToOJ(field.Value))
+		}
+		logOJ.Put("fields", fieldsOJ)
+	}
 
 	return logOJ
 }
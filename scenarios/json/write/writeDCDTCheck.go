@@ -74,7 +74,7 @@ func appendCheckDCDTInstanceToOJ(dcdtInstance *mj.CheckDCDTInstance, targetOj *o
 		targetOj.Put("hash", checkBytesToOJ(dcdtInstance.Hash))
 	}
 	if !dcdtInstance.Uri.Unspecified && len(dcdtInstance.Uri.Value) > 0 {
-		targetOj.Put("uri", checkBytesToOJ(dcdtInstance.Creator))
+		targetOj.Put("uri", checkBytesToOJ(dcdtInstance.Uri))
 	}
 	if !dcdtInstance.Attributes.Unspecified && len(dcdtInstance.Attributes.Value) > 0 {
 		targetOj.Put("attributes", checkBytesToOJ(dcdtInstance.Attributes))
@@ -0,0 +1,29 @@
+package scenjsonwrite
+
+import (
+	"testing"
+
+	mj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/json/model"
+	oj "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/orderedjson"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendCheckDCDTInstanceToOJ_UriIsNotCreator(t *testing.T) {
+	instance := mj.NewCheckDCDTInstance()
+	instance.Nonce = mj.JSONCheckUint64{Value: 1, Original: "1"}
+	instance.Creator = mj.JSONCheckBytes{
+		Value:    []byte("creator"),
+		Original: &oj.OJsonString{Value: "str:creator"},
+	}
+	instance.Uri = mj.JSONCheckBytes{
+		Value:    []byte("www.token.com"),
+		Original: &oj.OJsonString{Value: "str:www.token.com"},
+	}
+
+	instanceOJ := oj.NewMap()
+	appendCheckDCDTInstanceToOJ(instance, instanceOJ)
+
+	serialized := oj.JSONString(instanceOJ)
+	require.Contains(t, serialized, `"creator": "str:creator"`)
+	require.Contains(t, serialized, `"uri": "str:www.token.com"`)
+}
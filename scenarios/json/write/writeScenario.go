@@ -30,6 +30,15 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 
 	scenarioOJ.Put("gasSchedule", gasScheduleToOJ(scenario.GasSchedule))
 
+	if len(scenario.EnableEpochFlags) > 0 {
+		var flagOJList []oj.OJsonObject
+		for _, flag := range scenario.EnableEpochFlags {
+			flagOJList = append(flagOJList, stringToOJ(flag))
+		}
+		flagsOJ := oj.OJsonList(flagOJList)
+		scenarioOJ.Put("enableEpochFlags", &flagsOJ)
+	}
+
 	var stepOJList []oj.OJsonObject
 
 	for _, generalStep := range scenario.Steps {
@@ -122,6 +131,10 @@ func transactionToScenarioOJ(tx *mj.Transaction) oj.OJsonObject {
 		transactionOJ.Put("arguments", &argOJ)
 	}
 
+	if len(tx.AccessList) > 0 {
+		transactionOJ.Put("accessList", accessListToOJ(tx.AccessList))
+	}
+
 	if tx.Type.HasGas() {
 		transactionOJ.Put("gasLimit", uint64ToOJ(tx.GasLimit))
 		transactionOJ.Put("gasPrice", uint64ToOJ(tx.GasPrice))
@@ -130,6 +143,23 @@ func transactionToScenarioOJ(tx *mj.Transaction) oj.OJsonObject {
 	return transactionOJ
 }
 
+func accessListToOJ(accessList []*mj.AccessListEntry) oj.OJsonObject {
+	var entryList []oj.OJsonObject
+	for _, entry := range accessList {
+		entryOJ := oj.NewMap()
+		entryOJ.Put("address", bytesFromStringToOJ(entry.Address))
+		var keyList []oj.OJsonObject
+		for _, key := range entry.Keys {
+			keyList = append(keyList, bytesFromStringToOJ(key))
+		}
+		keysOJ := oj.OJsonList(keyList)
+		entryOJ.Put("keys", &keysOJ)
+		entryList = append(entryList, entryOJ)
+	}
+	entryOJList := oj.OJsonList(entryList)
+	return &entryOJList
+}
+
 func newAddressMocksToOJ(newAddressMocks []*mj.NewAddressMock) oj.OJsonObject {
 	var namList []oj.OJsonObject
 	for _, namEntry := range newAddressMocks {
@@ -0,0 +1,17 @@
+(module
+  (type (;0;) (func (param i64)))
+  (type (;1;) (func))
+  (import "env" "int64finish" (func $int64finish (type 0)))
+  (memory (;0;) 1)
+  (global $counter (mut i64) (i64.const 0))
+  (export "memory" (memory 0))
+  (export "init" (func $init))
+  (export "increment" (func $increment))
+  (func $init (type 1))
+  (func $increment (type 1)
+    global.get $counter
+    i64.const 1
+    i64.add
+    global.set $counter
+    global.get $counter
+    call $int64finish))
@@ -0,0 +1,160 @@
+(module
+  (type (;0;) (func (param i32 i32) (result i32)))
+  (type (;1;) (func (param i32) (result i32)))
+  (type (;2;) (func (param i32) (result i64)))
+  (type (;3;) (func (param i64)))
+  (type (;4;) (func (param i32 i32 i32) (result i32)))
+  (type (;5;) (func (param i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32 i32) (result i64)))
+  (type (;6;) (func (param i32 i32 i64 i32) (result i32)))
+  (type (;7;) (func (param i32 i32 i64 i32 i32) (result i32)))
+  (type (;8;) (func (param i32 i32) (result i64)))
+  (type (;9;) (func (result i32)))
+  (type (;10;) (func (param i32)))
+  (type (;11;) (func))
+  (import "env" "getArgument" (func $getArgument (type 0)))
+  (import "env" "getArgumentLength" (func $getArgumentLength (type 1)))
+  (import "env" "int64getArgument" (func $int64getArgument (type 2)))
+  (import "env" "int64finish" (func $int64finish (type 3)))
+  (import "env" "dcdtLocalMint" (func $dcdtLocalMint (type 4)))
+  (import "env" "dcdtLocalBurn" (func $dcdtLocalBurn (type 4)))
+  (import "env" "dcdtNFTCreate" (func $dcdtNFTCreate (type 5)))
+  (import "env" "dcdtNFTAddQuantity" (func $dcdtNFTAddQuantity (type 6)))
+  (import "env" "dcdtNFTBurn" (func $dcdtNFTBurn (type 6)))
+  (import "env" "dcdtNFTUpdateAttributes" (func $dcdtNFTUpdateAttributes (type 7)))
+  (import "env" "getDCDTLocalRoles" (func $getDCDTLocalRoles (type 8)))
+  (memory (;0;) 1)
+  (export "memory" (memory 0))
+  (export "init" (func $init))
+  (export "localMint" (func $localMint))
+  (export "localBurn" (func $localBurn))
+  (export "nftCreate" (func $nftCreate))
+  (export "nftAddQuantity" (func $nftAddQuantity))
+  (export "nftBurn" (func $nftBurn))
+  (export "nftUpdateAttributes" (func $nftUpdateAttributes))
+  (export "getLocalRoles" (func $getLocalRoles))
+  (func $loadTokenID (type 9) (result i32)
+    i32.const 0
+    i32.const 0
+    call $getArgument)
+  (func $loadValue (type 10) (param $arg i32)
+    i32.const 64
+    i64.const 0
+    i64.store
+    i32.const 64
+    i64.const 0
+    i64.store offset=8
+    i32.const 64
+    i64.const 0
+    i64.store offset=16
+    i32.const 64
+    i64.const 0
+    i64.store offset=24
+    local.get $arg
+    i32.const 96
+    local.get $arg
+    call $getArgumentLength
+    i32.sub
+    call $getArgument
+    drop)
+  (func $init (type 11))
+  (func $localMint (type 11) (local $tokenLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 1
+    call $loadValue
+    i32.const 0
+    local.get $tokenLength
+    i32.const 64
+    call $dcdtLocalMint
+    drop)
+  (func $localBurn (type 11) (local $tokenLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 1
+    call $loadValue
+    i32.const 0
+    local.get $tokenLength
+    i32.const 64
+    call $dcdtLocalBurn
+    drop)
+  (func $nftCreate (type 11) (local $tokenLength i32) (local $nameLength i32) (local $attributesLength i32) (local $uriLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 1
+    call $loadValue
+    i32.const 2
+    i32.const 128
+    call $getArgument
+    local.set $nameLength
+    i32.const 3
+    i32.const 256
+    call $getArgument
+    local.set $attributesLength
+    i32.const 4
+    i32.const 520
+    call $getArgument
+    local.set $uriLength
+    i32.const 512
+    local.get $uriLength
+    i32.store
+    i32.const 0
+    local.get $tokenLength
+    i32.const 64
+    i32.const 128
+    local.get $nameLength
+    i32.const 0
+    i32.const 0
+    i32.const 0
+    i32.const 256
+    local.get $attributesLength
+    i32.const 1
+    i32.const 512
+    i32.const 520
+    call $dcdtNFTCreate
+    call $int64finish)
+  (func $nftAddQuantity (type 11) (local $tokenLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 2
+    call $loadValue
+    i32.const 0
+    local.get $tokenLength
+    i32.const 1
+    call $int64getArgument
+    i32.const 64
+    call $dcdtNFTAddQuantity
+    drop)
+  (func $nftBurn (type 11) (local $tokenLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 2
+    call $loadValue
+    i32.const 0
+    local.get $tokenLength
+    i32.const 1
+    call $int64getArgument
+    i32.const 64
+    call $dcdtNFTBurn
+    drop)
+  (func $nftUpdateAttributes (type 11) (local $tokenLength i32) (local $attributesLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 2
+    i32.const 256
+    call $getArgument
+    local.set $attributesLength
+    i32.const 0
+    local.get $tokenLength
+    i32.const 1
+    call $int64getArgument
+    i32.const 256
+    local.get $attributesLength
+    call $dcdtNFTUpdateAttributes
+    drop)
+  (func $getLocalRoles (type 11) (local $tokenLength i32)
+    call $loadTokenID
+    local.set $tokenLength
+    i32.const 0
+    local.get $tokenLength
+    call $getDCDTLocalRoles
+    call $int64finish))
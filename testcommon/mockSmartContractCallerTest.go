@@ -3,6 +3,7 @@ package testcommon
 import (
 	"testing"
 
+	"github.com/kalyan3104/k-chain-core-go/core"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	mock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
 	worldmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
@@ -19,6 +20,8 @@ type testTemplateConfig struct {
 type MockInstancesTestTemplate struct {
 	testTemplateConfig
 	contracts     *[]MockTestSmartContract
+	disabledFlags []core.EnableEpochFlag
+	asQuery       bool
 	setup         func(vmhost.VMHost, *worldmock.MockWorld)
 	assertResults func(*worldmock.MockWorld, *VMOutputVerifier)
 }
@@ -46,6 +49,18 @@ func (callerTest *MockInstancesTestTemplate) WithInput(input *vmcommon.ContractC
 	return callerTest
 }
 
+// WithDisabledFlags disables the given flags for the mock contract call test
+func (callerTest *MockInstancesTestTemplate) WithDisabledFlags(flags ...core.EnableEpochFlag) *MockInstancesTestTemplate {
+	callerTest.disabledFlags = flags
+	return callerTest
+}
+
+// AsQuery runs the mock contract call test as a query instead of a transaction
+func (callerTest *MockInstancesTestTemplate) AsQuery() *MockInstancesTestTemplate {
+	callerTest.asQuery = true
+	return callerTest
+}
+
 // WithSetup provides the setup function to be used by the mock contract call test
 func (callerTest *MockInstancesTestTemplate) WithSetup(setup func(vmhost.VMHost, *worldmock.MockWorld)) *MockInstancesTestTemplate {
 	callerTest.setup = setup
@@ -60,7 +75,7 @@ func (callerTest *MockInstancesTestTemplate) AndAssertResults(assertResults func
 
 func (callerTest *MockInstancesTestTemplate) runTest() {
 
-	host, world, imb := DefaultTestVMForCallWithInstanceMocks(callerTest.t)
+	host, world, imb := DefaultTestVMForCallWithInstanceMocksAndDisabledFlags(callerTest.t, callerTest.disabledFlags...)
 
 	for _, mockSC := range *callerTest.contracts {
 		mockSC.initialize(callerTest.t, host, imb)
@@ -70,7 +85,13 @@ func (callerTest *MockInstancesTestTemplate) runTest() {
 	// create snapshot (normaly done by node)
 	world.CreateStateBackup()
 
-	vmOutput, err := host.RunSmartContractCall(callerTest.input)
+	var vmOutput *vmcommon.VMOutput
+	var err error
+	if callerTest.asQuery {
+		vmOutput, err = host.RunSmartContractQuery(callerTest.input)
+	} else {
+		vmOutput, err = host.RunSmartContractCall(callerTest.input)
+	}
 
 	allErrors := host.Runtime().GetAllErrors()
 	verify := NewVMOutputVerifierWithAllErrors(callerTest.t, vmOutput, err, allErrors)
@@ -60,6 +60,12 @@ var DCDTTestTokenName = []byte("TT")
 // DCDTTestTokenKey is an exposed value to use in tests
 var DCDTTestTokenKey = worldmock.MakeTokenKey(DCDTTestTokenName, 0)
 
+// DCDTTestSecondTokenName is an exposed value to use in tests which transfer several tokens
+var DCDTTestSecondTokenName = []byte("TT2")
+
+// DCDTTestSecondTokenKey is an exposed value to use in tests which transfer several tokens
+var DCDTTestSecondTokenKey = worldmock.MakeTokenKey(DCDTTestSecondTokenName, 0)
+
 // DefaultCodeMetadata is an exposed value to use in tests
 var DefaultCodeMetadata = []byte{3, 0}
 
@@ -183,8 +189,14 @@ func DefaultTestVMForCallWithInstanceRecorderMock(tb testing.TB, code []byte, ba
 
 // DefaultTestVMForCallWithInstanceMocks creates an InstanceBuilderMock
 func DefaultTestVMForCallWithInstanceMocks(tb testing.TB) (vmhost.VMHost, *worldmock.MockWorld, *contextmock.InstanceBuilderMock) {
+	return DefaultTestVMForCallWithInstanceMocksAndDisabledFlags(tb)
+}
+
+// DefaultTestVMForCallWithInstanceMocksAndDisabledFlags creates an InstanceBuilderMock for a host
+// which has the given flags disabled
+func DefaultTestVMForCallWithInstanceMocksAndDisabledFlags(tb testing.TB, disabledFlags ...core.EnableEpochFlag) (vmhost.VMHost, *worldmock.MockWorld, *contextmock.InstanceBuilderMock) {
 	world := worldmock.NewMockWorld()
-	host := DefaultTestVM(tb, world)
+	host := DefaultTestVMWithDisabledFlags(tb, world, disabledFlags...)
 
 	instanceBuilderMock := contextmock.NewInstanceBuilderMock(world)
 	host.Runtime().ReplaceInstanceBuilder(instanceBuilderMock)
@@ -297,6 +309,16 @@ func defaultTestVMForContracts(
 
 // DefaultTestVMWithWorldMock creates a host configured with a mock world
 func DefaultTestVMWithWorldMock(tb testing.TB) (vmhost.VMHost, *worldmock.MockWorld) {
+	return defaultTestVMWithWorldMock(tb, false)
+}
+
+// DefaultTestVMWithWorldMockAndWarmInstances creates a host configured with a mock world,
+// which reuses the Wasmer instances of contracts with the same code
+func DefaultTestVMWithWorldMockAndWarmInstances(tb testing.TB) (vmhost.VMHost, *worldmock.MockWorld) {
+	return defaultTestVMWithWorldMock(tb, true)
+}
+
+func defaultTestVMWithWorldMock(tb testing.TB, useWarmInstance bool) (vmhost.VMHost, *worldmock.MockWorld) {
 	world := worldmock.NewMockWorld()
 	gasSchedule := customGasSchedule
 	if gasSchedule == nil {
@@ -311,11 +333,9 @@ func DefaultTestVMWithWorldMock(tb testing.TB) (vmhost.VMHost, *worldmock.MockWo
 		GasSchedule:          gasSchedule,
 		BuiltInFuncContainer: world.BuiltinFuncs.Container,
 		ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
-		UseWarmInstance:      false,
+		UseWarmInstance:      useWarmInstance,
 		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-			},
+			IsFlagEnabledCalled: hostCore.IsKnownFlag,
 		},
 	})
 	require.Nil(tb, err)
@@ -326,6 +346,12 @@ func DefaultTestVMWithWorldMock(tb testing.TB) (vmhost.VMHost, *worldmock.MockWo
 
 // DefaultTestVM creates a host configured with a configured blockchain hook
 func DefaultTestVM(tb testing.TB, blockchain vmcommon.BlockchainHook) vmhost.VMHost {
+	return DefaultTestVMWithDisabledFlags(tb, blockchain)
+}
+
+// DefaultTestVMWithDisabledFlags creates a host configured with a configured blockchain hook,
+// which has the given flags disabled
+func DefaultTestVMWithDisabledFlags(tb testing.TB, blockchain vmcommon.BlockchainHook, disabledFlags ...core.EnableEpochFlag) vmhost.VMHost {
 	gasSchedule := customGasSchedule
 	if gasSchedule == nil {
 		gasSchedule = config.MakeGasMapForTests()
@@ -340,7 +366,12 @@ func DefaultTestVM(tb testing.TB, blockchain vmcommon.BlockchainHook) vmhost.VMH
 		UseWarmInstance:      false,
 		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
 			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
+				for _, disabledFlag := range disabledFlags {
+					if flag == disabledFlag {
+						return false
+					}
+				}
+				return hostCore.IsKnownFlag(flag)
 			},
 		},
 	})
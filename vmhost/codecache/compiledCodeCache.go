@@ -0,0 +1,321 @@
+package codecache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	logger "github.com/kalyan3104/k-chain-logger-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+var log = logger.GetOrCreate("vm/codecache")
+
+var _ vmhost.CompiledCodeCache = (*compiledCodeCache)(nil)
+
+const entryFileExtension = ".bin"
+const tempFileExtension = ".tmp"
+
+// entryMagic marks the files written by the cache; the checksum covers the key and the compiled code
+var entryMagic = []byte("VMCC")
+
+const entryHeaderLength = 4 + sha256.Size
+
+// ArgsCompiledCodeCache holds the arguments needed to create a compiled code cache
+type ArgsCompiledCodeCache struct {
+	// MaxSizeInBytes bounds the total size of the compiled code held in memory and on disk
+	MaxSizeInBytes uint64
+	// Directory enables persistence across restarts, if not empty
+	Directory string
+}
+
+// Metrics holds the counters of the compiled code cache
+type Metrics struct {
+	Hits             uint64
+	Misses           uint64
+	Evictions        uint64
+	CorruptedEntries uint64
+	SizeInBytes      uint64
+}
+
+type cacheEntry struct {
+	key          string
+	compiledCode []byte
+}
+
+type compiledCodeCache struct {
+	mutCache    sync.Mutex
+	lru         *list.List
+	entries     map[string]*list.Element
+	sizeInBytes uint64
+	maxSize     uint64
+	directory   string
+	metrics     Metrics
+}
+
+// NewCompiledCodeCache creates a new size-bounded LRU cache of compiled contract code.
+// If a directory is given, entries are also written to disk and survive restarts. The files
+// on disk always mirror the entries held in memory: files left over from previous runs are
+// loaded newest first, as long as they fit in the size bound, and the others are removed.
+func NewCompiledCodeCache(args ArgsCompiledCodeCache) (*compiledCodeCache, error) {
+	if args.MaxSizeInBytes == 0 {
+		return nil, ErrInvalidMaxSize
+	}
+
+	cache := &compiledCodeCache{
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element),
+		maxSize:   args.MaxSizeInBytes,
+		directory: args.Directory,
+	}
+
+	if len(cache.directory) > 0 {
+		err := os.MkdirAll(cache.directory, 0755)
+		if err != nil {
+			return nil, err
+		}
+
+		err = cache.loadDirectory()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cache, nil
+}
+
+// Get returns the compiled code saved under the given key, looking on disk if it is not held in memory.
+// Persisted entries that fail the integrity check are removed and reported as missing.
+func (cache *compiledCodeCache) Get(key []byte) ([]byte, bool) {
+	cache.mutCache.Lock()
+	defer cache.mutCache.Unlock()
+
+	element, found := cache.entries[string(key)]
+	if found {
+		cache.lru.MoveToFront(element)
+		cache.metrics.Hits++
+		return element.Value.(*cacheEntry).compiledCode, true
+	}
+
+	compiledCode, found := cache.loadEntry(key)
+	if !found {
+		cache.metrics.Misses++
+		return nil, false
+	}
+
+	cache.metrics.Hits++
+	cache.addEntry(key, compiledCode)
+
+	return compiledCode, true
+}
+
+// Put saves the compiled code under the given key, evicting the least recently used entries if needed.
+func (cache *compiledCodeCache) Put(key []byte, compiledCode []byte) {
+	if uint64(len(compiledCode)) > cache.maxSize {
+		log.Trace("compiled code too large for cache", "size", len(compiledCode))
+		return
+	}
+
+	cache.mutCache.Lock()
+	defer cache.mutCache.Unlock()
+
+	element, found := cache.entries[string(key)]
+	if found {
+		cache.lru.MoveToFront(element)
+		return
+	}
+
+	cache.addEntry(key, compiledCode)
+
+	err := cache.saveEntry(key, compiledCode)
+	if err != nil {
+		log.Warn("compiled code cache", "error", err)
+	}
+}
+
+// GetMetrics returns the hit, miss and eviction counters of the cache
+func (cache *compiledCodeCache) GetMetrics() Metrics {
+	cache.mutCache.Lock()
+	defer cache.mutCache.Unlock()
+
+	metrics := cache.metrics
+	metrics.SizeInBytes = cache.sizeInBytes
+	return metrics
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (cache *compiledCodeCache) IsInterfaceNil() bool {
+	return cache == nil
+}
+
+// addEntry adds the entry as the most recently used one, evicting the least recently used
+// entries, together with their files, to fit the size bound
+func (cache *compiledCodeCache) addEntry(key []byte, compiledCode []byte) {
+	entry := &cacheEntry{
+		key:          string(key),
+		compiledCode: compiledCode,
+	}
+	cache.entries[entry.key] = cache.lru.PushFront(entry)
+	cache.sizeInBytes += uint64(len(compiledCode))
+
+	for cache.sizeInBytes > cache.maxSize {
+		oldest := cache.lru.Back()
+		oldestEntry := oldest.Value.(*cacheEntry)
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldestEntry.key)
+		cache.sizeInBytes -= uint64(len(oldestEntry.compiledCode))
+		cache.metrics.Evictions++
+		cache.removeEntryFile([]byte(oldestEntry.key))
+	}
+}
+
+func (cache *compiledCodeCache) entryPath(key []byte) string {
+	return filepath.Join(cache.directory, hex.EncodeToString(key)+entryFileExtension)
+}
+
+func (cache *compiledCodeCache) loadEntry(key []byte) ([]byte, bool) {
+	if len(cache.directory) == 0 {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(cache.entryPath(key))
+	if err != nil {
+		return nil, false
+	}
+
+	compiledCode, err := decodeEntry(key, data)
+	if err != nil {
+		log.Warn("compiled code cache", "key", hex.EncodeToString(key), "error", err)
+		cache.metrics.CorruptedEntries++
+		cache.removeEntryFile(key)
+		return nil, false
+	}
+	if uint64(len(compiledCode)) > cache.maxSize {
+		return nil, false
+	}
+
+	return compiledCode, true
+}
+
+// saveEntry writes the entry to a temporary file first, so that a crash never leaves a partial entry behind.
+func (cache *compiledCodeCache) saveEntry(key []byte, compiledCode []byte) error {
+	if len(cache.directory) == 0 {
+		return nil
+	}
+
+	path := cache.entryPath(key)
+	tempPath := path + tempFileExtension
+	err := os.WriteFile(tempPath, encodeEntry(key, compiledCode), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
+
+func (cache *compiledCodeCache) removeEntryFile(key []byte) {
+	if len(cache.directory) == 0 {
+		return
+	}
+
+	err := os.Remove(cache.entryPath(key))
+	if err != nil && !os.IsNotExist(err) {
+		log.Warn("compiled code cache", "error", err)
+	}
+}
+
+// loadDirectory removes unfinished writes, loads the newest entries which fit in the size bound
+// as the most recently used ones, and removes the others, so that the directory never holds
+// entries unknown to the LRU, which would never be evicted.
+func (cache *compiledCodeCache) loadDirectory() error {
+	dirEntries, err := os.ReadDir(cache.directory)
+	if err != nil {
+		return err
+	}
+
+	entryFiles := make([]os.FileInfo, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(dirEntry.Name(), tempFileExtension) {
+			_ = os.Remove(filepath.Join(cache.directory, dirEntry.Name()))
+			continue
+		}
+		if !strings.HasSuffix(dirEntry.Name(), entryFileExtension) {
+			continue
+		}
+		fileInfo, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entryFiles = append(entryFiles, fileInfo)
+	}
+
+	sort.Slice(entryFiles, func(i, j int) bool {
+		return entryFiles[i].ModTime().After(entryFiles[j].ModTime())
+	})
+
+	for _, fileInfo := range entryFiles {
+		path := filepath.Join(cache.directory, fileInfo.Name())
+		key, err := hex.DecodeString(strings.TrimSuffix(fileInfo.Name(), entryFileExtension))
+		if err != nil {
+			_ = os.Remove(path)
+			continue
+		}
+
+		compiledCode, found := cache.loadEntry(key)
+		if !found {
+			_ = os.Remove(path)
+			continue
+		}
+		if cache.sizeInBytes+uint64(len(compiledCode)) > cache.maxSize {
+			_ = os.Remove(path)
+			continue
+		}
+
+		entry := &cacheEntry{
+			key:          string(key),
+			compiledCode: compiledCode,
+		}
+		cache.entries[entry.key] = cache.lru.PushBack(entry)
+		cache.sizeInBytes += uint64(len(compiledCode))
+	}
+
+	return nil
+}
+
+func entryChecksum(key []byte, compiledCode []byte) []byte {
+	hasher := sha256.New()
+	_, _ = hasher.Write(key)
+	_, _ = hasher.Write(compiledCode)
+	return hasher.Sum(nil)
+}
+
+func encodeEntry(key []byte, compiledCode []byte) []byte {
+	data := make([]byte, 0, entryHeaderLength+len(compiledCode))
+	data = append(data, entryMagic...)
+	data = append(data, entryChecksum(key, compiledCode)...)
+	data = append(data, compiledCode...)
+	return data
+}
+
+func decodeEntry(key []byte, data []byte) ([]byte, error) {
+	if len(data) < entryHeaderLength || !bytes.Equal(data[:len(entryMagic)], entryMagic) {
+		return nil, ErrCorruptedEntry
+	}
+
+	checksum := data[len(entryMagic):entryHeaderLength]
+	compiledCode := data[entryHeaderLength:]
+	if !bytes.Equal(checksum, entryChecksum(key, compiledCode)) {
+		return nil, ErrCorruptedEntry
+	}
+
+	return compiledCode, nil
+}
@@ -0,0 +1,125 @@
+package codecache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCompiledCodeCache_InvalidSize(t *testing.T) {
+	cache, err := NewCompiledCodeCache(ArgsCompiledCodeCache{})
+	require.Nil(t, cache)
+	require.Equal(t, ErrInvalidMaxSize, err)
+}
+
+func TestCompiledCodeCache_GetPut(t *testing.T) {
+	cache, err := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 100})
+	require.Nil(t, err)
+	require.False(t, cache.IsInterfaceNil())
+
+	_, found := cache.Get([]byte("key"))
+	require.False(t, found)
+
+	cache.Put([]byte("key"), []byte("compiled"))
+	compiledCode, found := cache.Get([]byte("key"))
+	require.True(t, found)
+	require.Equal(t, []byte("compiled"), compiledCode)
+
+	metrics := cache.GetMetrics()
+	require.Equal(t, uint64(1), metrics.Hits)
+	require.Equal(t, uint64(1), metrics.Misses)
+	require.Equal(t, uint64(len("compiled")), metrics.SizeInBytes)
+}
+
+func TestCompiledCodeCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 10})
+
+	cache.Put([]byte("a"), []byte("aaaa"))
+	cache.Put([]byte("b"), []byte("bbbb"))
+	_, _ = cache.Get([]byte("a"))
+	cache.Put([]byte("c"), []byte("cccc"))
+
+	_, found := cache.Get([]byte("b"))
+	require.False(t, found)
+	_, found = cache.Get([]byte("a"))
+	require.True(t, found)
+	_, found = cache.Get([]byte("c"))
+	require.True(t, found)
+	require.Equal(t, uint64(1), cache.GetMetrics().Evictions)
+
+	cache.Put([]byte("large"), make([]byte, 11))
+	_, found = cache.Get([]byte("large"))
+	require.False(t, found)
+}
+
+func TestCompiledCodeCache_Persistence(t *testing.T) {
+	directory := t.TempDir()
+
+	cache, err := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 100, Directory: directory})
+	require.Nil(t, err)
+	cache.Put([]byte("key"), []byte("compiled"))
+
+	restarted, err := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 100, Directory: directory})
+	require.Nil(t, err)
+	compiledCode, found := restarted.Get([]byte("key"))
+	require.True(t, found)
+	require.Equal(t, []byte("compiled"), compiledCode)
+}
+
+func TestCompiledCodeCache_CorruptedEntry(t *testing.T) {
+	directory := t.TempDir()
+
+	cache, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 100, Directory: directory})
+	cache.Put([]byte("key"), []byte("compiled"))
+
+	path := cache.entryPath([]byte("key"))
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	data[len(data)-1] ^= 0xff
+	require.Nil(t, os.WriteFile(path, data, 0644))
+
+	restarted, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 100, Directory: directory})
+	_, found := restarted.Get([]byte("key"))
+	require.False(t, found)
+	require.Equal(t, uint64(1), restarted.GetMetrics().CorruptedEntries)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestCompiledCodeCache_PrunesDirectory(t *testing.T) {
+	directory := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(directory, "partial"+entryFileExtension+tempFileExtension), []byte("x"), 0644))
+
+	cache, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 1000, Directory: directory})
+	cache.Put([]byte("a"), make([]byte, 400))
+	cache.Put([]byte("b"), make([]byte, 400))
+
+	_, err := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 500, Directory: directory})
+	require.Nil(t, err)
+
+	fileInfos, err := os.ReadDir(directory)
+	require.Nil(t, err)
+	require.Len(t, fileInfos, 1)
+}
+
+func TestCompiledCodeCache_EvictsPersistedEntriesOfPreviousRuns(t *testing.T) {
+	directory := t.TempDir()
+	cache, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 1000, Directory: directory})
+	cache.Put([]byte("a"), make([]byte, 400))
+	cache.Put([]byte("b"), make([]byte, 400))
+
+	restarted, _ := NewCompiledCodeCache(ArgsCompiledCodeCache{MaxSizeInBytes: 1000, Directory: directory})
+	require.Equal(t, uint64(800), restarted.GetMetrics().SizeInBytes)
+	restarted.Put([]byte("c"), make([]byte, 400))
+	restarted.Put([]byte("d"), make([]byte, 400))
+	restarted.Put([]byte("e"), make([]byte, 400))
+
+	fileInfos, err := os.ReadDir(directory)
+	require.Nil(t, err)
+	require.Len(t, fileInfos, 2)
+	_, found := restarted.Get([]byte("e"))
+	require.True(t, found)
+}
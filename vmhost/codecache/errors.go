@@ -0,0 +1,9 @@
+package codecache
+
+import "errors"
+
+// ErrInvalidMaxSize signals that the cache was configured with a zero size
+var ErrInvalidMaxSize = errors.New("invalid compiled code cache size")
+
+// ErrCorruptedEntry signals that a persisted entry failed the integrity check
+var ErrCorruptedEntry = errors.New("corrupted compiled code cache entry")
@@ -65,6 +65,9 @@ const TimeLockKeyPrefix = ProtectedStoragePrefix + "TIMELOCK"
 // AsyncDataPrefix is the storage key prefix used for AsyncContext-related storage.
 const AsyncDataPrefix = ProtectedStoragePrefix + "ASYNC"
 
+// MaxStorageIterationLimit is the maximum number of storage entries returned by a single storage iteration
+const MaxStorageIterationLimit = 1000
+
 // AsyncCallStatus represents the different status an async call can have
 type AsyncCallStatus uint8
 
@@ -100,6 +103,36 @@ const (
 	UpgradeFunctionName = "upgradeContract"
 )
 
+// DCDTLocalRole is a bit of the mask returned to contracts by getDCDTLocalRoles,
+// signifying that the contract holds the corresponding DCDT role for a token
+type DCDTLocalRole int64
+
+const (
+	// RoleMint signifies the local mint role
+	RoleMint DCDTLocalRole = 1 << iota
+
+	// RoleBurn signifies the local burn role
+	RoleBurn
+
+	// RoleNFTCreate signifies the NFT create role
+	RoleNFTCreate
+
+	// RoleNFTAddQuantity signifies the NFT add quantity role
+	RoleNFTAddQuantity
+
+	// RoleNFTBurn signifies the NFT burn role
+	RoleNFTBurn
+
+	// RoleNFTAddURI signifies the NFT add URI role
+	RoleNFTAddURI
+
+	// RoleNFTUpdateAttributes signifies the NFT update attributes role
+	RoleNFTUpdateAttributes
+
+	// RoleTransfer signifies the transfer role
+	RoleTransfer
+)
+
 // CodeDeployInput contains code deploy state, whether it comes from a ContractCreateInput or a ContractCallInput
 type CodeDeployInput struct {
 	ContractCode         []byte
@@ -108,6 +141,16 @@ type CodeDeployInput struct {
 	CodeDeployerAddress  []byte
 }
 
+// StorageAccessListEntry declares the storage keys of an account which a call intends to access
+type StorageAccessListEntry struct {
+	Address []byte
+	Keys    [][]byte
+}
+
+// StorageAccessList declares the storage keys a call intends to access; these are pre-warmed at
+// the start of the call, so that subsequent accesses to them are charged at the warm cost
+type StorageAccessList []*StorageAccessListEntry
+
 // VMHostParameters represents the parameters to be passed to VMHost
 type VMHostParameters struct {
 	VMType                   []byte
@@ -118,6 +161,20 @@ type VMHostParameters struct {
 	WasmerSIGSEGVPassthrough bool
 	UseWarmInstance          bool
 	EnableEpochsHandler      EnableEpochsHandler
+
+	// CompiledCodeCacheSize enables the host-side compiled code cache, if not zero;
+	// otherwise compiled code is stored through the blockchain hook
+	CompiledCodeCacheSize      uint64
+	CompiledCodeCacheDirectory string
+}
+
+// WarmInstancePoolMetrics holds the counters of the warm Wasmer instance pool
+type WarmInstancePoolMetrics struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Discards      uint64
+	IdleInstances uint64
 }
 
 // AsyncCallInfo contains the information required to handle the asynchronous call of another SmartContract
@@ -164,6 +221,7 @@ type AsyncGeneratedCall struct {
 	SuccessCallback string
 	ErrorCallback   string
 	ProvidedGas     uint64
+	GasLocked       uint64
 }
 
 // AsyncContext is a structure containing a group of async calls and a callback
@@ -199,7 +257,7 @@ func (ac *AsyncGeneratedCall) GetGasLimit() uint64 {
 
 // GetGasLocked returns the gas locked for the async callback
 func (ac *AsyncGeneratedCall) GetGasLocked() uint64 {
-	return 0
+	return ac.GasLocked
 }
 
 // GetValueBytes returns the byte representation of the value of the async call
@@ -1,8 +1,10 @@
 package contexts
 
 import (
+	"bytes"
 	"math/big"
 
+	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/data/dcdt"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
@@ -289,9 +291,47 @@ func (context *blockchainContext) GetUserAccount(address []byte) (vmcommon.UserA
 	return context.blockChainHook.GetUserAccount(address)
 }
 
-// ProcessBuiltInFunction will process the builtIn function for the created input
+// ProcessBuiltInFunction will process the builtIn function for the created input; the built-in
+// functions write directly into the storage of the accounts, so the resulting changes of their
+// storage sizes are added to the returned output accounts
 func (context *blockchainContext) ProcessBuiltInFunction(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error) {
-	return context.blockChainHook.ProcessBuiltInFunction(input)
+	storage := context.host.Storage()
+	sizesBefore := storage.GetBlockchainStorageSizes(builtinFunctionAccounts(input))
+
+	vmOutput, err := context.blockChainHook.ProcessBuiltInFunction(input)
+	if err != nil {
+		return vmOutput, err
+	}
+
+	if vmOutput.ReturnCode == vmcommon.Ok {
+		storage.AddStorageSizeChanges(vmOutput, sizesBefore)
+	}
+
+	return vmOutput, nil
+}
+
+// builtinFunctionAccounts returns the addresses of the accounts whose storage may be written
+// by the given built-in function call
+func builtinFunctionAccounts(input *vmcommon.ContractCallInput) [][]byte {
+	accounts := [][]byte{input.CallerAddr}
+	if !bytes.Equal(input.CallerAddr, input.RecipientAddr) {
+		return append(accounts, input.RecipientAddr)
+	}
+
+	// the transfers of NFTs and of several tokens are sent to the caller itself,
+	// with the actual destination among the arguments
+	switch input.Function {
+	case core.BuiltInFunctionDCDTNFTTransfer:
+		if len(input.Arguments) > 3 {
+			accounts = append(accounts, input.Arguments[3])
+		}
+	case core.BuiltInFunctionMultiDCDTNFTTransfer:
+		if len(input.Arguments) > 0 {
+			accounts = append(accounts, input.Arguments[0])
+		}
+	}
+
+	return accounts
 }
 
 // InitState does nothing
@@ -0,0 +1,152 @@
+package contexts
+
+import (
+	"container/list"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+)
+
+// warmInstance is a Wasmer instance managed by the warm instance pool, together
+// with the size of its memory right after instantiation.
+type warmInstance struct {
+	codeHash            string
+	instance            wasmer.InstanceHandler
+	initialMemoryLength uint32
+	failed              bool
+}
+
+func newWarmInstance(codeHash []byte, instance wasmer.InstanceHandler) *warmInstance {
+	warm := &warmInstance{
+		codeHash: string(codeHash),
+		instance: instance,
+	}
+	if instance.HasMemory() {
+		warm.initialMemoryLength = instance.GetMemory().Length()
+	}
+
+	return warm
+}
+
+// reset restores the memory and the globals of the instance to their state right after
+// instantiation, since the instance may be handed to another contract with the same code.
+func (warm *warmInstance) reset() bool {
+	warm.failed = false
+	return warm.instance.Reset()
+}
+
+// wrapFunction marks the instance as failed if the function returns an error, because
+// a trap can interrupt the execution at any point and leave the instance in an unknown state.
+func (warm *warmInstance) wrapFunction(function wasmer.ExportedFunctionCallback) wasmer.ExportedFunctionCallback {
+	return func(args ...interface{}) (wasmer.Value, error) {
+		result, err := function(args...)
+		if err != nil {
+			warm.failed = true
+		}
+		return result, err
+	}
+}
+
+// isReusable returns false if the instance was interrupted by a breakpoint or a trap,
+// or if its memory has grown, since resetting it might not shrink the memory back.
+func (warm *warmInstance) isReusable() bool {
+	if warm.failed {
+		return false
+	}
+	if vmhost.BreakpointValue(warm.instance.GetBreakpointValue()) != vmhost.BreakpointNone {
+		return false
+	}
+	if !warm.instance.HasMemory() {
+		return true
+	}
+
+	return warm.instance.GetMemory().Length() == warm.initialMemoryLength
+}
+
+// warmInstancePool holds idle Wasmer instances, indexed by the hash of their code
+// and evicted in least-recently-used order.
+type warmInstancePool struct {
+	lru     *list.List
+	entries map[string][]*list.Element
+	metrics vmhost.WarmInstancePoolMetrics
+}
+
+func newWarmInstancePool() *warmInstancePool {
+	return &warmInstancePool{
+		lru:     list.New(),
+		entries: make(map[string][]*list.Element),
+	}
+}
+
+// take removes an idle instance with the given code hash from the pool, if any.
+func (pool *warmInstancePool) take(codeHash []byte) (*warmInstance, bool) {
+	elements := pool.entries[string(codeHash)]
+	if len(elements) == 0 {
+		pool.metrics.Misses++
+		return nil, false
+	}
+
+	element := elements[len(elements)-1]
+	pool.removeElement(element)
+	pool.metrics.Hits++
+
+	return element.Value.(*warmInstance), true
+}
+
+// put adds an idle instance to the pool, cleaning the least recently used
+// instances until at most maxIdle instances remain.
+func (pool *warmInstancePool) put(warm *warmInstance, maxIdle int) {
+	element := pool.lru.PushFront(warm)
+	pool.entries[warm.codeHash] = append(pool.entries[warm.codeHash], element)
+
+	for pool.lru.Len() > maxIdle {
+		oldest := pool.lru.Back()
+		pool.removeElement(oldest)
+		oldest.Value.(*warmInstance).instance.Clean()
+		pool.metrics.Evictions++
+	}
+}
+
+// discard cleans an instance that cannot be returned to the pool.
+func (pool *warmInstancePool) discard(warm *warmInstance) {
+	warm.instance.Clean()
+	pool.metrics.Discards++
+}
+
+// clear cleans all the idle instances.
+func (pool *warmInstancePool) clear() {
+	for element := pool.lru.Front(); element != nil; element = element.Next() {
+		element.Value.(*warmInstance).instance.Clean()
+	}
+
+	pool.lru.Init()
+	pool.entries = make(map[string][]*list.Element)
+}
+
+func (pool *warmInstancePool) len() int {
+	return pool.lru.Len()
+}
+
+func (pool *warmInstancePool) getMetrics() vmhost.WarmInstancePoolMetrics {
+	metrics := pool.metrics
+	metrics.IdleInstances = uint64(pool.lru.Len())
+	return metrics
+}
+
+func (pool *warmInstancePool) removeElement(element *list.Element) {
+	codeHash := element.Value.(*warmInstance).codeHash
+	elements := pool.entries[codeHash]
+	for i, candidate := range elements {
+		if candidate == element {
+			elements = append(elements[:i], elements[i+1:]...)
+			break
+		}
+	}
+
+	if len(elements) == 0 {
+		delete(pool.entries, codeHash)
+	} else {
+		pool.entries[codeHash] = elements
+	}
+	pool.lru.Remove(element)
+}
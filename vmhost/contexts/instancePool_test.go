@@ -0,0 +1,111 @@
+package contexts
+
+import (
+	"errors"
+	"testing"
+
+	contextmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmInstancePool_TakeAndPut(t *testing.T) {
+	pool := newWarmInstancePool()
+
+	_, found := pool.take([]byte("code1"))
+	require.False(t, found)
+
+	instance := contextmock.NewInstanceMock([]byte("code1"))
+	pool.put(newWarmInstance([]byte("code1"), instance), 10)
+	require.Equal(t, 1, pool.len())
+
+	_, found = pool.take([]byte("code2"))
+	require.False(t, found)
+
+	warm, found := pool.take([]byte("code1"))
+	require.True(t, found)
+	require.Equal(t, instance, warm.instance)
+	require.Equal(t, 0, pool.len())
+
+	metrics := pool.getMetrics()
+	require.Equal(t, uint64(1), metrics.Hits)
+	require.Equal(t, uint64(2), metrics.Misses)
+	require.Equal(t, uint64(0), metrics.IdleInstances)
+}
+
+func TestWarmInstancePool_EvictsLeastRecentlyUsed(t *testing.T) {
+	pool := newWarmInstancePool()
+
+	pool.put(newWarmInstance([]byte("code1"), contextmock.NewInstanceMock(nil)), 2)
+	pool.put(newWarmInstance([]byte("code2"), contextmock.NewInstanceMock(nil)), 2)
+	pool.put(newWarmInstance([]byte("code3"), contextmock.NewInstanceMock(nil)), 2)
+	require.Equal(t, 2, pool.len())
+
+	_, found := pool.take([]byte("code1"))
+	require.False(t, found)
+	_, found = pool.take([]byte("code2"))
+	require.True(t, found)
+	_, found = pool.take([]byte("code3"))
+	require.True(t, found)
+
+	metrics := pool.getMetrics()
+	require.Equal(t, uint64(1), metrics.Evictions)
+
+	pool.put(newWarmInstance([]byte("code1"), contextmock.NewInstanceMock(nil)), 0)
+	require.Equal(t, 0, pool.len())
+}
+
+func TestWarmInstancePool_SameCodeHash(t *testing.T) {
+	pool := newWarmInstancePool()
+
+	pool.put(newWarmInstance([]byte("code"), contextmock.NewInstanceMock(nil)), 10)
+	pool.put(newWarmInstance([]byte("code"), contextmock.NewInstanceMock(nil)), 10)
+	require.Equal(t, 2, pool.len())
+
+	_, found := pool.take([]byte("code"))
+	require.True(t, found)
+	_, found = pool.take([]byte("code"))
+	require.True(t, found)
+	_, found = pool.take([]byte("code"))
+	require.False(t, found)
+
+	pool.put(newWarmInstance([]byte("code"), contextmock.NewInstanceMock(nil)), 10)
+	pool.clear()
+	require.Equal(t, 0, pool.len())
+}
+
+func TestWarmInstance_FailedFunction(t *testing.T) {
+	instance := contextmock.NewInstanceMock(nil)
+	warm := newWarmInstance([]byte("code"), instance)
+
+	succeeding := warm.wrapFunction(func(...interface{}) (wasmer.Value, error) {
+		return wasmer.Void(), nil
+	})
+	_, err := succeeding()
+	require.Nil(t, err)
+	require.True(t, warm.isReusable())
+
+	failing := warm.wrapFunction(func(...interface{}) (wasmer.Value, error) {
+		return wasmer.Void(), errors.New("trap")
+	})
+	_, err = failing()
+	require.NotNil(t, err)
+	require.False(t, warm.isReusable())
+
+	require.True(t, warm.reset())
+	require.True(t, warm.isReusable())
+}
+
+func TestWarmInstance_IsReusable(t *testing.T) {
+	instance := contextmock.NewInstanceMock(nil)
+	warm := newWarmInstance([]byte("code"), instance)
+	require.True(t, warm.isReusable())
+
+	instance.SetBreakpointValue(uint64(vmhost.BreakpointSignalError))
+	require.False(t, warm.isReusable())
+
+	instance.SetBreakpointValue(uint64(vmhost.BreakpointNone))
+	_ = instance.Memory.Grow(1)
+	require.False(t, warm.isReusable())
+}
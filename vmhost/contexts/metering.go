@@ -412,9 +412,15 @@ func (context *meteringContext) UseGasBounded(gasToUse uint64) error {
 
 // ComputeGasLockedForAsync calculates the minimum amount of gas to lock for async callbacks
 func (context *meteringContext) ComputeGasLockedForAsync() uint64 {
+	codeSize := context.host.Runtime().GetSCCodeSize()
+	return context.ComputeGasLockedForAsyncWithCodeSize(codeSize)
+}
+
+// ComputeGasLockedForAsyncWithCodeSize calculates the minimum amount of gas to lock for
+// async callbacks into a contract with the given code size
+func (context *meteringContext) ComputeGasLockedForAsyncWithCodeSize(codeSize uint64) uint64 {
 	baseGasSchedule := context.GasSchedule().BaseOperationCost
 	apiGasSchedule := context.GasSchedule().BaseOpsAPICost
-	codeSize := context.host.Runtime().GetSCCodeSize()
 
 	costPerByte := baseGasSchedule.CompilePerByte
 	if context.host.IsAheadOfTimeCompileEnabled() {
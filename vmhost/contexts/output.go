@@ -3,12 +3,14 @@ package contexts
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/data/vm"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/math"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 )
 
@@ -201,9 +203,24 @@ func (context *outputContext) ClearReturnData() {
 	context.outputState.ReturnData = make([][]byte, 0)
 }
 
-// SelfDestruct does nothing
+// SelfDestruct does nothing, apart from failing the execution in strict read-only mode
 // TODO change comment when the function is implemented
 func (context *outputContext) SelfDestruct(_ []byte, _ []byte) {
+	err := context.checkStrictReadOnly("self destruct")
+	if err != nil {
+		logOutput.Trace("self destruct", "error", err)
+		_ = vmhost.WithFaultAndHost(context.host, err, true)
+	}
+}
+
+// checkStrictReadOnly returns an error naming the given operation if the current
+// execution is read-only and strict read-only checks are enabled
+func (context *outputContext) checkStrictReadOnly(operation string) error {
+	if !context.host.Runtime().ReadOnly() || !context.host.IsStrictReadOnlyEnabled() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", vmhost.ErrStateChangeInReadOnly, operation)
 }
 
 // Finish appends the given data to the return data of the current output state.
@@ -219,6 +236,11 @@ func (context *outputContext) PrependFinish(data []byte) {
 // WriteLog creates a new LogEntry and appends it to the logs of the current output state.
 func (context *outputContext) WriteLog(address []byte, topics [][]byte, data []byte) {
 	if context.host.Runtime().ReadOnly() {
+		if context.host.IsStrictReadOnlyEnabled() {
+			logOutput.Trace("log entry", "error", vmhost.ErrStateChangeInReadOnly)
+			_ = vmhost.WithFaultAndHost(context.host, fmt.Errorf("%w: log write", vmhost.ErrStateChangeInReadOnly), true)
+			return
+		}
 		logOutput.Trace("log entry", "error", "cannot write logs in readonly mode")
 		return
 	}
@@ -250,6 +272,19 @@ func (context *outputContext) TransferValueOnly(destination []byte, sender []byt
 		return vmhost.ErrTransferNegativeValue
 	}
 
+	if value.Cmp(vmhost.Zero) > 0 && context.host.Runtime().QueryMode() {
+		logOutput.Trace("transfer value", "error", vmhost.ErrStateChangeInQuery)
+		return vmhost.ErrStateChangeInQuery
+	}
+
+	if value.Cmp(vmhost.Zero) > 0 {
+		err := context.checkStrictReadOnly("value transfer")
+		if err != nil {
+			logOutput.Trace("transfer value", "error", err)
+			return err
+		}
+	}
+
 	if !context.hasSufficientBalance(sender, value) {
 		logOutput.Trace("transfer value", "error", vmhost.ErrTransferInsufficientFunds)
 		return vmhost.ErrTransferInsufficientFunds
@@ -282,6 +317,11 @@ func (context *outputContext) TransferValueOnly(destination []byte, sender []byt
 // the necessary steps to create accounts and reverses the state in case of an
 // execution error or failed value transfer.
 func (context *outputContext) Transfer(destination []byte, sender []byte, gasLimit uint64, gasLocked uint64, value *big.Int, input []byte, callType vm.CallType) error {
+	if context.host.Runtime().QueryMode() {
+		logOutput.Trace("transfer", "error", vmhost.ErrStateChangeInQuery)
+		return vmhost.ErrStateChangeInQuery
+	}
+
 	checkPayableIfNotCallback := gasLimit > 0 && callType != vm.AsynchronousCallBack
 	err := context.TransferValueOnly(destination, sender, value, checkPayableIfNotCallback)
 	if err != nil {
@@ -312,66 +352,158 @@ func (context *outputContext) TransferDCDT(
 	value *big.Int,
 	callInput *vmcommon.ContractCallInput,
 ) (uint64, error) {
-	isSmartContract := context.host.Blockchain().IsSmartContract(destination)
-	sameShard := context.host.AreInSameShard(sender, destination)
-	callType := vm.DirectCall
-	isExecution := isSmartContract && callInput != nil
-	if isExecution {
-		callType = vm.DCDTTransferAndExecute
+	if context.host.Runtime().QueryMode() {
+		logOutput.Trace("transfer DCDT", "error", vmhost.ErrStateChangeInQuery)
+		return 0, vmhost.ErrStateChangeInQuery
+	}
+	err := context.checkStrictReadOnly("DCDT transfer")
+	if err != nil {
+		logOutput.Trace("transfer DCDT", "error", err)
+		return 0, err
 	}
 
+	callType := context.dcdtTransferCallType(destination, callInput)
 	vmOutput, gasConsumedByTransfer, err := context.host.ExecuteDCDTTransfer(destination, sender, tokenIdentifier, nonce, value, callType)
 	if err != nil {
 		return 0, err
 	}
 
-	gasRemaining := uint64(0)
+	gasRemaining, err := context.gasForDCDTPostTransferExecution(destination, sender, gasConsumedByTransfer, callInput)
+	if err != nil {
+		return 0, err
+	}
 
-	if callInput != nil && isSmartContract {
-		if gasConsumedByTransfer > callInput.GasProvided {
-			logOutput.Trace("DCDT post-transfer execution", "error", vmhost.ErrNotEnoughGas)
-			return 0, vmhost.ErrNotEnoughGas
+	sameShard := context.host.AreInSameShard(sender, destination)
+	transferData := []byte(core.BuiltInFunctionDCDTTransfer + "@" + hex.EncodeToString(tokenIdentifier) + "@" + hex.EncodeToString(value.Bytes()))
+	if nonce > 0 {
+		nonceAsBytes := big.NewInt(0).SetUint64(nonce).Bytes()
+		transferData = []byte(core.BuiltInFunctionDCDTNFTTransfer + "@" + hex.EncodeToString(tokenIdentifier) +
+			"@" + hex.EncodeToString(nonceAsBytes) + "@" + hex.EncodeToString(value.Bytes()))
+		if sameShard {
+			transferData = append(transferData, []byte("@"+hex.EncodeToString(destination))...)
+		} else {
+			transferData = crossShardTransferData(destination, vmOutput, transferData)
 		}
-		gasRemaining = callInput.GasProvided - gasConsumedByTransfer
 	}
 
-	if isExecution {
-		if gasRemaining > context.host.Metering().GasLeft() {
-			logOutput.Trace("DCDT post-transfer execution", "error", vmhost.ErrNotEnoughGas)
-			return 0, vmhost.ErrNotEnoughGas
-		}
+	context.addDCDTOutputTransfer(destination, sender, transferData, gasRemaining, callInput, vmOutput)
+	return gasRemaining, nil
+}
 
-		if !sameShard {
-			context.host.Metering().UseGas(gasRemaining)
-		}
+// TransferMultiDCDT makes the transfer of several dcdt/nft tokens at once, through the
+// MultiDCDTNFTTransfer built-in function, and exports the data if it is cross shard
+func (context *outputContext) TransferMultiDCDT(
+	destination []byte,
+	sender []byte,
+	transfers []*vmcommon.DCDTTransfer,
+	callInput *vmcommon.ContractCallInput,
+) (uint64, error) {
+	if context.host.Runtime().QueryMode() {
+		logOutput.Trace("transfer multi DCDT", "error", vmhost.ErrStateChangeInQuery)
+		return 0, vmhost.ErrStateChangeInQuery
+	}
+	err := context.checkStrictReadOnly("multi DCDT transfer")
+	if err != nil {
+		logOutput.Trace("transfer multi DCDT", "error", err)
+		return 0, err
+	}
+
+	callType := context.dcdtTransferCallType(destination, callInput)
+	vmOutput, gasConsumedByTransfer, err := context.host.ExecuteMultiDCDTTransfer(destination, sender, transfers, callType)
+	if err != nil {
+		return 0, err
 	}
 
+	gasRemaining, err := context.gasForDCDTPostTransferExecution(destination, sender, gasConsumedByTransfer, callInput)
+	if err != nil {
+		return 0, err
+	}
+
+	numTransfers := big.NewInt(int64(len(transfers))).Bytes()
+	transferData := []byte(core.BuiltInFunctionMultiDCDTNFTTransfer + "@" + hex.EncodeToString(destination) + "@" + hex.EncodeToString(numTransfers))
+	for _, transfer := range transfers {
+		nonceAsBytes := big.NewInt(0).SetUint64(transfer.DCDTTokenNonce).Bytes()
+		transferData = append(transferData, []byte("@"+hex.EncodeToString(transfer.DCDTTokenName)+
+			"@"+hex.EncodeToString(nonceAsBytes)+"@"+hex.EncodeToString(transfer.DCDTValue.Bytes()))...)
+	}
+	if !context.host.AreInSameShard(sender, destination) {
+		transferData = crossShardTransferData(destination, vmOutput, transferData)
+	}
+
+	context.addDCDTOutputTransfer(destination, sender, transferData, gasRemaining, callInput, vmOutput)
+	return gasRemaining, nil
+}
+
+func (context *outputContext) dcdtTransferCallType(destination []byte, callInput *vmcommon.ContractCallInput) vm.CallType {
+	isSmartContract := context.host.Blockchain().IsSmartContract(destination)
+	if isSmartContract && callInput != nil {
+		return vm.DCDTTransferAndExecute
+	}
+
+	return vm.DirectCall
+}
+
+// gasForDCDTPostTransferExecution returns the gas left for the execution following a dcdt transfer,
+// which is consumed right away if the execution takes place on another shard
+func (context *outputContext) gasForDCDTPostTransferExecution(
+	destination []byte,
+	sender []byte,
+	gasConsumedByTransfer uint64,
+	callInput *vmcommon.ContractCallInput,
+) (uint64, error) {
+	isSmartContract := context.host.Blockchain().IsSmartContract(destination)
+	if callInput == nil || !isSmartContract {
+		return 0, nil
+	}
+
+	if gasConsumedByTransfer > callInput.GasProvided {
+		logOutput.Trace("DCDT post-transfer execution", "error", vmhost.ErrNotEnoughGas)
+		return 0, vmhost.ErrNotEnoughGas
+	}
+	gasRemaining := callInput.GasProvided - gasConsumedByTransfer
+
+	if gasRemaining > context.host.Metering().GasLeft() {
+		logOutput.Trace("DCDT post-transfer execution", "error", vmhost.ErrNotEnoughGas)
+		return 0, vmhost.ErrNotEnoughGas
+	}
+
+	if !context.host.AreInSameShard(sender, destination) {
+		context.host.Metering().UseGas(gasRemaining)
+	}
+
+	return gasRemaining, nil
+}
+
+// crossShardTransferData returns the data of the transfer generated by the built-in function
+// for the destination, if there is exactly one, or the given data otherwise
+func crossShardTransferData(destination []byte, vmOutput *vmcommon.VMOutput, transferData []byte) []byte {
+	outTransfer, ok := vmOutput.OutputAccounts[string(destination)]
+	if ok && len(outTransfer.OutputTransfers) == 1 {
+		return outTransfer.OutputTransfers[0].Data
+	}
+
+	return transferData
+}
+
+func (context *outputContext) addDCDTOutputTransfer(
+	destination []byte,
+	sender []byte,
+	transferData []byte,
+	gasRemaining uint64,
+	callInput *vmcommon.ContractCallInput,
+	vmOutput *vmcommon.VMOutput,
+) {
 	destAcc, _ := context.GetOutputAccount(destination)
 	outputTransfer := vmcommon.OutputTransfer{
 		Value:         big.NewInt(0),
 		GasLimit:      gasRemaining,
 		GasLocked:     0,
-		Data:          []byte(core.BuiltInFunctionDCDTTransfer + "@" + hex.EncodeToString(tokenIdentifier) + "@" + hex.EncodeToString(value.Bytes())),
+		Data:          transferData,
 		CallType:      vm.DirectCall,
 		SenderAddress: sender,
 	}
 
-	if nonce > 0 {
-		nonceAsBytes := big.NewInt(0).SetUint64(nonce).Bytes()
-		outputTransfer.Data = []byte(core.BuiltInFunctionDCDTNFTTransfer + "@" + hex.EncodeToString(tokenIdentifier) +
-			"@" + hex.EncodeToString(nonceAsBytes) + "@" + hex.EncodeToString(value.Bytes()))
-		if sameShard {
-			outputTransfer.Data = append(outputTransfer.Data, []byte("@"+hex.EncodeToString(destination))...)
-		} else {
-			outTransfer, ok := vmOutput.OutputAccounts[string(destination)]
-			if ok && len(outTransfer.OutputTransfers) == 1 {
-				outputTransfer.Data = outTransfer.OutputTransfers[0].Data
-			}
-		}
-
-	}
-
-	if sameShard {
+	if context.host.AreInSameShard(sender, destination) {
 		outputTransfer.GasLimit = 0
 	}
 
@@ -385,8 +517,16 @@ func (context *outputContext) TransferDCDT(
 
 	destAcc.OutputTransfers = append(destAcc.OutputTransfers, outputTransfer)
 
+	for _, builtinAccount := range vmOutput.OutputAccounts {
+		if builtinAccount.BytesAddedToStorage == 0 && builtinAccount.BytesDeletedFromStorage == 0 {
+			continue
+		}
+		account, _ := context.GetOutputAccount(builtinAccount.Address)
+		account.BytesAddedToStorage = math.AddUint64(account.BytesAddedToStorage, builtinAccount.BytesAddedToStorage)
+		account.BytesDeletedFromStorage = math.AddUint64(account.BytesDeletedFromStorage, builtinAccount.BytesDeletedFromStorage)
+	}
+
 	context.outputState.Logs = append(context.outputState.Logs, vmOutput.Logs...)
-	return gasRemaining, nil
 }
 
 func (context *outputContext) hasSufficientBalance(address []byte, value *big.Int) bool {
@@ -519,6 +659,8 @@ func (context *outputContext) AddToActiveState(rightOutput *vmcommon.VMOutput) {
 		if rightAccount.BalanceDelta != nil {
 			rightAccount.BalanceDelta.Add(rightAccount.BalanceDelta, leftAccount.BalanceDelta)
 		}
+		rightAccount.BytesAddedToStorage = math.AddUint64(rightAccount.BytesAddedToStorage, leftAccount.BytesAddedToStorage)
+		rightAccount.BytesDeletedFromStorage = math.AddUint64(rightAccount.BytesDeletedFromStorage, leftAccount.BytesDeletedFromStorage)
 		if len(rightAccount.OutputTransfers) > 0 {
 			leftAccount.OutputTransfers = append(leftAccount.OutputTransfers, rightAccount.OutputTransfers...)
 		}
@@ -590,6 +732,9 @@ func mergeOutputAccounts(
 
 	leftAccount.GasUsed = rightAccount.GasUsed
 
+	leftAccount.BytesAddedToStorage = rightAccount.BytesAddedToStorage
+	leftAccount.BytesDeletedFromStorage = rightAccount.BytesDeletedFromStorage
+
 	if rightAccount.CodeDeployerAddress != nil {
 		leftAccount.CodeDeployerAddress = rightAccount.CodeDeployerAddress
 	}
@@ -1,6 +1,7 @@
 package contexts
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -361,6 +362,33 @@ func TestOutputContext_MergeVMOutputs(t *testing.T) {
 	require.Equal(t, expected, left)
 }
 
+func TestOutputContext_AddToActiveState_StorageSize(t *testing.T) {
+	t.Parallel()
+
+	host := &contextmock.VMHostStub{}
+	outputContext, _ := NewOutputContext(host)
+
+	address := []byte("account")
+	account, _ := outputContext.GetOutputAccount(address)
+	account.BytesAddedToStorage = 10
+	account.BytesDeletedFromStorage = 4
+
+	outputContext.PushState()
+	account.BytesAddedToStorage += 5
+	outputContext.PopMergeActiveState()
+
+	builtinOutput := newVMOutput()
+	builtinAccount := NewVMOutputAccount(address)
+	builtinAccount.BytesAddedToStorage = 3
+	builtinAccount.BytesDeletedFromStorage = 2
+	builtinOutput.OutputAccounts[string(address)] = builtinAccount
+	outputContext.AddToActiveState(builtinOutput)
+
+	account, _ = outputContext.GetOutputAccount(address)
+	require.Equal(t, uint64(18), account.BytesAddedToStorage)
+	require.Equal(t, uint64(6), account.BytesDeletedFromStorage)
+}
+
 func TestOutputContext_VMOutputError(t *testing.T) {
 	t.Parallel()
 
@@ -560,6 +588,31 @@ func TestOutputContext_WriteLog(t *testing.T) {
 	require.Equal(t, outputContext.outputState.Logs[2].Topics, [][]byte{topic})
 }
 
+func TestOutputContext_WriteLog_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	mockRuntime := &contextmock.RuntimeContextMock{}
+	mockRuntime.SetReadOnly(true)
+	mockMetering := &contextmock.MeteringContextMock{}
+	host := &contextmock.VMHostMock{
+		RuntimeContext:  mockRuntime,
+		MeteringContext: mockMetering,
+	}
+	outputContext, _ := NewOutputContext(host)
+
+	address := []byte("address")
+	data := []byte("data")
+
+	outputContext.WriteLog(address, nil, data)
+	require.Empty(t, outputContext.outputState.Logs)
+	require.Nil(t, mockRuntime.GetAllErrors())
+
+	host.StrictReadOnly = true
+	outputContext.WriteLog(address, nil, data)
+	require.Empty(t, outputContext.outputState.Logs)
+	require.True(t, errors.Is(mockRuntime.GetAllErrors(), vmhost.ErrStateChangeInReadOnly))
+}
+
 func TestOutputContext_PopSetActiveStateIfStackIsEmptyShouldNotPanic(t *testing.T) {
 	t.Parallel()
 
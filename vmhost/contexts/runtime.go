@@ -2,12 +2,14 @@ package contexts
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	builtinMath "math"
 	"math/big"
 	"unsafe"
 
+	"github.com/kalyan3104/k-chain-core-go/core/check"
 	logger "github.com/kalyan3104/k-chain-logger-go"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/math"
@@ -32,6 +34,7 @@ type runtimeContext struct {
 	callFunction string
 	vmType       []byte
 	readOnly     bool
+	queryMode    bool
 
 	verifyCode bool
 
@@ -46,11 +49,17 @@ type runtimeContext struct {
 	validator *wasmValidator
 
 	useWarmInstance     bool
-	warmInstanceAddress []byte
-	warmInstance        wasmer.InstanceHandler
+	warmInstances       *warmInstancePool
+	checkedOutInstances map[wasmer.InstanceHandler]*warmInstance
 
 	instanceBuilder vmhost.InstanceBuilder
 
+	compiledCodeCache  vmhost.CompiledCodeCache
+	gasScheduleVersion []byte
+
+	functionCallTracer vmhost.FunctionCallTracer
+	instanceCodeHash   []byte
+
 	errors vmhost.WrappableError
 }
 
@@ -70,8 +79,8 @@ func NewRuntimeContext(
 		instanceStack:       make([]wasmer.InstanceHandler, 0),
 		validator:           newWASMValidator(scAPINames, builtInFuncContainer),
 		useWarmInstance:     useWarmInstance,
-		warmInstanceAddress: nil,
-		warmInstance:        nil,
+		warmInstances:       newWarmInstancePool(),
+		checkedOutInstances: make(map[wasmer.InstanceHandler]*warmInstance),
 		errors:              nil,
 	}
 
@@ -105,21 +114,63 @@ func (context *runtimeContext) ReplaceInstanceBuilder(builder vmhost.InstanceBui
 	context.instanceBuilder = builder
 }
 
-func (context *runtimeContext) setWarmInstanceWhenNeeded(gasLimit uint64) bool {
-	scAddress := context.GetSCAddress()
-	useWarm := context.useWarmInstance && context.warmInstanceAddress != nil && bytes.Equal(scAddress, context.warmInstanceAddress)
-	if scAddress != nil && useWarm {
-		logRuntime.Trace("reusing warm instance")
+func (context *runtimeContext) setWarmInstanceWhenNeeded(codeHash []byte, gasLimit uint64, newCode bool) bool {
+	if !context.useWarmInstance || newCode || len(codeHash) == 0 {
+		return false
+	}
 
-		context.instance = context.warmInstance
-		context.SetPointsUsed(0)
-		context.instance.SetGasLimit(gasLimit)
+	warm, found := context.warmInstances.take(codeHash)
+	if !found {
+		return false
+	}
 
-		context.SetRuntimeBreakpointValue(vmhost.BreakpointNone)
-		return true
+	if !warm.reset() {
+		context.warmInstances.discard(warm)
+		logRuntime.Trace("warm instance discarded", "error", "reset failed")
+		return false
 	}
 
-	return false
+	logRuntime.Trace("reusing warm instance")
+	context.instance = warm.instance
+	context.checkedOutInstances[warm.instance] = warm
+	context.SetPointsUsed(0)
+	context.instance.SetGasLimit(gasLimit)
+	context.verifyCode = false
+
+	context.SetRuntimeBreakpointValue(vmhost.BreakpointNone)
+	return true
+}
+
+// trackWarmInstance marks the current instance as managed by the warm instance pool,
+// before any code is executed.
+func (context *runtimeContext) trackWarmInstance(codeHash []byte) {
+	if !context.useWarmInstance || len(codeHash) == 0 {
+		return
+	}
+
+	context.checkedOutInstances[context.instance] = newWarmInstance(codeHash, context.instance)
+	logRuntime.Trace("updated warm instance")
+}
+
+// releaseWarmInstance returns the current instance to the warm instance pool, if it is reusable.
+// The pool only keeps as many idle instances as allowed by maxWasmerInstances, besides the running ones.
+func (context *runtimeContext) releaseWarmInstance(warm *warmInstance) {
+	delete(context.checkedOutInstances, warm.instance)
+	context.instance = nil
+
+	if !warm.isReusable() {
+		context.warmInstances.discard(warm)
+		logRuntime.Trace("warm instance discarded")
+		return
+	}
+
+	maxIdle := 0
+	numRunning := context.RunningInstancesCount()
+	if context.maxWasmerInstances > numRunning {
+		maxIdle = int(context.maxWasmerInstances - numRunning)
+	}
+	context.warmInstances.put(warm, maxIdle)
+	logRuntime.Trace("warm instance released", "idle", context.warmInstances.len())
 }
 
 // StartWasmerInstance creates a new wasmer instance if the maxWasmerInstances has not been reached.
@@ -130,13 +181,15 @@ func (context *runtimeContext) StartWasmerInstance(contract []byte, gasLimit uin
 		return vmhost.ErrMaxInstancesReached
 	}
 
-	warmInstanceUsed := context.setWarmInstanceWhenNeeded(gasLimit)
+	context.setInstanceCodeHashForTracing(contract)
+
+	blockchain := context.host.Blockchain()
+	codeHash := blockchain.GetCodeHash(context.GetSCAddress())
+	warmInstanceUsed := context.setWarmInstanceWhenNeeded(codeHash, gasLimit, newCode)
 	if warmInstanceUsed {
 		return nil
 	}
 
-	blockchain := context.host.Blockchain()
-	codeHash := blockchain.GetCodeHash(context.GetSCAddress())
 	compiledCodeUsed := context.makeInstanceFromCompiledCode(codeHash, gasLimit, newCode)
 	if compiledCodeUsed {
 		return nil
@@ -154,13 +207,6 @@ func (context *runtimeContext) makeInstanceFromCompiledCode(codeHash []byte, gas
 		return false
 	}
 
-	blockchain := context.host.Blockchain()
-	found, compiledCode := blockchain.GetCompiledCode(codeHash)
-	if !found {
-		logRuntime.Trace("instance creation", "code", "cached compilation", "error", "compiled code was not found")
-		return false
-	}
-
 	gasSchedule := context.host.Metering().GasSchedule()
 	options := wasmer.CompilationOptions{
 		GasLimit:           gasLimit,
@@ -171,6 +217,12 @@ func (context *runtimeContext) makeInstanceFromCompiledCode(codeHash []byte, gas
 		Metering:           true,
 		RuntimeBreakpoints: true,
 	}
+	found, compiledCode := context.getCompiledCode(codeHash, options)
+	if !found {
+		logRuntime.Trace("instance creation", "code", "cached compilation", "error", "compiled code was not found")
+		return false
+	}
+
 	newInstance, err := context.instanceBuilder.NewInstanceFromCompiledCodeWithOptions(compiledCode, options)
 	if err != nil {
 		logRuntime.Error("instance creation", "code", "cached compilation", "error", err)
@@ -182,6 +234,7 @@ func (context *runtimeContext) makeInstanceFromCompiledCode(codeHash []byte, gas
 	hostReference := uintptr(unsafe.Pointer(&context.host))
 	context.instance.SetContextData(hostReference)
 	context.verifyCode = false
+	context.trackWarmInstance(codeHash)
 
 	logRuntime.Trace("new instance created", "code", "cached compilation")
 	return true
@@ -207,6 +260,8 @@ func (context *runtimeContext) makeInstanceFromContractByteCode(contract []byte,
 
 	context.instance = newInstance
 
+	// only instances of deployed code can be found again by its hash, on later calls
+	isWarmCandidate := !newCode && len(codeHash) > 0
 	if newCode || len(codeHash) == 0 {
 		codeHash, err = context.host.Crypto().Sha256(contract)
 		if err != nil {
@@ -216,7 +271,7 @@ func (context *runtimeContext) makeInstanceFromContractByteCode(contract []byte,
 		}
 	}
 
-	context.saveCompiledCode(codeHash)
+	context.saveCompiledCode(codeHash, options)
 
 	hostReference := uintptr(unsafe.Pointer(&context.host))
 	context.instance.SetContextData(hostReference)
@@ -230,10 +285,8 @@ func (context *runtimeContext) makeInstanceFromContractByteCode(contract []byte,
 		}
 	}
 
-	if context.useWarmInstance {
-		context.warmInstanceAddress = context.GetSCAddress()
-		context.warmInstance = context.instance
-		logRuntime.Trace("updated warm instance")
+	if isWarmCandidate {
+		context.trackWarmInstance(codeHash)
 	}
 
 	logRuntime.Trace("new instance created", "code", "bytecode")
@@ -258,38 +311,83 @@ func (context *runtimeContext) GetSCCodeSize() uint64 {
 	return context.codeSize
 }
 
-func (context *runtimeContext) saveCompiledCode(codeHash []byte) {
+func (context *runtimeContext) saveCompiledCode(codeHash []byte, options wasmer.CompilationOptions) {
 	compiledCode, err := context.instance.Cache()
 	if err != nil {
 		logRuntime.Error("getCompiledCode from instance", "error", err)
 		return
 	}
 
+	if !check.IfNil(context.compiledCodeCache) {
+		context.compiledCodeCache.Put(context.compiledCodeCacheKey(codeHash, options), compiledCode)
+		return
+	}
+
 	blockchain := context.host.Blockchain()
 	blockchain.SaveCompiledCode(codeHash, compiledCode)
 }
 
-// IsWarmInstance returns true if there is a warm instance equal to the current wasmer instance.
+func (context *runtimeContext) getCompiledCode(codeHash []byte, options wasmer.CompilationOptions) (bool, []byte) {
+	if !check.IfNil(context.compiledCodeCache) {
+		compiledCode, found := context.compiledCodeCache.Get(context.compiledCodeCacheKey(codeHash, options))
+		return found, compiledCode
+	}
+
+	blockchain := context.host.Blockchain()
+	return blockchain.GetCompiledCode(codeHash)
+}
+
+// compiledCodeCacheKey identifies the compiled code by everything it depends on: the contract code,
+// the gas schedule and the compilation options, except the gas limit, which is set on each instance.
+func (context *runtimeContext) compiledCodeCacheKey(codeHash []byte, options wasmer.CompilationOptions) []byte {
+	hasher := sha256.New()
+	_, _ = hasher.Write(codeHash)
+	_, _ = hasher.Write(context.gasScheduleVersion)
+	_, _ = fmt.Fprintf(hasher, "%d|%d|%d|%t|%t|%t",
+		options.UnmeteredLocals,
+		options.MaxMemoryGrow,
+		options.MaxMemoryGrowDelta,
+		options.OpcodeTrace,
+		options.Metering,
+		options.RuntimeBreakpoints,
+	)
+	return hasher.Sum(nil)
+}
+
+// SetCompiledCodeCache replaces the blockchain hook as storage for compiled code with the given cache.
+// The gas schedule version must change whenever the gas schedule does, as the compiled code embeds opcode costs.
+func (context *runtimeContext) SetCompiledCodeCache(cache vmhost.CompiledCodeCache, gasScheduleVersion []byte) {
+	context.compiledCodeCache = cache
+	context.gasScheduleVersion = gasScheduleVersion
+}
+
+// IsWarmInstance returns true if the current wasmer instance is managed by the warm instance pool.
 func (context *runtimeContext) IsWarmInstance() bool {
-	if context.instance != nil && context.instance == context.warmInstance {
-		return true
+	if context.instance == nil {
+		return false
 	}
 
-	return false
+	_, isWarm := context.checkedOutInstances[context.instance]
+	return isWarm
 }
 
-// ResetWarmInstance clears the fields for the current wasmer instance, warm instance, and warm instance address
+// ResetWarmInstance cleans the current wasmer instance and all the idle warm instances
 func (context *runtimeContext) ResetWarmInstance() {
+	context.warmInstances.clear()
+	logRuntime.Trace("warm instances cleaned")
+
 	if context.instance == nil {
 		return
 	}
 
+	delete(context.checkedOutInstances, context.instance)
 	context.instance.Clean()
-
 	context.instance = nil
-	context.warmInstanceAddress = nil
-	context.warmInstance = nil
-	logRuntime.Trace("warm instance cleaned")
+}
+
+// GetWarmInstancePoolMetrics returns the hit, miss and eviction counters of the warm instance pool
+func (context *runtimeContext) GetWarmInstancePoolMetrics() vmhost.WarmInstancePoolMetrics {
+	return context.warmInstances.getMetrics()
 }
 
 // MustVerifyNextContractCode sets the verifyCode field to true
@@ -599,7 +697,7 @@ func (context *runtimeContext) VerifyContractCode() error {
 		return err
 	}
 
-	err = context.checkBackwardCompatibility()
+	err = context.validator.verifyImportsEnabled(context.instance, context.host.IsImportEnabled)
 	if err != nil {
 		logRuntime.Trace("verify contract code", "error", err)
 		return err
@@ -610,51 +708,6 @@ func (context *runtimeContext) VerifyContractCode() error {
 	return nil
 }
 
-func (context *runtimeContext) checkBackwardCompatibility() error {
-	if context.host.IsDCDTFunctionsEnabled() {
-		return nil
-	}
-
-	if context.instance.IsFunctionImported("transferDCDTExecute") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("transferDCDTNFTExecute") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("transferValueExecute") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTBalance") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTTokenData") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTTokenType") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTTokenNonce") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getCurrentDCDTNFTNonce") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTNFTNameLength") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTNFTAttributeLength") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("getDCDTNFTURILength") {
-		return vmhost.ErrContractInvalid
-	}
-	if context.instance.IsFunctionImported("bigIntGetDCDTExternalBalance") {
-		return vmhost.ErrContractInvalid
-	}
-
-	return nil
-}
-
 // BaseOpsErrorShouldFailExecution returns true
 func (context *runtimeContext) BaseOpsErrorShouldFailExecution() bool {
 	return true
@@ -701,6 +754,18 @@ func (context *runtimeContext) SetReadOnly(readOnly bool) {
 	context.readOnly = readOnly
 }
 
+// QueryMode returns true if the current transaction is a query, in which case
+// no call in the call tree is allowed to change the state
+func (context *runtimeContext) QueryMode() bool {
+	return context.queryMode
+}
+
+// SetQueryMode sets the queryMode field of the context to the given value. Unlike
+// readOnly, it is not part of the state stack, so it applies to the whole call tree.
+func (context *runtimeContext) SetQueryMode(queryMode bool) {
+	context.queryMode = queryMode
+}
+
 // GetInstance returns the current wasmer instance
 func (context *runtimeContext) GetInstance() wasmer.InstanceHandler {
 	return context.instance
@@ -711,9 +776,15 @@ func (context *runtimeContext) GetInstanceExports() wasmer.ExportsMap {
 	return context.instance.GetExports()
 }
 
-// CleanWasmerInstance cleans the current wasmer instance.
+// CleanWasmerInstance cleans the current wasmer instance, or returns it to the warm instance pool.
 func (context *runtimeContext) CleanWasmerInstance() {
-	if context.instance == nil || context.IsWarmInstance() {
+	if context.instance == nil {
+		return
+	}
+
+	warm, isWarm := context.checkedOutInstances[context.instance]
+	if isWarm {
+		context.releaseWarmInstance(warm)
 		return
 	}
 
@@ -739,7 +810,8 @@ func (context *runtimeContext) GetFunctionToCall() (wasmer.ExportedFunctionCallb
 	exports := context.instance.GetExports()
 	logRuntime.Trace("get function to call", "function", context.callFunction)
 	if function, ok := exports[context.callFunction]; ok {
-		return function, nil
+		context.traceFunctionCall(context.callFunction, exports)
+		return context.wrapWarmInstanceFunction(function), nil
 	}
 
 	if context.callFunction == vmhost.CallbackFunctionName {
@@ -755,12 +827,24 @@ func (context *runtimeContext) GetFunctionToCall() (wasmer.ExportedFunctionCallb
 func (context *runtimeContext) GetInitFunction() wasmer.ExportedFunctionCallback {
 	exports := context.instance.GetExports()
 	if init, ok := exports[vmhost.InitFunctionName]; ok {
-		return init
+		context.traceFunctionCall(vmhost.InitFunctionName, exports)
+		return context.wrapWarmInstanceFunction(init)
 	}
 
 	return nil
 }
 
+// wrapWarmInstanceFunction lets the warm instance pool observe the failures of the functions
+// of the current instance, so that instances left in an unknown state are not reused.
+func (context *runtimeContext) wrapWarmInstanceFunction(function wasmer.ExportedFunctionCallback) wasmer.ExportedFunctionCallback {
+	warm, isWarm := context.checkedOutInstances[context.instance]
+	if !isWarm {
+		return function
+	}
+
+	return warm.wrapFunction(function)
+}
+
 // ExecuteAsyncCall locks the necessary gas and sets the async call info and a runtime breakpoint value.
 func (context *runtimeContext) ExecuteAsyncCall(address []byte, data []byte, value []byte) error {
 	metering := context.host.Metering()
@@ -811,6 +895,13 @@ func (context *runtimeContext) AddAsyncContextCall(contextIdentifier []byte, asy
 		}
 	}
 
+	if context.host.IsAsyncContextMeteringEnabled() {
+		maxAsyncCalls := context.host.Metering().GasSchedule().AsyncContextCost.MaxAsyncCallsPerContext
+		if uint64(len(currentContextMap[string(contextIdentifier)].AsyncCalls)) >= maxAsyncCalls {
+			return vmhost.ErrTooManyAsyncCalls
+		}
+	}
+
 	currentContextMap[string(contextIdentifier)].AsyncCalls =
 		append(currentContextMap[string(contextIdentifier)].AsyncCalls, asyncCall)
 
@@ -955,6 +1046,40 @@ func (context *runtimeContext) GetAllErrors() error {
 	return context.errors
 }
 
+// SetFunctionCallTracer sets the tracer notified whenever an exported function
+// of a contract is resolved for execution; a nil tracer disables tracing
+func (context *runtimeContext) SetFunctionCallTracer(tracer vmhost.FunctionCallTracer) {
+	context.functionCallTracer = tracer
+}
+
+func (context *runtimeContext) setInstanceCodeHashForTracing(contract []byte) {
+	context.instanceCodeHash = nil
+	if context.functionCallTracer == nil {
+		return
+	}
+
+	codeHash, err := context.host.Crypto().Sha256(contract)
+	if err != nil {
+		logRuntime.Trace("function call tracing", "error", err)
+		return
+	}
+
+	context.instanceCodeHash = codeHash
+}
+
+func (context *runtimeContext) traceFunctionCall(functionName string, exports wasmer.ExportsMap) {
+	if context.functionCallTracer == nil {
+		return
+	}
+
+	exportedFunctions := make([]string, 0, len(exports))
+	for name := range exports {
+		exportedFunctions = append(exportedFunctions, name)
+	}
+
+	context.functionCallTracer.TraceFunctionCall(context.instanceCodeHash, functionName, exportedFunctions)
+}
+
 // SetWarmInstance overwrites the warm Wasmer instance with the provided one.
 // TODO remove after implementing proper mocking of Wasmer instances; this is
 // used for tests only
@@ -338,6 +338,73 @@ func TestRuntimeContext_Instance(t *testing.T) {
 	require.Nil(t, runtimeContext.instance)
 }
 
+type tracedFunctionCall struct {
+	codeHash          []byte
+	functionName      string
+	exportedFunctions []string
+}
+
+type functionCallTracerStub struct {
+	calls []tracedFunctionCall
+}
+
+func (tracer *functionCallTracerStub) TraceFunctionCall(codeHash []byte, functionName string, exportedFunctions []string) {
+	tracer.calls = append(tracer.calls, tracedFunctionCall{
+		codeHash:          codeHash,
+		functionName:      functionName,
+		exportedFunctions: exportedFunctions,
+	})
+}
+
+func TestRuntimeContext_FunctionCallTracer(t *testing.T) {
+	host := InitializeVMAndWasmer()
+
+	vmType := []byte("type")
+	runtimeContext, _ := NewRuntimeContext(host, vmType, false, builtInFunctions.NewBuiltInFunctionContainer())
+	runtimeContext.SetMaxInstanceCount(1)
+
+	tracer := &functionCallTracerStub{}
+	runtimeContext.SetFunctionCallTracer(tracer)
+
+	gasLimit := uint64(100000000)
+	contractCode := vmhost.GetSCCode(counterWasmCode)
+	err := runtimeContext.StartWasmerInstance(contractCode, gasLimit, false)
+	require.Nil(t, err)
+
+	input := &vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallValue: big.NewInt(0),
+		},
+		RecipientAddr: []byte("addr"),
+		Function:      "increment",
+	}
+	runtimeContext.InitStateFromContractCallInput(input)
+
+	_, err = runtimeContext.GetFunctionToCall()
+	require.Nil(t, err)
+
+	input.Function = "func"
+	runtimeContext.InitStateFromContractCallInput(input)
+	_, err = runtimeContext.GetFunctionToCall()
+	require.Equal(t, vmhost.ErrFuncNotFound, err)
+
+	expectedCodeHash, _ := host.Crypto().Sha256(contractCode)
+	require.Len(t, tracer.calls, 1)
+	require.Equal(t, expectedCodeHash, tracer.calls[0].codeHash)
+	require.Equal(t, "increment", tracer.calls[0].functionName)
+	require.Contains(t, tracer.calls[0].exportedFunctions, "increment")
+	require.Contains(t, tracer.calls[0].exportedFunctions, vmhost.InitFunctionName)
+
+	runtimeContext.SetFunctionCallTracer(nil)
+	input.Function = "increment"
+	runtimeContext.InitStateFromContractCallInput(input)
+	_, err = runtimeContext.GetFunctionToCall()
+	require.Nil(t, err)
+	require.Len(t, tracer.calls, 1)
+
+	runtimeContext.CleanWasmerInstance()
+}
+
 func TestRuntimeContext_Breakpoints(t *testing.T) {
 	host := InitializeVMAndWasmer()
 
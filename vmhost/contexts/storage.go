@@ -3,6 +3,7 @@ package contexts
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"github.com/kalyan3104/k-chain-core-go/core/check"
 	logger "github.com/kalyan3104/k-chain-logger-go"
@@ -20,6 +21,15 @@ type storageContext struct {
 	stateStack                 [][]byte
 	protectedKeyPrefix         []byte
 	vmStorageProtectionEnabled bool
+
+	// accessedKeys holds the address+key pairs accessed during the current
+	// transaction, which are charged at the warm cost on further accesses
+	accessedKeys      map[string]struct{}
+	accessedKeysStack []map[string]struct{}
+
+	// storageSizes holds the storage size reported by the blockchain hook for each address
+	// before the changes counted in its output account were made during the current transaction
+	storageSizes map[string]uint64
 }
 
 // NewStorageContext creates a new storageContext
@@ -37,21 +47,34 @@ func NewStorageContext(
 		stateStack:                 make([][]byte, 0),
 		protectedKeyPrefix:         protectedKeyPrefix,
 		vmStorageProtectionEnabled: true,
+		accessedKeys:               make(map[string]struct{}),
+		accessedKeysStack:          make([]map[string]struct{}, 0),
+		storageSizes:               make(map[string]uint64),
 	}
 
 	return context, nil
 }
 
-// InitState does nothing
+// InitState forgets the storage keys accessed and the storage sizes read during the previous transaction
 func (context *storageContext) InitState() {
+	context.accessedKeys = make(map[string]struct{})
+	context.storageSizes = make(map[string]uint64)
 }
 
-// PushState appends the current address to the state stack.
+// PushState appends the current address and a copy of the accessed keys to the state stack.
 func (context *storageContext) PushState() {
 	context.stateStack = append(context.stateStack, context.address)
+
+	accessedKeys := make(map[string]struct{}, len(context.accessedKeys))
+	for accessedKey := range context.accessedKeys {
+		accessedKeys[accessedKey] = struct{}{}
+	}
+	context.accessedKeysStack = append(context.accessedKeysStack, accessedKeys)
 }
 
-// PopSetActiveState removes the latest entry from the state stack and sets it as the current address
+// PopSetActiveState removes the latest entry from the state stack and sets it as the current
+// address, restoring the keys accessed before the entry was pushed; the keys warmed up by a
+// failed call are thus charged again at the cold cost.
 func (context *storageContext) PopSetActiveState() {
 	stateStackLen := len(context.stateStack)
 	if stateStackLen == 0 {
@@ -62,6 +85,22 @@ func (context *storageContext) PopSetActiveState() {
 	context.stateStack = context.stateStack[:stateStackLen-1]
 
 	context.address = prevAddress
+	context.accessedKeys = context.popAccessedKeys()
+}
+
+// PopMergeActiveState removes the latest entry from the state stack and sets it as the current
+// address, keeping the keys accessed since the entry was pushed
+func (context *storageContext) PopMergeActiveState() {
+	stateStackLen := len(context.stateStack)
+	if stateStackLen == 0 {
+		return
+	}
+
+	prevAddress := context.stateStack[stateStackLen-1]
+	context.stateStack = context.stateStack[:stateStackLen-1]
+
+	context.address = prevAddress
+	_ = context.popAccessedKeys()
 }
 
 // PopDiscard removes the latest entry from the state stack
@@ -72,11 +111,24 @@ func (context *storageContext) PopDiscard() {
 	}
 
 	context.stateStack = context.stateStack[:stateStackLen-1]
+	_ = context.popAccessedKeys()
 }
 
 // ClearStateStack clears the state stack from the current context.
 func (context *storageContext) ClearStateStack() {
 	context.stateStack = make([][]byte, 0)
+	context.accessedKeysStack = make([]map[string]struct{}, 0)
+}
+
+func (context *storageContext) popAccessedKeys() map[string]struct{} {
+	stackLen := len(context.accessedKeysStack)
+	if stackLen == 0 {
+		return context.accessedKeys
+	}
+
+	accessedKeys := context.accessedKeysStack[stackLen-1]
+	context.accessedKeysStack = context.accessedKeysStack[:stackLen-1]
+	return accessedKeys
 }
 
 // SetAddress sets the given address as the address for the current context.
@@ -85,6 +137,79 @@ func (context *storageContext) SetAddress(address []byte) {
 	logStorage.Trace("storage under address set", "address", address)
 }
 
+// PrewarmStorageKeys charges the cold access cost for each of the keys declared
+// in the access list and marks them as warm for the rest of the transaction.
+// The access list is ignored while the warm and cold pricing is not enabled.
+func (context *storageContext) PrewarmStorageKeys(accessList vmhost.StorageAccessList) error {
+	if len(accessList) == 0 || !context.host.IsStorageAccessCostEnabled() {
+		return nil
+	}
+
+	metering := context.host.Metering()
+	coldAccess := metering.GasSchedule().StorageAccessCost.ColdAccess
+
+	for _, entry := range accessList {
+		if entry == nil {
+			continue
+		}
+		for _, key := range entry.Keys {
+			accessedKey := string(entry.Address) + string(key)
+			if _, warm := context.accessedKeys[accessedKey]; warm {
+				continue
+			}
+
+			err := metering.UseGasBounded(coldAccess)
+			if err != nil {
+				return err
+			}
+			context.accessedKeys[accessedKey] = struct{}{}
+		}
+	}
+
+	logStorage.Trace("storage keys prewarmed", "accessed keys", len(context.accessedKeys))
+	return nil
+}
+
+// useGasForKeyAccess charges the warm or the cold access cost for the given key,
+// then marks the key as warm; it returns whether the key was already warm.
+// While the warm and cold pricing is not enabled, nothing is charged or tracked
+// and every access is priced as cold.
+func (context *storageContext) useGasForKeyAccess(address []byte, key []byte) bool {
+	if !context.host.IsStorageAccessCostEnabled() {
+		return false
+	}
+
+	metering := context.host.Metering()
+	accessCost := metering.GasSchedule().StorageAccessCost
+
+	accessedKey := string(address) + string(key)
+	_, warm := context.accessedKeys[accessedKey]
+	if warm {
+		metering.UseGas(accessCost.WarmAccess)
+		return true
+	}
+
+	metering.UseGas(accessCost.ColdAccess)
+	context.accessedKeys[accessedKey] = struct{}{}
+	return false
+}
+
+func (context *storageContext) dataCopyCostPerByte(warm bool) uint64 {
+	gasSchedule := context.host.Metering().GasSchedule()
+	if warm {
+		return gasSchedule.StorageAccessCost.WarmDataCopyPerByte
+	}
+	return gasSchedule.BaseOperationCost.DataCopyPerByte
+}
+
+func (context *storageContext) persistCostPerByte(warm bool) uint64 {
+	gasSchedule := context.host.Metering().GasSchedule()
+	if warm {
+		return gasSchedule.StorageAccessCost.WarmPersistPerByte
+	}
+	return gasSchedule.BaseOperationCost.PersistPerByte
+}
+
 // GetStorageUpdates returns the storage updates for the account mapped to the given address.
 func (context *storageContext) GetStorageUpdates(address []byte) map[string]*vmcommon.StorageUpdate {
 	account, _ := context.host.Output().GetOutputAccount(address)
@@ -101,9 +226,10 @@ func (context *storageContext) GetStorage(key []byte) []byte {
 		metering.UseGas(gasToUse)
 	}
 
+	warm := context.useGasForKeyAccess(context.address, key)
 	value := context.GetStorageUnmetered(key)
 
-	gasToUse := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, uint64(len(value)))
+	gasToUse := math.MulUint64(context.dataCopyCostPerByte(warm), uint64(len(value)))
 	metering.UseGas(gasToUse)
 
 	logStorage.Trace("get", "key", key, "value", value)
@@ -133,6 +259,8 @@ func (context *storageContext) GetStorageFromAddress(address []byte, key []byte)
 		}
 	}
 
+	warm := context.useGasForKeyAccess(address, key)
+
 	// If the requested key is protected by the node, the stored value
 	// could have been changed by a built-in function in the meantime, even if
 	// contracts themselves cannot change protected values. Values stored under
@@ -145,8 +273,7 @@ func (context *storageContext) GetStorageFromAddress(address []byte, key []byte)
 		value = context.getStorageFromAddressUnmetered(address, key)
 	}
 
-	costPerByte := metering.GasSchedule().BaseOperationCost.DataCopyPerByte
-	gasToUse := math.MulUint64(costPerByte, uint64(len(value)))
+	gasToUse := math.MulUint64(context.dataCopyCostPerByte(warm), uint64(len(value)))
 	metering.UseGas(gasToUse)
 
 	logStorage.Trace("get from address", "address", address, "key", key, "value", value)
@@ -175,6 +302,173 @@ func (context *storageContext) GetStorageUnmetered(key []byte) []byte {
 	return context.getStorageFromAddressUnmetered(context.address, key)
 }
 
+// IterateStorage returns at most limit entries of the storage of the current address whose keys
+// start with the given prefix and come strictly after the given cursor, sorted by key. Storage
+// changes made earlier in the transaction are visible. Iteration is only allowed in queries and
+// read-only calls.
+func (context *storageContext) IterateStorage(prefix []byte, cursor []byte, limit int) ([]*vmhost.StorageEntry, error) {
+	runtime := context.host.Runtime()
+	if !runtime.QueryMode() && !runtime.ReadOnly() {
+		return nil, vmhost.ErrStorageIterationNotAllowed
+	}
+
+	iterator, ok := context.blockChainHook.(vmhost.StorageIterator)
+	if !ok {
+		return nil, vmhost.ErrStorageIterationNotSupported
+	}
+
+	if limit > vmhost.MaxStorageIterationLimit {
+		limit = vmhost.MaxStorageIterationLimit
+	}
+	if limit <= 0 {
+		return make([]*vmhost.StorageEntry, 0), nil
+	}
+
+	// values under protocol protected keys are always read from the node, see GetStorageFromAddress
+	overlay := make(map[string][]byte)
+	for key, update := range context.GetStorageUpdates(context.address) {
+		if context.isProtocolProtectedKey(update.Offset) || !vmhost.IsStorageKeyInRange(update.Offset, prefix, cursor) {
+			continue
+		}
+		overlay[key] = update.Data
+	}
+
+	// pending deletions may hide entries returned by the node, so more entries are requested
+	entries, err := iterator.IterateStorage(context.address, prefix, cursor, limit+len(overlay))
+	if err != nil {
+		return nil, err
+	}
+
+	result := vmhost.MergeStorageEntries(entries, overlay, prefix, cursor, limit)
+	logStorage.Trace("iterate", "prefix", prefix, "cursor", cursor, "entries", len(result))
+
+	return result, nil
+}
+
+// GetStorageSize returns the number of bytes stored by the current address, including the
+// storage changes made earlier in the transaction.
+func (context *storageContext) GetStorageSize() (uint64, error) {
+	if !context.host.IsStorageSizeEnabled() {
+		return 0, vmhost.ErrStorageSizeNotEnabled
+	}
+
+	size, err := context.initialStorageSize(context.address)
+	if err != nil {
+		return 0, err
+	}
+
+	account, _ := context.host.Output().GetOutputAccount(context.address)
+	size = math.AddUint64(size, account.BytesAddedToStorage)
+	if size < account.BytesDeletedFromStorage {
+		return 0, nil
+	}
+
+	return size - account.BytesDeletedFromStorage, nil
+}
+
+// GetBlockchainStorageSizes returns the storage sizes currently reported by the blockchain hook
+// for the given addresses, to be passed to AddStorageSizeChanges once a built-in function wrote
+// directly into their storage; it returns nil if the storage size is not tracked.
+func (context *storageContext) GetBlockchainStorageSizes(addresses [][]byte) map[string]uint64 {
+	if !context.host.IsStorageSizeEnabled() {
+		return nil
+	}
+	provider, ok := context.blockChainHook.(vmhost.StorageSizeProvider)
+	if !ok {
+		return nil
+	}
+
+	sizes := make(map[string]uint64, len(addresses))
+	for _, address := range addresses {
+		size, err := provider.GetStorageSize(address)
+		if err != nil {
+			logStorage.Trace("get blockchain storage size", "address", address, "error", err)
+			continue
+		}
+
+		// the changes made from now on are counted in the output account
+		_, known := context.storageSizes[string(address)]
+		if !known {
+			context.storageSizes[string(address)] = size
+		}
+		sizes[string(address)] = size
+	}
+
+	return sizes
+}
+
+// AddStorageSizeChanges compares the storage sizes returned by GetBlockchainStorageSizes with the
+// ones currently reported by the blockchain hook and adds the differences to the output accounts
+// of the given vmOutput.
+func (context *storageContext) AddStorageSizeChanges(vmOutput *vmcommon.VMOutput, sizesBefore map[string]uint64) {
+	provider, ok := context.blockChainHook.(vmhost.StorageSizeProvider)
+	if !ok || vmOutput == nil {
+		return
+	}
+
+	for address, sizeBefore := range sizesBefore {
+		sizeAfter, err := provider.GetStorageSize([]byte(address))
+		if err != nil || sizeAfter == sizeBefore {
+			continue
+		}
+
+		account, ok := vmOutput.OutputAccounts[address]
+		if !ok {
+			account = NewVMOutputAccount([]byte(address))
+			vmOutput.OutputAccounts[address] = account
+		}
+		if sizeAfter > sizeBefore {
+			account.BytesAddedToStorage = math.AddUint64(account.BytesAddedToStorage, sizeAfter-sizeBefore)
+		} else {
+			account.BytesDeletedFromStorage = math.AddUint64(account.BytesDeletedFromStorage, sizeBefore-sizeAfter)
+		}
+	}
+}
+
+// initialStorageSize returns the storage size of the given address before the changes counted
+// in its output account; the blockchain hook already reflects the writes of built-in functions.
+func (context *storageContext) initialStorageSize(address []byte) (uint64, error) {
+	if !context.host.IsStorageSizeEnabled() {
+		return 0, vmhost.ErrStorageSizeNotEnabled
+	}
+
+	size, ok := context.storageSizes[string(address)]
+	if ok {
+		return size, nil
+	}
+
+	provider, ok := context.blockChainHook.(vmhost.StorageSizeProvider)
+	if !ok {
+		return 0, vmhost.ErrStorageSizeNotSupported
+	}
+
+	size, err := provider.GetStorageSize(address)
+	if err != nil {
+		return 0, err
+	}
+
+	context.storageSizes[string(address)] = size
+	return size, nil
+}
+
+// updateStorageSize accumulates the change of the storage size of the current address into
+// its output account; the net change is BytesAddedToStorage minus BytesDeletedFromStorage.
+func (context *storageContext) updateStorageSize(key []byte, oldValue []byte, newValue []byte) {
+	if !context.host.IsStorageSizeEnabled() {
+		return
+	}
+
+	account, _ := context.host.Output().GetOutputAccount(context.address)
+
+	oldSize := vmhost.StorageEntrySize(key, oldValue)
+	newSize := vmhost.StorageEntrySize(key, newValue)
+	if newSize > oldSize {
+		account.BytesAddedToStorage = math.AddUint64(account.BytesAddedToStorage, newSize-oldSize)
+	} else {
+		account.BytesDeletedFromStorage = math.AddUint64(account.BytesDeletedFromStorage, oldSize-newSize)
+	}
+}
+
 // enableStorageProtection will prevent writing to protected keys
 func (context *storageContext) enableStorageProtection() {
 	context.vmStorageProtectionEnabled = true
@@ -202,7 +496,15 @@ func (context *storageContext) SetProtectedStorage(key []byte, value []byte) (vm
 
 // SetStorage sets the given value at the given key.
 func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.StorageStatus, error) {
+	if context.host.Runtime().QueryMode() {
+		logStorage.Trace("storage set", "error", vmhost.ErrStateChangeInQuery, "key", key)
+		return vmhost.StorageUnchanged, vmhost.ErrStateChangeInQuery
+	}
 	if context.host.Runtime().ReadOnly() {
+		if context.host.IsStrictReadOnlyEnabled() {
+			logStorage.Trace("storage set", "error", vmhost.ErrStateChangeInReadOnly, "key", key)
+			return vmhost.StorageUnchanged, fmt.Errorf("%w: storage write", vmhost.ErrStateChangeInReadOnly)
+		}
 		logStorage.Trace("storage set", "error", "cannot set storage in readonly mode")
 		return vmhost.StorageUnchanged, nil
 	}
@@ -223,6 +525,8 @@ func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.Stor
 		metering.UseGas(gasToUse)
 	}
 
+	warm := context.useGasForKeyAccess(context.address, key)
+
 	var zero []byte
 	strKey := string(key)
 	length := len(value)
@@ -241,7 +545,7 @@ func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.Stor
 
 	lengthOldValue := len(oldValue)
 	if bytes.Equal(oldValue, value) {
-		useGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, uint64(length))
+		useGas := math.MulUint64(context.dataCopyCostPerByte(warm), uint64(length))
 		metering.UseGas(useGas)
 		logStorage.Trace("storage set to identical value")
 		return vmhost.StorageUnchanged, nil
@@ -253,6 +557,7 @@ func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.Stor
 	}
 	copy(newUpdate.Data[:length], value[:length])
 	storageUpdates[strKey] = newUpdate
+	context.updateStorageSize(key, oldValue, value)
 
 	if bytes.Equal(oldValue, zero) {
 		useGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.StorePerByte, uint64(length))
@@ -270,7 +575,7 @@ func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.Stor
 	newValueExtraLength := math.SubInt(length, lengthOldValue)
 
 	if newValueExtraLength > 0 {
-		useGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.PersistPerByte, uint64(lengthOldValue))
+		useGas := math.MulUint64(context.persistCostPerByte(warm), uint64(lengthOldValue))
 		newValStoreUseGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.StorePerByte, uint64(newValueExtraLength))
 		gasUsed := math.AddUint64(useGas, newValStoreUseGas)
 
@@ -280,7 +585,7 @@ func (context *storageContext) SetStorage(key []byte, value []byte) (vmhost.Stor
 	if newValueExtraLength < 0 {
 		newValueExtraLength = -newValueExtraLength
 
-		useGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.PersistPerByte, uint64(length))
+		useGas := math.MulUint64(context.persistCostPerByte(warm), uint64(length))
 		metering.UseGas(useGas)
 
 		freeGas := math.MulUint64(metering.GasSchedule().BaseOperationCost.ReleasePerByte, uint64(newValueExtraLength))
@@ -202,6 +202,47 @@ func TestStorageContext_SetStorage(t *testing.T) {
 	require.Equal(t, vmhost.ErrStoreReservedKey, err)
 }
 
+func TestStorageContext_SetStorage_StrictReadOnly(t *testing.T) {
+	t.Parallel()
+
+	address := []byte("account")
+	mockOutput := &contextmock.OutputContextMock{}
+	account := mockOutput.NewVMOutputAccount(address)
+	mockOutput.OutputAccountMock = account
+	mockOutput.OutputAccountIsNew = false
+
+	mockRuntime := &contextmock.RuntimeContextMock{}
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+	mockMetering.BlockGasLimitMock = uint64(15000)
+
+	host := &contextmock.VMHostMock{
+		OutputContext:   mockOutput,
+		MeteringContext: mockMetering,
+		RuntimeContext:  mockRuntime,
+		StrictReadOnly:  true,
+	}
+	bcHook := &contextmock.BlockchainHookStub{}
+
+	storageContext, _ := NewStorageContext(host, bcHook, reservedTestPrefix)
+	storageContext.SetAddress(address)
+
+	key := []byte("key")
+	value := []byte("value")
+
+	mockRuntime.SetReadOnly(true)
+	storageStatus, err := storageContext.SetStorage(key, value)
+	require.True(t, errors.Is(err, vmhost.ErrStateChangeInReadOnly))
+	require.Equal(t, vmhost.StorageUnchanged, storageStatus)
+	require.Len(t, storageContext.GetStorageUpdates(address), 0)
+
+	mockRuntime.SetReadOnly(false)
+	storageStatus, err = storageContext.SetStorage(key, value)
+	require.Nil(t, err)
+	require.Equal(t, vmhost.StorageAdded, storageStatus)
+	require.Len(t, storageContext.GetStorageUpdates(address), 1)
+}
+
 func TestStorageContext_StorageProtection(t *testing.T) {
 	address := []byte("account")
 	mockOutput := &contextmock.OutputContextMock{}
@@ -298,6 +339,335 @@ func TestStorageContext_GetStorageFromAddress(t *testing.T) {
 	require.Nil(t, data)
 }
 
+func TestStorageContext_AccessedKeys(t *testing.T) {
+	t.Parallel()
+
+	scAddress := []byte("account")
+	otherAddress := []byte("other")
+	mockOutput := &contextmock.OutputContextMock{}
+	account := mockOutput.NewVMOutputAccount(scAddress)
+	mockOutput.OutputAccountMock = account
+	mockOutput.OutputAccountIsNew = false
+
+	mockRuntime := &contextmock.RuntimeContextMock{}
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+	mockMetering.BlockGasLimitMock = uint64(15000)
+
+	host := &contextmock.VMHostMock{
+		OutputContext:   mockOutput,
+		MeteringContext: mockMetering,
+		RuntimeContext:  mockRuntime,
+	}
+	bcHook := &contextmock.BlockchainHookStub{
+		GetUserAccountCalled: func(address []byte) (vmcommon.UserAccountHandler, error) {
+			return &worldmock.Account{CodeMetadata: []byte{0, 0}}, nil
+		},
+	}
+
+	storageContext, _ := NewStorageContext(host, bcHook, reservedTestPrefix)
+	storageContext.SetAddress(scAddress)
+
+	key := []byte("key")
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, key))
+	require.True(t, storageContext.useGasForKeyAccess(scAddress, key))
+	require.False(t, storageContext.useGasForKeyAccess(otherAddress, key))
+
+	_, err := storageContext.SetStorage([]byte("written"), []byte("value"))
+	require.Nil(t, err)
+	_ = storageContext.GetStorage([]byte("read"))
+	_ = storageContext.GetStorageFromAddress(otherAddress, []byte("unreadable"))
+	require.Len(t, storageContext.accessedKeys, 4)
+
+	storageContext.InitState()
+	require.Len(t, storageContext.accessedKeys, 0)
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, key))
+}
+
+func TestStorageContext_PrewarmStorageKeys(t *testing.T) {
+	t.Parallel()
+
+	scAddress := []byte("account")
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+
+	host := &contextmock.VMHostMock{
+		MeteringContext: mockMetering,
+	}
+	bcHook := &contextmock.BlockchainHookStub{}
+
+	storageContext, _ := NewStorageContext(host, bcHook, reservedTestPrefix)
+	storageContext.SetAddress(scAddress)
+
+	accessList := vmhost.StorageAccessList{
+		{Address: scAddress, Keys: [][]byte{[]byte("key1"), []byte("key2")}},
+		nil,
+		{Address: []byte("other"), Keys: [][]byte{[]byte("key1")}},
+	}
+	err := storageContext.PrewarmStorageKeys(accessList)
+	require.Nil(t, err)
+	require.Len(t, storageContext.accessedKeys, 3)
+	require.True(t, storageContext.useGasForKeyAccess(scAddress, []byte("key2")))
+
+	storageContext.InitState()
+	mockMetering.Err = vmhost.ErrNotEnoughGas
+	err = storageContext.PrewarmStorageKeys(accessList)
+	require.Equal(t, vmhost.ErrNotEnoughGas, err)
+	require.Len(t, storageContext.accessedKeys, 0)
+}
+
+func TestStorageContext_AccessCostDisabled(t *testing.T) {
+	t.Parallel()
+
+	scAddress := []byte("account")
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+	mockMetering.Err = vmhost.ErrNotEnoughGas
+
+	host := &contextmock.VMHostMock{
+		MeteringContext:           mockMetering,
+		StorageAccessCostDisabled: true,
+	}
+	bcHook := &contextmock.BlockchainHookStub{}
+
+	storageContext, _ := NewStorageContext(host, bcHook, reservedTestPrefix)
+	storageContext.SetAddress(scAddress)
+
+	accessList := vmhost.StorageAccessList{
+		{Address: scAddress, Keys: [][]byte{[]byte("key1")}},
+	}
+	err := storageContext.PrewarmStorageKeys(accessList)
+	require.Nil(t, err)
+
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, []byte("key1")))
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, []byte("key1")))
+	require.Len(t, storageContext.accessedKeys, 0)
+}
+
+func TestStorageContext_AccessedKeysStateStack(t *testing.T) {
+	t.Parallel()
+
+	scAddress := []byte("account")
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+
+	host := &contextmock.VMHostMock{
+		MeteringContext: mockMetering,
+	}
+	bcHook := &contextmock.BlockchainHookStub{}
+
+	storageContext, _ := NewStorageContext(host, bcHook, reservedTestPrefix)
+	storageContext.SetAddress(scAddress)
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, []byte("key1")))
+
+	storageContext.PushState()
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, []byte("key2")))
+	storageContext.PopSetActiveState()
+	require.Len(t, storageContext.accessedKeys, 1)
+	require.True(t, storageContext.useGasForKeyAccess(scAddress, []byte("key1")))
+
+	storageContext.PushState()
+	require.False(t, storageContext.useGasForKeyAccess(scAddress, []byte("key2")))
+	storageContext.PopMergeActiveState()
+	require.Len(t, storageContext.accessedKeys, 2)
+	require.True(t, storageContext.useGasForKeyAccess(scAddress, []byte("key2")))
+	require.Len(t, storageContext.accessedKeysStack, 0)
+}
+
+func TestStorageContext_IterateStorage(t *testing.T) {
+	t.Parallel()
+
+	address := []byte("account")
+	mockOutput := &contextmock.OutputContextMock{}
+	account := mockOutput.NewVMOutputAccount(address)
+	mockOutput.OutputAccountMock = account
+	mockOutput.OutputAccountIsNew = false
+
+	mockRuntime := &contextmock.RuntimeContextMock{}
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+
+	host := &contextmock.VMHostMock{
+		OutputContext:   mockOutput,
+		MeteringContext: mockMetering,
+		RuntimeContext:  mockRuntime,
+	}
+
+	world := worldmock.NewMockWorld()
+	world.AcctMap.PutAccount(&worldmock.Account{
+		Address: address,
+		Storage: map[string][]byte{
+			"item1": []byte("value1"),
+			"item2": []byte("value2"),
+			"item3": []byte("value3"),
+			"other": []byte("other"),
+		},
+	})
+
+	storageContext, _ := NewStorageContext(host, world, reservedTestPrefix)
+	storageContext.SetAddress(address)
+
+	_, err := storageContext.SetStorage([]byte("item0"), []byte("value0"))
+	require.Nil(t, err)
+	_, err = storageContext.SetStorage([]byte("item1"), nil)
+	require.Nil(t, err)
+
+	entries, err := storageContext.IterateStorage([]byte("item"), nil, 10)
+	require.Equal(t, vmhost.ErrStorageIterationNotAllowed, err)
+	require.Nil(t, entries)
+
+	mockRuntime.SetReadOnly(true)
+	entries, err = storageContext.IterateStorage([]byte("item"), nil, 10)
+	require.Nil(t, err)
+	require.Equal(t, []*vmhost.StorageEntry{
+		{Key: []byte("item0"), Value: []byte("value0")},
+		{Key: []byte("item2"), Value: []byte("value2")},
+		{Key: []byte("item3"), Value: []byte("value3")},
+	}, entries)
+
+	entries, err = storageContext.IterateStorage([]byte("item"), nil, 2)
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, []byte("item2"), entries[1].Key)
+
+	entries, err = storageContext.IterateStorage([]byte("item"), []byte("item2"), 2)
+	require.Nil(t, err)
+	require.Equal(t, []*vmhost.StorageEntry{{Key: []byte("item3"), Value: []byte("value3")}}, entries)
+
+	storageContext, _ = NewStorageContext(host, &contextmock.BlockchainHookStub{}, reservedTestPrefix)
+	storageContext.SetAddress(address)
+	_, err = storageContext.IterateStorage([]byte("item"), nil, 10)
+	require.Equal(t, vmhost.ErrStorageIterationNotSupported, err)
+}
+
+func TestStorageContext_StorageSize(t *testing.T) {
+	t.Parallel()
+
+	address := []byte("account")
+	mockOutput := &contextmock.OutputContextMock{}
+	account := mockOutput.NewVMOutputAccount(address)
+	mockOutput.OutputAccountMock = account
+	mockOutput.OutputAccountIsNew = false
+
+	mockRuntime := &contextmock.RuntimeContextMock{}
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+
+	host := &contextmock.VMHostMock{
+		OutputContext:   mockOutput,
+		MeteringContext: mockMetering,
+		RuntimeContext:  mockRuntime,
+	}
+
+	world := worldmock.NewMockWorld()
+	world.AcctMap.PutAccount(&worldmock.Account{
+		Address: address,
+		Storage: map[string][]byte{
+			"key1": []byte("value1"),
+			"key2": []byte("value2"),
+		},
+	})
+
+	storageContext, _ := NewStorageContext(host, world, reservedTestPrefix)
+	storageContext.SetAddress(address)
+
+	size, err := storageContext.GetStorageSize()
+	require.Nil(t, err)
+	require.Equal(t, uint64(20), size)
+
+	_, err = storageContext.SetStorage([]byte("key3"), []byte("value3"))
+	require.Nil(t, err)
+	_, err = storageContext.SetStorage([]byte("key1"), []byte("v1"))
+	require.Nil(t, err)
+	_, err = storageContext.SetStorage([]byte("key2"), nil)
+	require.Nil(t, err)
+	_, err = storageContext.SetStorage([]byte("key3"), []byte("value3"))
+	require.Nil(t, err)
+
+	require.Equal(t, uint64(10), account.BytesAddedToStorage)
+	require.Equal(t, uint64(14), account.BytesDeletedFromStorage)
+
+	size, err = storageContext.GetStorageSize()
+	require.Nil(t, err)
+	require.Equal(t, uint64(16), size)
+
+	storageContext, _ = NewStorageContext(host, &contextmock.BlockchainHookStub{}, reservedTestPrefix)
+	storageContext.SetAddress(address)
+	_, err = storageContext.GetStorageSize()
+	require.Equal(t, vmhost.ErrStorageSizeNotSupported, err)
+}
+
+func TestStorageContext_StorageSizeDisabled(t *testing.T) {
+	t.Parallel()
+
+	address := []byte("account")
+	host := &contextmock.VMHostMock{
+		StorageSizeDisabled: true,
+	}
+
+	world := worldmock.NewMockWorld()
+	world.AcctMap.PutAccount(&worldmock.Account{
+		Address: address,
+		Storage: map[string][]byte{"key1": []byte("value1")},
+	})
+
+	storageContext, _ := NewStorageContext(host, world, reservedTestPrefix)
+	storageContext.SetAddress(address)
+
+	_, err := storageContext.GetStorageSize()
+	require.Equal(t, vmhost.ErrStorageSizeNotEnabled, err)
+
+	_, err = storageContext.initialStorageSize(address)
+	require.Equal(t, vmhost.ErrStorageSizeNotEnabled, err)
+	require.Len(t, storageContext.storageSizes, 0)
+}
+
+func TestStorageContext_StorageSizeOfBuiltinFunction(t *testing.T) {
+	t.Parallel()
+
+	address := []byte("account")
+	mockOutput := &contextmock.OutputContextMock{}
+	account := mockOutput.NewVMOutputAccount(address)
+	mockOutput.OutputAccountMock = account
+	mockOutput.OutputAccountIsNew = false
+
+	mockMetering := &contextmock.MeteringContextMock{}
+	mockMetering.SetGasSchedule(config.MakeGasMapForTests())
+
+	host := &contextmock.VMHostMock{
+		OutputContext:   mockOutput,
+		MeteringContext: mockMetering,
+		RuntimeContext:  &contextmock.RuntimeContextMock{},
+	}
+
+	world := worldmock.NewMockWorld()
+	world.AcctMap.PutAccount(&worldmock.Account{
+		Address: address,
+		Storage: map[string][]byte{
+			"key1": []byte("value1"),
+		},
+	})
+
+	storageContext, _ := NewStorageContext(host, world, reservedTestPrefix)
+	storageContext.SetAddress(address)
+
+	// a built-in function writes directly into the storage of the account
+	sizesBefore := storageContext.GetBlockchainStorageSizes([][]byte{address})
+	require.Equal(t, map[string]uint64{string(address): 10}, sizesBefore)
+	world.AcctMap.GetAccount(address).Storage["key2"] = []byte("value2")
+
+	builtinOutput := &vmcommon.VMOutput{OutputAccounts: make(map[string]*vmcommon.OutputAccount)}
+	storageContext.AddStorageSizeChanges(builtinOutput, sizesBefore)
+	require.Equal(t, uint64(10), builtinOutput.OutputAccounts[string(address)].BytesAddedToStorage)
+
+	// the built-in function output merged into the active state
+	account.BytesAddedToStorage = builtinOutput.OutputAccounts[string(address)].BytesAddedToStorage
+
+	size, err := storageContext.GetStorageSize()
+	require.Nil(t, err)
+	require.Equal(t, uint64(20), size)
+}
+
 func TestStorageContext_LoadGasStoreGasPerKey(t *testing.T) {
 	// TODO
 }
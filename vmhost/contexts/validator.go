@@ -14,12 +14,37 @@ const noArity = -1
 // wasmValidator is a validator for WASM SmartContracts
 type wasmValidator struct {
 	reserved *reservedFunctions
+	apiNames vmcommon.FunctionNames
+}
+
+// ValidateContractInstance runs the checks applied to newly deployed contracts on the given instance,
+// outside of any execution
+func ValidateContractInstance(
+	instance wasmer.InstanceHandler,
+	scAPINames vmcommon.FunctionNames,
+	builtInFuncContainer vmcommon.BuiltInFunctionContainer,
+	isImportEnabled func(importName string) bool,
+) error {
+	validator := newWASMValidator(scAPINames, builtInFuncContainer)
+
+	err := validator.verifyMemoryDeclaration(instance)
+	if err != nil {
+		return err
+	}
+
+	err = validator.verifyFunctions(instance)
+	if err != nil {
+		return err
+	}
+
+	return validator.verifyImportsEnabled(instance, isImportEnabled)
 }
 
 // newWASMValidator creates a new WASMValidator
 func newWASMValidator(scAPINames vmcommon.FunctionNames, builtInFuncContainer vmcommon.BuiltInFunctionContainer) *wasmValidator {
 	return &wasmValidator{
 		reserved: NewReservedFunctions(scAPINames, builtInFuncContainer),
+		apiNames: scAPINames,
 	}
 }
 
@@ -31,6 +56,20 @@ func (validator *wasmValidator) verifyMemoryDeclaration(instance wasmer.Instance
 	return nil
 }
 
+// verifyImportsEnabled rejects contracts importing VM hooks which are not active yet
+func (validator *wasmValidator) verifyImportsEnabled(instance wasmer.InstanceHandler, isImportEnabled func(importName string) bool) error {
+	for importName := range validator.apiNames {
+		if isImportEnabled(importName) {
+			continue
+		}
+		if instance.IsFunctionImported(importName) {
+			return fmt.Errorf("%w: %s", vmhost.ErrImportNotEnabled, importName)
+		}
+	}
+
+	return nil
+}
+
 func (validator *wasmValidator) verifyFunctions(instance wasmer.InstanceHandler) error {
 	for functionName := range instance.GetExports() {
 		err := validator.verifyValidFunctionName(functionName)
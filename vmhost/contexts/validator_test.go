@@ -1,10 +1,12 @@
 package contexts
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
+	contextmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/mock"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
@@ -95,3 +97,23 @@ func TestFunctionsGuard_Arity(t *testing.T) {
 	err = validator.verifyVoidFunction(instance, "wrongParamsAndReturn")
 	require.NotNil(t, err)
 }
+
+func TestFunctionsGuard_ImportsEnabled(t *testing.T) {
+	imports := MakeAPIImports()
+	validator := newWASMValidator(imports.Names(), builtInFunctions.NewBuiltInFunctionContainer())
+
+	instance := contextmock.NewInstanceMock(nil)
+	instance.AddMockImport("getDCDTBalance")
+
+	allEnabled := func(_ string) bool { return true }
+	require.Nil(t, validator.verifyImportsEnabled(instance, allEnabled))
+
+	dcdtDisabled := func(importName string) bool { return importName != "getDCDTBalance" }
+	err := validator.verifyImportsEnabled(instance, dcdtDisabled)
+	require.True(t, errors.Is(err, vmhost.ErrImportNotEnabled))
+	require.True(t, errors.Is(err, vmhost.ErrContractInvalid))
+	require.Contains(t, err.Error(), "getDCDTBalance")
+
+	otherDisabled := func(importName string) bool { return importName != "transferValueExecute" }
+	require.Nil(t, validator.verifyImportsEnabled(instance, otherDisabled))
+}
@@ -80,6 +80,12 @@ var ErrContractNotFound = fmt.Errorf("%w (not found)", ErrContractInvalid)
 // ErrMemoryDeclarationMissing signals that a memory declaration is missing
 var ErrMemoryDeclarationMissing = fmt.Errorf("%w (missing memory declaration)", ErrContractInvalid)
 
+// ErrImportNotEnabled signals that the contract uses a VM hook which is not active yet
+var ErrImportNotEnabled = fmt.Errorf("%w (import not enabled)", ErrContractInvalid)
+
+// ErrInvalidImportFlag signals that the import flags registry refers to an unknown import or flag
+var ErrInvalidImportFlag = errors.New("invalid import flag registration")
+
 // ErrMaxInstancesReached signals that the max number of Wasmer instances has been reached.
 var ErrMaxInstancesReached = fmt.Errorf("%w (max instances reached)", ErrExecutionFailed)
 
@@ -116,6 +122,12 @@ var ErrShiftNegative = errors.New("bitwise shift operations only allowed on posi
 // ErrAsyncContextDoesNotExist signals that the async context does not exist
 var ErrAsyncContextDoesNotExist = errors.New("async context does not exist")
 
+// ErrTooManyAsyncCalls signals that an async context already holds the maximum number of async calls
+var ErrTooManyAsyncCalls = errors.New("too many async calls in async context")
+
+// ErrInvalidAsyncCallGas signals that a negative amount of gas was provided for an async call
+var ErrInvalidAsyncCallGas = errors.New("invalid gas provided for async call")
+
 // ErrInvalidAccount signals that a certain account does not exist
 var ErrInvalidAccount = errors.New("account does not exist")
 
@@ -152,3 +164,30 @@ var ErrTransferValueOnDCDTCall = errors.New("transfer value on dcdt call")
 
 // ErrNilEnableEpochsHandler signals that enable epochs handler is nil
 var ErrNilEnableEpochsHandler = errors.New("nil enable epochs handler")
+
+// ErrStateChangeInQuery signals that a query attempted to change the state
+var ErrStateChangeInQuery = errors.New("state changing operation not permitted in query")
+
+// ErrStateChangeInReadOnly signals that a state changing operation was attempted in read-only mode
+var ErrStateChangeInReadOnly = errors.New("state changing operation not permitted in read-only mode")
+
+// ErrStorageIterationNotAllowed signals that storage iteration was attempted outside of a query or a read-only call
+var ErrStorageIterationNotAllowed = errors.New("storage iteration only permitted in queries and read-only calls")
+
+// ErrStorageIterationNotSupported signals that the blockchain hook cannot enumerate the storage of accounts
+var ErrStorageIterationNotSupported = errors.New("storage iteration not supported by the blockchain hook")
+
+// ErrStorageSizeNotSupported signals that the blockchain hook cannot provide the storage size of accounts
+var ErrStorageSizeNotSupported = errors.New("storage size not supported by the blockchain hook")
+
+// ErrStorageSizeNotEnabled signals that the storage size was requested while its tracking is not enabled
+var ErrStorageSizeNotEnabled = errors.New("storage size tracking is not enabled")
+
+// ErrInvalidTokenIndex signals that the index of an incoming DCDT transfer is out of range
+var ErrInvalidTokenIndex = errors.New("invalid token index")
+
+// ErrGasEstimationFailed signals that the call being estimated fails even with the maximum gas limit
+var ErrGasEstimationFailed = errors.New("call fails with the maximum gas limit")
+
+// ErrNegativeRoyalties signals that the royalties given for a new NFT are negative
+var ErrNegativeRoyalties = errors.New("negative royalties")
@@ -0,0 +1,21 @@
+package vmhost
+
+// GasEstimationSafetyMarginPercent is the margin added on top of the minimum gas limit
+// of a call to obtain its recommended gas limit
+const GasEstimationSafetyMarginPercent = 10
+
+// GasEstimate holds the gas limits found for a call by VMHost.EstimateGas
+type GasEstimate struct {
+	// GasUsed is the gas consumed by the call when given the maximum gas limit,
+	// including the gas used by built-in functions and the gas given to async calls
+	GasUsed uint64
+	// GasLocked is the gas locked for the callbacks of the cross-shard async calls
+	GasLocked uint64
+	// MinimumGasLimit is the smallest gas limit with which the call still succeeds
+	MinimumGasLimit uint64
+	// RecommendedGasLimit is MinimumGasLimit with the safety margin added, capped
+	// at the maximum gas limit
+	RecommendedGasLimit uint64
+	// Executions counts the executions of the call performed by the estimation
+	Executions int
+}
@@ -175,6 +175,46 @@ func GetStorageContext(vmHostPtr unsafe.Pointer) StorageContext {
 	return GetVMHost(vmHostPtr).Storage()
 }
 
+// FailIfImportNotEnabled fails the execution and returns true if the import is gated by a flag which is not active yet
+func FailIfImportNotEnabled(vmHostPtr unsafe.Pointer, importName string) bool {
+	return FailIfImportNotEnabledWithHost(GetVMHost(vmHostPtr), importName)
+}
+
+// FailIfImportNotEnabledWithHost fails the execution and returns true if the import is gated by a flag which is not active yet
+func FailIfImportNotEnabledWithHost(host VMHost, importName string) bool {
+	if host.IsImportEnabled(importName) {
+		return false
+	}
+
+	return WithFaultAndHost(host, fmt.Errorf("%w: %s", ErrImportNotEnabled, importName), true)
+}
+
+// FailIfStateChangeInQuery fails the execution if the given import is called during a query,
+// since it would change the state
+func FailIfStateChangeInQuery(vmHostPtr unsafe.Pointer, importName string) bool {
+	host := GetVMHost(vmHostPtr)
+	if !host.Runtime().QueryMode() {
+		return false
+	}
+
+	return WithFaultAndHost(host, fmt.Errorf("%w: %s", ErrStateChangeInQuery, importName), true)
+}
+
+// FailIfStateChange fails the execution if the given import is called during a query, or in
+// read-only mode while strict read-only checks are enabled, since it would change the state
+func FailIfStateChange(vmHostPtr unsafe.Pointer, importName string) bool {
+	if FailIfStateChangeInQuery(vmHostPtr, importName) {
+		return true
+	}
+
+	host := GetVMHost(vmHostPtr)
+	if !host.Runtime().ReadOnly() || !host.IsStrictReadOnlyEnabled() {
+		return false
+	}
+
+	return WithFaultAndHost(host, fmt.Errorf("%w: %s", ErrStateChangeInReadOnly, importName), true)
+}
+
 // WithFault returns true if the error is not nil, and uses the remaining gas if the execution has failed
 func WithFault(err error, vmHostPtr unsafe.Pointer, failExecution bool) bool {
 	runtime := GetVMHost(vmHostPtr)
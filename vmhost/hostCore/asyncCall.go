@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"math/big"
+	"sort"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/data/vm"
@@ -478,8 +479,12 @@ func (host *vmHost) processAsyncInfo(asyncInfo *vmhost.AsyncContextInfo) (*vmhos
 		return nil, err
 	}
 
-	for _, asyncContext := range asyncInfo.AsyncContextMap {
-		for _, asyncCall := range asyncContext.AsyncCalls {
+	isCallbacksEnabled := host.isAsyncContextCallbacksEnabled()
+	for _, contextIdentifier := range host.asyncContextIdentifiers(asyncInfo) {
+		for _, asyncCall := range asyncInfo.AsyncContextMap[contextIdentifier].AsyncCalls {
+			if isCallbacksEnabled && asyncCall.Status != vmhost.AsyncCallPending {
+				continue
+			}
 			if !host.canExecuteSynchronously(asyncCall.Destination, asyncCall.Data) {
 				continue
 			}
@@ -491,6 +496,13 @@ func (host *vmHost) processAsyncInfo(asyncInfo *vmhost.AsyncContextInfo) (*vmhos
 		}
 	}
 
+	if isCallbacksEnabled {
+		err = host.executeCompletedAsyncContextCallbacks(asyncInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	pendingMapInfo := host.getPendingAsyncCalls(asyncInfo)
 	if len(pendingMapInfo.AsyncContextMap) == 0 {
 		return pendingMapInfo, nil
@@ -506,10 +518,15 @@ func (host *vmHost) processAsyncInfo(asyncInfo *vmhost.AsyncContextInfo) (*vmhos
 		return nil, err
 	}
 
-	for _, asyncContext := range pendingMapInfo.AsyncContextMap {
-		for _, asyncCall := range asyncContext.AsyncCalls {
+	for _, contextIdentifier := range host.asyncContextIdentifiers(pendingMapInfo) {
+		for _, asyncCall := range pendingMapInfo.AsyncContextMap[contextIdentifier].AsyncCalls {
 			if !host.canExecuteSynchronously(asyncCall.Destination, asyncCall.Data) {
-				sendErr := host.sendAsyncCallToDestination(asyncCall)
+				var sendErr error
+				if isCallbacksEnabled {
+					sendErr = host.sendAsyncGeneratedCallToDestination(asyncCall)
+				} else {
+					sendErr = host.sendAsyncCallToDestination(asyncCall)
+				}
 				if sendErr != nil {
 					return nil, sendErr
 				}
@@ -521,9 +538,37 @@ func (host *vmHost) processAsyncInfo(asyncInfo *vmhost.AsyncContextInfo) (*vmhos
 }
 
 /**
- * processAsyncCall executes an async call and processes the callback if no extra calls are pending
+ * processAsyncCall executes an async call with the gas set aside for it and processes the callback if no extra calls are pending.
+ *  The gas not used by the destination is returned to the caller, and then given to the callback
  */
 func (host *vmHost) processAsyncCall(asyncCall *vmhost.AsyncGeneratedCall) error {
+	if !host.isAsyncContextCallbacksEnabled() {
+		return host.processAsyncCallLegacy(asyncCall)
+	}
+
+	input, err := host.createDestinationContractCallInput(asyncCall)
+	if err != nil {
+		return err
+	}
+	input.GasProvided = asyncCall.GetGasLimit()
+
+	output, asyncMap, executionError := host.ExecuteOnDestContext(input)
+	if asyncMap == nil {
+		return host.callbackAsync(asyncCall, output, executionError)
+	}
+
+	pendingMap := host.getPendingAsyncCalls(asyncMap)
+	if len(pendingMap.AsyncContextMap) == 0 {
+		return host.callbackAsync(asyncCall, output, executionError)
+	}
+
+	return executionError
+}
+
+/**
+ * processAsyncCallLegacy executes an async call with the gas split by setupAsyncCallsGas and processes the callback if no extra calls are pending
+ */
+func (host *vmHost) processAsyncCallLegacy(asyncCall *vmhost.AsyncGeneratedCall) error {
 	input, _ := host.createDestinationContractCallInput(asyncCall)
 	output, asyncMap, executionError := host.ExecuteOnDestContext(input)
 
@@ -535,6 +580,93 @@ func (host *vmHost) processAsyncCall(asyncCall *vmhost.AsyncGeneratedCall) error
 	return executionError
 }
 
+/**
+ * executeCompletedAsyncContextCallbacks calls the completion callback of each async context whose calls are all resolved or rejected
+ */
+func (host *vmHost) executeCompletedAsyncContextCallbacks(asyncInfo *vmhost.AsyncContextInfo) error {
+	for _, contextIdentifier := range sortedAsyncContextIdentifiers(asyncInfo) {
+		asyncContext := asyncInfo.AsyncContextMap[contextIdentifier]
+		if !isAsyncContextCompleted(asyncContext) {
+			continue
+		}
+
+		err := host.executeAsyncContextCallback(asyncContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+ * executeAsyncContextCallback calls the completion callback of an async context on the current contract, if one was set
+ */
+func (host *vmHost) executeAsyncContextCallback(asyncContext *vmhost.AsyncContext) error {
+	if len(asyncContext.Callback) == 0 {
+		return nil
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	contractCallInput := &vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:     runtime.GetSCAddress(),
+			Arguments:      make([][]byte, 0),
+			CallValue:      big.NewInt(0),
+			CallType:       vm.DirectCall,
+			GasPrice:       runtime.GetVMInput().GasPrice,
+			GasProvided:    metering.GasLeft(),
+			CurrentTxHash:  runtime.GetCurrentTxHash(),
+			OriginalTxHash: runtime.GetOriginalTxHash(),
+		},
+		RecipientAddr: runtime.GetSCAddress(),
+		Function:      asyncContext.Callback,
+	}
+
+	callbackVMOutput, _, callBackErr := host.ExecuteOnDestContext(contractCallInput)
+	return host.processCallbackVMOutput(callbackVMOutput, callBackErr)
+}
+
+func isAsyncContextCompleted(asyncContext *vmhost.AsyncContext) bool {
+	for _, asyncCall := range asyncContext.AsyncCalls {
+		if asyncCall.Status == vmhost.AsyncCallPending {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+ * sendAsyncGeneratedCallToDestination sends an async call to another shard, together with the gas set aside for it.
+ *  Unlike the legacy async call, the caller keeps the gas which was not given to any of its async calls
+ */
+func (host *vmHost) sendAsyncGeneratedCallToDestination(asyncCall *vmhost.AsyncGeneratedCall) error {
+	runtime := host.Runtime()
+	output := host.Output()
+	metering := host.Metering()
+
+	err := output.Transfer(
+		asyncCall.GetDestination(),
+		runtime.GetSCAddress(),
+		asyncCall.GetGasLimit(),
+		asyncCall.GetGasLocked(),
+		big.NewInt(0).SetBytes(asyncCall.GetValueBytes()),
+		asyncCall.GetData(),
+		vm.AsynchronousCall,
+	)
+	if err != nil {
+		metering.UseGas(metering.GasLeft())
+		runtime.FailExecution(err)
+		return err
+	}
+
+	metering.UseGas(asyncCall.GetGasLimit())
+	return nil
+}
+
 /**
  * callbackAsync will execute a callback from an async call that was ran on this host and set it's status to resolved or rejected
  */
@@ -557,6 +689,12 @@ func (host *vmHost) callbackAsync(asyncCall *vmhost.AsyncGeneratedCall, vmOutput
 		return err
 	}
 
+	// Restore gas locked while still on the caller instance; otherwise, the
+	// locked gas will appear to have been used twice by the caller instance.
+	if host.IsAsyncContextMeteringEnabled() {
+		host.Metering().RestoreGas(asyncCall.GetGasLocked())
+	}
+
 	// Callback omits for now any async call - TODO: take into consideration async calls generated from callbacks
 	callbackVMOutput, _, callBackErr := host.ExecuteOnDestContext(callbackCallInput)
 	err = host.processCallbackVMOutput(callbackVMOutput, callBackErr)
@@ -568,28 +706,62 @@ func (host *vmHost) callbackAsync(asyncCall *vmhost.AsyncGeneratedCall, vmOutput
 }
 
 /**
- * savePendingAsyncCalls takes a list of pending async calls and save them to storage so the info will be available on callback
+ * savePendingAsyncCalls takes a list of pending async calls and save them to storage so the info will be available on callback.
+ *  The calls are added to the ones already waiting for callbacks in the same transaction, e.g. when a callback starts new async calls
  */
 func (host *vmHost) savePendingAsyncCalls(pendingAsyncMap *vmhost.AsyncContextInfo) error {
 	if len(pendingAsyncMap.AsyncContextMap) == 0 {
 		return nil
 	}
 
+	if !host.isAsyncContextCallbacksEnabled() {
+		return host.storeAsyncContextInfo(pendingAsyncMap)
+	}
+
+	storedAsyncInfo, err := host.getCurrentAsyncInfo()
+	if err != nil {
+		return err
+	}
+	if len(storedAsyncInfo.AsyncContextMap) == 0 {
+		return host.storeAsyncContextInfo(pendingAsyncMap)
+	}
+
+	for contextIdentifier, asyncContext := range pendingAsyncMap.AsyncContextMap {
+		storedContext, ok := storedAsyncInfo.AsyncContextMap[contextIdentifier]
+		if !ok {
+			storedAsyncInfo.AsyncContextMap[contextIdentifier] = asyncContext
+			continue
+		}
+
+		storedContext.AsyncCalls = append(storedContext.AsyncCalls, asyncContext.AsyncCalls...)
+		if len(asyncContext.Callback) > 0 {
+			storedContext.Callback = asyncContext.Callback
+		}
+	}
+
+	return host.storeAsyncContextInfo(storedAsyncInfo)
+}
+
+/**
+ * storeAsyncContextInfo overwrites the async calls waiting for callbacks in the current transaction, clearing them if there are none left
+ */
+func (host *vmHost) storeAsyncContextInfo(asyncInfo *vmhost.AsyncContextInfo) error {
 	storage := host.Storage()
 	runtime := host.Runtime()
 
 	asyncCallStorageKey := vmhost.CustomStorageKey(vmhost.AsyncDataPrefix, runtime.GetOriginalTxHash())
-	data, err := json.Marshal(pendingAsyncMap)
-	if err != nil {
+	if len(asyncInfo.AsyncContextMap) == 0 {
+		_, err := storage.SetProtectedStorage(asyncCallStorageKey, nil)
 		return err
 	}
 
-	_, err = storage.SetProtectedStorage(asyncCallStorageKey, data)
+	data, err := json.Marshal(asyncInfo)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = storage.SetProtectedStorage(asyncCallStorageKey, data)
+	return err
 }
 
 /**
@@ -659,10 +831,98 @@ func (host *vmHost) getPendingAsyncCalls(asyncInfo *vmhost.AsyncContextInfo) *vm
 /**
  * processCallbackStack is triggered when a callback was received from another host through a transaction.
  *  It will return an error if we receive a callback and we don't have it's associated data in the storage.
- *  If the associated callback was found in the pending set, it will be removed - It should not be executed
- *   again since it was executed in the callSCMethod step
+ *  The async call is removed from the pending set, so that it is not executed again; if it was the last one
+ *   of its async context, the completion callback of the context is executed. Once no calls are pending,
+ *   the callback of the original caller is triggered
  */
 func (host *vmHost) processCallbackStack() error {
+	if !host.isAsyncContextCallbacksEnabled() {
+		return host.processCallbackStackLegacy()
+	}
+
+	runtime := host.Runtime()
+
+	asyncInfo, err := host.getCurrentAsyncInfo()
+	if err != nil {
+		return err
+	}
+	if len(asyncInfo.AsyncContextMap) == 0 {
+		return nil
+	}
+
+	vmInput := runtime.GetVMInput()
+	contextIdentifier, asyncCallPosition, found := findPendingAsyncCall(asyncInfo, vmInput.CallerAddr)
+	if !found {
+		return vmhost.ErrCallBackFuncNotExpected
+	}
+
+	// Remove current async call from the pending list
+	asyncContext := asyncInfo.AsyncContextMap[contextIdentifier]
+	asyncContext.AsyncCalls = append(asyncContext.AsyncCalls[:asyncCallPosition], asyncContext.AsyncCalls[asyncCallPosition+1:]...)
+	isContextCompleted := len(asyncContext.AsyncCalls) == 0
+	if isContextCompleted {
+		delete(asyncInfo.AsyncContextMap, contextIdentifier)
+	}
+
+	// Persist the progress before any other contract code runs, which could start new async calls
+	err = host.storeAsyncContextInfo(asyncInfo)
+	if err != nil {
+		return err
+	}
+
+	if isContextCompleted {
+		err = host.executeAsyncContextCallback(asyncContext)
+		if err != nil {
+			return err
+		}
+	}
+
+	// If we are still waiting for callbacks we return
+	remainingAsyncInfo, err := host.getCurrentAsyncInfo()
+	if err != nil {
+		return err
+	}
+	if len(remainingAsyncInfo.AsyncContextMap) > 0 {
+		return nil
+	}
+
+	// Now figure out if we can execute the callback here or different shard
+	if !host.canExecuteSynchronously(asyncInfo.CallerAddr, asyncInfo.ReturnData) {
+		err = host.sendStorageCallbackToDestination(asyncInfo.CallerAddr, asyncInfo.ReturnData)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// The caller is in the same shard, execute it's callback; no gas was locked for it in this transaction
+	callbackCallInput, err := host.createCallbackContractCallInput(
+		&vmhost.AsyncCallInfo{Destination: asyncInfo.CallerAddr},
+		host.Output().GetVMOutput(),
+		asyncInfo.CallerAddr,
+		vmhost.CallbackFunctionName,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	callbackVMOutput, _, callBackErr := host.ExecuteOnDestContext(callbackCallInput)
+	err = host.processCallbackVMOutput(callbackVMOutput, callBackErr)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/**
+ * processCallbackStackLegacy is triggered when a callback was received from another host through a transaction,
+ *  before the completion callbacks of the async contexts. The associated async call is removed from the pending set,
+ *  and once no calls are pending, the callback of the original caller is triggered
+ */
+func (host *vmHost) processCallbackStackLegacy() error {
 	runtime := host.Runtime()
 	storage := host.Storage()
 
@@ -820,19 +1080,11 @@ func (host *vmHost) getFunctionByCallType(callType vm.CallType) (wasmer.Exported
 
 	vmInput := runtime.GetVMInput()
 
-	customCallback := false
-	for _, asyncContext := range asyncInfo.AsyncContextMap {
-		for _, asyncCall := range asyncContext.AsyncCalls {
-			if bytes.Equal(vmInput.CallerAddr, asyncCall.Destination) {
-				customCallback = true
-				runtime.SetCustomCallFunction(asyncCall.SuccessCallback)
-				break
-			}
-		}
-
-		if customCallback {
-			break
-		}
+	var customCallback bool
+	if host.isAsyncContextCallbacksEnabled() {
+		customCallback = setCustomCallback(runtime, asyncInfo, vmInput)
+	} else {
+		customCallback = setLegacyCustomCallback(runtime, asyncInfo, vmInput)
 	}
 
 	function, err := runtime.GetFunctionToCall()
@@ -862,3 +1114,96 @@ func (host *vmHost) getCurrentAsyncInfo() (*vmhost.AsyncContextInfo, error) {
 
 	return asyncInfo, nil
 }
+
+/**
+ * findPendingAsyncCall returns the first pending async call to the given destination. The async contexts
+ *  are searched in the order of their identifiers, so that all nodes resolve the same async call
+ */
+func findPendingAsyncCall(asyncInfo *vmhost.AsyncContextInfo, destination []byte) (string, int, bool) {
+	for _, contextIdentifier := range sortedAsyncContextIdentifiers(asyncInfo) {
+		for position, asyncCall := range asyncInfo.AsyncContextMap[contextIdentifier].AsyncCalls {
+			if asyncCall.Status != vmhost.AsyncCallPending {
+				continue
+			}
+			if bytes.Equal(destination, asyncCall.Destination) {
+				return contextIdentifier, position, true
+			}
+		}
+	}
+
+	return "", 0, false
+}
+
+// setCustomCallback sets the success or the error callback of the first pending async call to the caller
+// as the function to call, depending on the result of the async call
+func setCustomCallback(runtime vmhost.RuntimeContext, asyncInfo *vmhost.AsyncContextInfo, vmInput *vmcommon.VMInput) bool {
+	contextIdentifier, asyncCallPosition, found := findPendingAsyncCall(asyncInfo, vmInput.CallerAddr)
+	if !found {
+		return false
+	}
+
+	asyncCall := asyncInfo.AsyncContextMap[contextIdentifier].AsyncCalls[asyncCallPosition]
+	callbackFunction := asyncCall.SuccessCallback
+	if isCallbackForFailedCall(vmInput) {
+		callbackFunction = asyncCall.ErrorCallback
+	}
+	if len(callbackFunction) == 0 {
+		return false
+	}
+
+	runtime.SetCustomCallFunction(callbackFunction)
+	return true
+}
+
+// setLegacyCustomCallback sets the success callback of the first async call to the caller as the function
+// to call, searching the async contexts in no particular order
+func setLegacyCustomCallback(runtime vmhost.RuntimeContext, asyncInfo *vmhost.AsyncContextInfo, vmInput *vmcommon.VMInput) bool {
+	for _, asyncContext := range asyncInfo.AsyncContextMap {
+		for _, asyncCall := range asyncContext.AsyncCalls {
+			if bytes.Equal(vmInput.CallerAddr, asyncCall.Destination) {
+				runtime.SetCustomCallFunction(asyncCall.SuccessCallback)
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// asyncContextIdentifiers returns the identifiers of the async contexts, sorted if the completion callbacks
+// of the async contexts are enabled, so that all nodes process the async calls in the same order
+func (host *vmHost) asyncContextIdentifiers(asyncInfo *vmhost.AsyncContextInfo) []string {
+	if host.isAsyncContextCallbacksEnabled() {
+		return sortedAsyncContextIdentifiers(asyncInfo)
+	}
+
+	contextIdentifiers := make([]string, 0, len(asyncInfo.AsyncContextMap))
+	for contextIdentifier := range asyncInfo.AsyncContextMap {
+		contextIdentifiers = append(contextIdentifiers, contextIdentifier)
+	}
+	return contextIdentifiers
+}
+
+func sortedAsyncContextIdentifiers(asyncInfo *vmhost.AsyncContextInfo) []string {
+	contextIdentifiers := make([]string, 0, len(asyncInfo.AsyncContextMap))
+	for contextIdentifier := range asyncInfo.AsyncContextMap {
+		contextIdentifiers = append(contextIdentifiers, contextIdentifier)
+	}
+	sort.Strings(contextIdentifiers)
+
+	return contextIdentifiers
+}
+
+// isCallbackForFailedCall returns true if the callback reports an error; the return code is the first
+// argument, either as a number or, for callbacks from other shards, as its name
+func isCallbackForFailedCall(vmInput *vmcommon.VMInput) bool {
+	if vmInput.ReturnCallAfterError {
+		return true
+	}
+	if len(vmInput.Arguments) == 0 {
+		return false
+	}
+
+	returnCode := vmInput.Arguments[0]
+	return len(returnCode) > 0 && string(returnCode) != vmcommon.Ok.String()
+}
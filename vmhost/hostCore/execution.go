@@ -16,7 +16,7 @@ import (
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 )
 
-func (host *vmHost) doRunSmartContractCreate(input *vmcommon.ContractCreateInput) *vmcommon.VMOutput {
+func (host *vmHost) doRunSmartContractCreate(input *vmcommon.ContractCreateInput) (vmOutput *vmcommon.VMOutput) {
 	host.InitState()
 	defer func() {
 		errs := host.GetRuntimeErrors()
@@ -33,6 +33,17 @@ func (host *vmHost) doRunSmartContractCreate(input *vmcommon.ContractCreateInput
 		return output.CreateVMOutputInCaseOfError(err)
 	}
 
+	// the deployment is traced as a call of the init function of the new contract
+	host.traceCallStart(&vmcommon.ContractCallInput{
+		VMInput:           input.VMInput,
+		RecipientAddr:     address,
+		Function:          vmhost.InitFunctionName,
+		AllowInitFunction: true,
+	})
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
 	runtime.SetVMInput(&input.VMInput)
 	runtime.SetSCAddress(address)
 	metering.InitStateFromContractCallInput(&input.VMInput)
@@ -47,7 +58,7 @@ func (host *vmHost) doRunSmartContractCreate(input *vmcommon.ContractCreateInput
 		CodeDeployerAddress:  input.CallerAddr,
 	}
 
-	vmOutput, err := host.performCodeDeployment(codeDeployInput)
+	vmOutput, err = host.performCodeDeployment(codeDeployInput)
 	if err != nil {
 		log.Trace("doRunSmartContractCreate", "error", err)
 		return output.CreateVMOutputInCaseOfError(err)
@@ -183,6 +194,12 @@ func (host *vmHost) doRunSmartContractCall(input *vmcommon.ContractCallInput) (v
 		return output.CreateVMOutputInCaseOfError(vmhost.ErrContractInvalid)
 	}
 
+	err = storage.PrewarmStorageKeys(host.accessList)
+	if err != nil {
+		log.Trace("doRunSmartContractCall access list", "error", err)
+		return output.CreateVMOutputInCaseOfError(err)
+	}
+
 	err = host.callSCMethod()
 	if err != nil {
 		log.Trace("doRunSmartContractCall", "error", err)
@@ -200,6 +217,50 @@ func (host *vmHost) doRunSmartContractCall(input *vmcommon.ContractCallInput) (v
 	return
 }
 
+func (host *vmHost) doRunSmartContractQuery(input *vmcommon.ContractCallInput) *vmcommon.VMOutput {
+	runtime := host.Runtime()
+	runtime.SetQueryMode(true)
+	defer runtime.SetQueryMode(false)
+
+	vmOutput := host.doRunSmartContractCall(input)
+	if vmOutput.ReturnCode == vmcommon.Ok {
+		return vmOutput
+	}
+
+	violation := host.findQueryViolation()
+	if violation != nil {
+		vmOutput.ReturnCode = vmcommon.ExecutionFailed
+		vmOutput.ReturnMessage = violation.Error()
+	}
+
+	return vmOutput
+}
+
+// findQueryViolation returns the first error recorded during the execution which rejected a state
+// change, naming the offending VM hook, or nil if the query failed for another reason
+func (host *vmHost) findQueryViolation() error {
+	runtimeErrors, ok := host.GetRuntimeErrors().(vmhost.WrappableError)
+	if !ok {
+		return nil
+	}
+
+	for _, err := range runtimeErrors.GetAllErrors() {
+		if errors.Is(err, vmhost.ErrStateChangeInQuery) || errors.Is(err, vmhost.ErrStateChangeInReadOnly) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (host *vmHost) createQueryViolationOutput(err error) *vmcommon.VMOutput {
+	return &vmcommon.VMOutput{
+		ReturnCode:    vmcommon.ExecutionFailed,
+		ReturnMessage: err.Error(),
+		GasRefund:     big.NewInt(0),
+	}
+}
+
 func copyTxHashesFromContext(copyEnabled bool, runtime vmhost.RuntimeContext, input *vmcommon.ContractCallInput) {
 	if !copyEnabled {
 		return
@@ -222,6 +283,11 @@ func copyTxHashesFromContext(copyEnabled bool, runtime vmhost.RuntimeContext, in
 func (host *vmHost) ExecuteOnDestContext(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, asyncInfo *vmhost.AsyncContextInfo, err error) {
 	log.Trace("ExecuteOnDestContext", "caller", input.CallerAddr, "dest", input.RecipientAddr, "function", input.Function)
 
+	host.traceCallStart(input)
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
 	scExecutionInput := input
 
 	blockchain := host.Blockchain()
@@ -251,6 +317,10 @@ func (host *vmHost) ExecuteOnDestContext(input *vmcommon.ContractCallInput) (vmO
 }
 
 func (host *vmHost) handleBuiltinFunctionCall(input *vmcommon.ContractCallInput) (*vmcommon.ContractCallInput, *vmcommon.VMOutput, error) {
+	if host.Runtime().QueryMode() {
+		return nil, nil, fmt.Errorf("%w: %s", vmhost.ErrStateChangeInQuery, input.Function)
+	}
+
 	output := host.Output()
 	postBuiltinInput, builtinOutput, err := host.callBuiltinFunction(input)
 	if err != nil {
@@ -328,14 +398,15 @@ func (host *vmHost) finishExecuteOnDestContext(executeErr error) *vmcommon.VMOut
 
 	// Restore the previous context states
 	bigInt.PopSetActiveState()
-	storage.PopSetActiveState()
 
 	if vmOutput.ReturnCode == vmcommon.Ok {
 		metering.PopMergeActiveState()
 		output.PopMergeActiveState()
+		storage.PopMergeActiveState()
 	} else {
 		metering.PopSetActiveState()
 		output.PopSetActiveState()
+		storage.PopSetActiveState()
 	}
 
 	// Return to the caller context completely
@@ -358,12 +429,16 @@ func (host *vmHost) ExecuteOnSameContext(input *vmcommon.ContractCallInput) (asy
 		return nil, vmhost.ErrBuiltinCallOnSameContextDisallowed
 	}
 
-	bigInt, blockchain, metering, output, runtime, _ := host.GetContexts()
+	host.traceCallStart(input)
+
+	bigInt, blockchain, metering, output, runtime, storage := host.GetContexts()
 
-	// Back up the states of the contexts (except Storage, which isn't affected
-	// by ExecuteOnSameContext())
+	// Back up the states of the contexts; the storage address isn't affected by
+	// ExecuteOnSameContext(), but the storage keys it accesses must be forgotten
+	// if it fails
 	bigInt.PushState()
 	output.PushState()
+	storage.PushState()
 
 	copyTxHashesFromContext(host.IsDCDTFunctionsEnabled(), runtime, input)
 	runtime.PushState()
@@ -376,6 +451,7 @@ func (host *vmHost) ExecuteOnSameContext(input *vmcommon.ContractCallInput) (asy
 
 	defer func() {
 		runtime.AddError(err, input.Function)
+		host.traceSameContextCallEnd(err)
 		host.finishExecuteOnSameContext(err)
 	}()
 
@@ -395,8 +471,24 @@ func (host *vmHost) ExecuteOnSameContext(input *vmcommon.ContractCallInput) (asy
 	return
 }
 
+func (host *vmHost) traceSameContextCallEnd(executeErr error) {
+	if check.IfNilReflect(host.callTracer) {
+		return
+	}
+
+	returnCode := host.Output().ReturnCode()
+	if executeErr != nil && returnCode == vmcommon.Ok {
+		returnCode = vmcommon.ExecutionFailed
+	}
+
+	host.traceCallEnd(&vmcommon.VMOutput{
+		ReturnCode:   returnCode,
+		GasRemaining: host.Metering().GasLeft(),
+	})
+}
+
 func (host *vmHost) finishExecuteOnSameContext(executeErr error) {
-	bigInt, blockchain, metering, output, runtime, _ := host.GetContexts()
+	bigInt, blockchain, metering, output, runtime, storage := host.GetContexts()
 
 	if output.ReturnCode() != vmcommon.Ok || executeErr != nil {
 		// Execution failed: restore contexts as if the execution didn't happen.
@@ -405,6 +497,7 @@ func (host *vmHost) finishExecuteOnSameContext(executeErr error) {
 		output.PopSetActiveState()
 		runtime.PopSetActiveState()
 		blockchain.PopSetActiveState()
+		storage.PopSetActiveState()
 		return
 	}
 
@@ -417,6 +510,7 @@ func (host *vmHost) finishExecuteOnSameContext(executeErr error) {
 	output.PopDiscard()
 	bigInt.PopDiscard()
 	blockchain.PopDiscard()
+	storage.PopMergeActiveState()
 	runtime.PopSetActiveState()
 
 	// Restore remaining gas to the caller (parent) Wasmer instance
@@ -471,6 +565,10 @@ func (host *vmHost) CreateNewContract(input *vmcommon.ContractCreateInput) (newC
 		err = vmhost.ErrInvalidCallOnReadOnlyMode
 		return
 	}
+	if runtime.QueryMode() {
+		err = vmhost.ErrStateChangeInQuery
+		return
+	}
 
 	newContractAddress, err = blockchain.NewAddress(input.CallerAddr)
 	if err != nil {
@@ -686,9 +784,46 @@ func (host *vmHost) ExecuteDCDTTransfer(destination []byte, sender []byte, token
 		dcdtTransferInput.Arguments = append(dcdtTransferInput.Arguments, tokenIdentifier, value.Bytes())
 	}
 
-	vmOutput, err := host.Blockchain().ProcessBuiltInFunction(dcdtTransferInput)
 	log.Trace("DCDT transfer", "sender", sender, "dest", destination)
 	log.Trace("DCDT transfer", "token", tokenIdentifier, "value", value)
+	return host.processDCDTTransferInput(dcdtTransferInput, callType)
+}
+
+// ExecuteMultiDCDTTransfer calls the process built in function MultiDCDTNFTTransfer,
+// moving all the given tokens to the destination at once
+func (host *vmHost) ExecuteMultiDCDTTransfer(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error) {
+	_, _, metering, _, runtime, _ := host.GetContexts()
+
+	multiTransferInput := &vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:  sender,
+			Arguments:   make([][]byte, 0, 2+len(transfers)*parsers.ArgsPerTransfer),
+			CallValue:   big.NewInt(0),
+			CallType:    callType,
+			GasPrice:    runtime.GetVMInput().GasPrice,
+			GasProvided: metering.GasLeft(),
+			GasLocked:   0,
+		},
+		RecipientAddr:     sender,
+		Function:          core.BuiltInFunctionMultiDCDTNFTTransfer,
+		AllowInitFunction: false,
+	}
+
+	numTransfers := big.NewInt(int64(len(transfers))).Bytes()
+	multiTransferInput.Arguments = append(multiTransferInput.Arguments, destination, numTransfers)
+	for _, transfer := range transfers {
+		nonceAsBytes := big.NewInt(0).SetUint64(transfer.DCDTTokenNonce).Bytes()
+		multiTransferInput.Arguments = append(multiTransferInput.Arguments, transfer.DCDTTokenName, nonceAsBytes, transfer.DCDTValue.Bytes())
+	}
+
+	log.Trace("multi DCDT transfer", "sender", sender, "dest", destination, "transfers", len(transfers))
+	return host.processDCDTTransferInput(multiTransferInput, callType)
+}
+
+func (host *vmHost) processDCDTTransferInput(dcdtTransferInput *vmcommon.ContractCallInput, callType vm.CallType) (*vmcommon.VMOutput, uint64, error) {
+	metering := host.Metering()
+
+	vmOutput, err := host.Blockchain().ProcessBuiltInFunction(dcdtTransferInput)
 	if err != nil {
 		log.Trace("DCDT transfer", "error", err)
 		return vmOutput, dcdtTransferInput.GasProvided, err
@@ -754,7 +889,9 @@ func (host *vmHost) addDCDTTransferToVMOutputSCIntraShardCall(
 	if !host.AreInSameShard(input.RecipientAddr, input.CallerAddr) {
 		return
 	}
-	isDCDTTransfer := input.Function == core.BuiltInFunctionDCDTTransfer || input.Function == core.BuiltInFunctionDCDTNFTTransfer
+	isDCDTTransfer := input.Function == core.BuiltInFunctionDCDTTransfer ||
+		input.Function == core.BuiltInFunctionDCDTNFTTransfer ||
+		input.Function == core.BuiltInFunctionMultiDCDTNFTTransfer
 	if !isDCDTTransfer {
 		return
 	}
@@ -766,6 +903,12 @@ func (host *vmHost) addDCDTTransferToVMOutputSCIntraShardCall(
 		}
 		recipientAddr = input.Arguments[3]
 	}
+	if input.Function == core.BuiltInFunctionMultiDCDTNFTTransfer {
+		if len(input.Arguments) < parsers.MinArgsForMultiDCDTNFTTransfer {
+			return
+		}
+		recipientAddr = input.Arguments[0]
+	}
 	addOutputTransferToVMOutput(input.Function, input.Arguments, input.CallerAddr, recipientAddr, input.CallType, output)
 }
 
@@ -928,6 +1071,9 @@ func (host *vmHost) isSCExecutionAfterBuiltInFunc(
 	if vmInput.Function == core.BuiltInFunctionDCDTNFTTransfer && bytes.Equal(vmInput.CallerAddr, vmInput.RecipientAddr) {
 		recipient = vmInput.Arguments[3]
 	}
+	if vmInput.Function == core.BuiltInFunctionMultiDCDTNFTTransfer && bytes.Equal(vmInput.CallerAddr, vmInput.RecipientAddr) {
+		recipient = vmInput.Arguments[0]
+	}
 	if !host.AreInSameShard(vmInput.CallerAddr, recipient) {
 		return nil, nil
 	}
@@ -975,6 +1121,11 @@ func (host *vmHost) isSCExecutionAfterBuiltInFunc(
 }
 
 func fillWithDCDTValue(fullVMInput *vmcommon.ContractCallInput, newVMInput *vmcommon.ContractCallInput) {
+	if fullVMInput.Function == core.BuiltInFunctionMultiDCDTNFTTransfer {
+		newVMInput.DCDTTransfers = extractMultiDCDTTransfers(fullVMInput)
+		return
+	}
+
 	isDCDTTransfer := fullVMInput.Function == core.BuiltInFunctionDCDTTransfer || fullVMInput.Function == core.BuiltInFunctionDCDTNFTTransfer
 	if !isDCDTTransfer {
 		return
@@ -994,3 +1145,41 @@ func fillWithDCDTValue(fullVMInput *vmcommon.ContractCallInput, newVMInput *vmco
 	newVMInput.DCDTTransfers = make([]*vmcommon.DCDTTransfer, 1)
 	newVMInput.DCDTTransfers[0] = dcdtTransfer
 }
+
+// extractMultiDCDTTransfers returns the tokens moved by a MultiDCDTNFTTransfer call, given either
+// at the sender, with the destination as first argument, or at the destination
+func extractMultiDCDTTransfers(multiTransferInput *vmcommon.ContractCallInput) []*vmcommon.DCDTTransfer {
+	arguments := multiTransferInput.Arguments
+	startIndex := 1
+	if bytes.Equal(multiTransferInput.CallerAddr, multiTransferInput.RecipientAddr) {
+		startIndex = 2
+	}
+	if len(arguments) < startIndex {
+		return nil
+	}
+
+	numTransfers := big.NewInt(0).SetBytes(arguments[startIndex-1])
+	if !numTransfers.IsUint64() || numTransfers.Uint64() > uint64(len(arguments)/parsers.ArgsPerTransfer) {
+		return nil
+	}
+	if len(arguments) < startIndex+int(numTransfers.Uint64())*parsers.ArgsPerTransfer {
+		return nil
+	}
+
+	dcdtTransfers := make([]*vmcommon.DCDTTransfer, numTransfers.Uint64())
+	for i := range dcdtTransfers {
+		tokenStartIndex := startIndex + i*parsers.ArgsPerTransfer
+		dcdtTransfer := &vmcommon.DCDTTransfer{
+			DCDTTokenName:  arguments[tokenStartIndex],
+			DCDTTokenNonce: big.NewInt(0).SetBytes(arguments[tokenStartIndex+1]).Uint64(),
+			DCDTValue:      big.NewInt(0).SetBytes(arguments[tokenStartIndex+2]),
+			DCDTTokenType:  uint32(core.Fungible),
+		}
+		if dcdtTransfer.DCDTTokenNonce > 0 {
+			dcdtTransfer.DCDTTokenType = uint32(core.NonFungible)
+		}
+		dcdtTransfers[i] = dcdtTransfer
+	}
+
+	return dcdtTransfers
+}
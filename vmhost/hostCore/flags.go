@@ -11,6 +11,22 @@ const (
 	RepairCallbackFlag core.EnableEpochFlag = "RepairCallbackFlag"
 	// AheadOfTimeGasUsageFlag defines the flag that activates the ahead of time gas usage fix
 	AheadOfTimeGasUsageFlag core.EnableEpochFlag = "AheadOfTimeGasUsageFlag"
+	// StrictReadOnlyFlag defines the flag that makes state changes in read-only mode fail the execution
+	StrictReadOnlyFlag core.EnableEpochFlag = "StrictReadOnlyFlag"
+	// AsyncContextMeteringFlag defines the flag that activates the gas metering and the limits of the async contexts
+	AsyncContextMeteringFlag core.EnableEpochFlag = "AsyncContextMeteringFlag"
+	// AsyncContextCallbacksFlag defines the flag that activates the completion callbacks and the persistence of the async contexts
+	AsyncContextCallbacksFlag core.EnableEpochFlag = "AsyncContextCallbacksFlag"
+	// MultiDCDTTransferFlag defines the flag that activates the multi-token transfers and the getters of the received tokens by index
+	MultiDCDTTransferFlag core.EnableEpochFlag = "MultiDCDTTransferFlag"
+	// DCDTManagementFlag defines the flag that activates the minting, burning and NFT management of tokens through the local roles of contracts
+	DCDTManagementFlag core.EnableEpochFlag = "DCDTManagementFlag"
+	// StorageIterateFlag defines the flag that activates the iteration over the storage of the current contract in queries and read-only calls
+	StorageIterateFlag core.EnableEpochFlag = "StorageIterateFlag"
+	// StorageSizeFlag defines the flag that activates the tracking of the storage size of the accounts and its getter
+	StorageSizeFlag core.EnableEpochFlag = "StorageSizeFlag"
+	// StorageAccessCostFlag defines the flag that activates the warm and cold pricing of the storage accesses and the storage access lists
+	StorageAccessCostFlag core.EnableEpochFlag = "StorageAccessCostFlag"
 )
 
 // allFlags must have all flags used by k-chain-vm-v1_3-go in the current version
@@ -19,4 +35,31 @@ var allFlags = []core.EnableEpochFlag{
 	BuiltInFunctionsFlag,
 	RepairCallbackFlag,
 	AheadOfTimeGasUsageFlag,
+	StrictReadOnlyFlag,
+	AsyncContextMeteringFlag,
+	AsyncContextCallbacksFlag,
+	MultiDCDTTransferFlag,
+	DCDTManagementFlag,
+	StorageIterateFlag,
+	StorageSizeFlag,
+	StorageAccessCostFlag,
+}
+
+// AllFlags returns a copy of all the flags used by k-chain-vm-v1_3-go in the current version
+func AllFlags() []core.EnableEpochFlag {
+	flags := make([]core.EnableEpochFlag, len(allFlags))
+	copy(flags, allFlags)
+	return flags
+}
+
+// IsKnownFlag returns true if the given flag is used by k-chain-vm-v1_3-go in the current version;
+// enable epochs handlers which activate all the flags of the VM use it as their predicate
+func IsKnownFlag(flag core.EnableEpochFlag) bool {
+	for _, knownFlag := range allFlags {
+		if flag == knownFlag {
+			return true
+		}
+	}
+
+	return false
 }
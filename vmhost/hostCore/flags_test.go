@@ -0,0 +1,18 @@
+package hostCore
+
+import (
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsKnownFlag(t *testing.T) {
+	t.Parallel()
+
+	for _, flag := range AllFlags() {
+		require.True(t, IsKnownFlag(flag), string(flag))
+	}
+	require.False(t, IsKnownFlag(core.EnableEpochFlag("UnknownFlag")))
+	require.False(t, IsKnownFlag(""))
+}
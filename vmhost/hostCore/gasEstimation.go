@@ -0,0 +1,140 @@
+package hostCore
+
+import (
+	"fmt"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/math"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// EstimateGas finds the minimum gas limit with which the call of an existing contract
+// succeeds, by executing it repeatedly against the current state. The GasProvided of the
+// input is the maximum gas limit considered; when zero, the block gas limit is used. None of
+// the resulting VMOutputs are returned, so the state is left unchanged.
+//
+// The gas used by a call with the maximum gas limit is not necessarily enough: async calls
+// require the gas for their callbacks to be available when they are registered, even if it
+// is refunded afterwards, so the minimum is searched for between the gas used, which already
+// accounts for built-in functions, and the maximum gas limit.
+func (host *vmHost) EstimateGas(input *vmcommon.ContractCallInput) (estimate *vmhost.GasEstimate, err error) {
+	host.mutExecution.RLock()
+	defer host.mutExecution.RUnlock()
+
+	log.Trace("EstimateGas begin", "function", input.Function)
+
+	tryEstimate := func() {
+		estimate, err = host.doEstimateGas(input)
+	}
+
+	catch := func(caught error) {
+		err = caught
+		log.Error("EstimateGas", "error", err)
+	}
+
+	TryCatch(tryEstimate, catch, "vmhost.EstimateGas")
+
+	return
+}
+
+func (host *vmHost) doEstimateGas(input *vmcommon.ContractCallInput) (*vmhost.GasEstimate, error) {
+	maxGasLimit := input.GasProvided
+	if maxGasLimit == 0 {
+		maxGasLimit = host.Metering().BlockGasLimit()
+	}
+
+	estimate := &vmhost.GasEstimate{}
+
+	vmOutput := host.runWithGasLimit(input, maxGasLimit, estimate)
+	if vmOutput.ReturnCode != vmcommon.Ok {
+		return nil, fmt.Errorf("%w: %s, %s", vmhost.ErrGasEstimationFailed, vmOutput.ReturnCode, vmOutput.ReturnMessage)
+	}
+
+	estimate.GasUsed = math.SubUint64(maxGasLimit, vmOutput.GasRemaining)
+	estimate.GasLocked = computeGasLockedInOutput(vmOutput)
+
+	// The call is known to fail with lowGasLimit and to succeed with highGasLimit
+	lowGasLimit := uint64(0)
+	highGasLimit := maxGasLimit
+	if estimate.GasUsed < highGasLimit {
+		if host.succeedsWithGasLimit(input, estimate.GasUsed, estimate) {
+			highGasLimit = estimate.GasUsed
+		} else {
+			lowGasLimit = estimate.GasUsed
+		}
+	}
+
+	// Calls which register async calls usually succeed once the gas locked for a callback
+	// into the contract is available on top of the gas used
+	gasLockedForAsync := host.computeGasLockedForAsync(input.RecipientAddr)
+	gasLimitWithLock := math.AddUint64(lowGasLimit, gasLockedForAsync)
+	if lowGasLimit > 0 && gasLimitWithLock < highGasLimit {
+		if host.succeedsWithGasLimit(input, gasLimitWithLock, estimate) {
+			highGasLimit = gasLimitWithLock
+		} else {
+			lowGasLimit = gasLimitWithLock
+		}
+	}
+
+	for highGasLimit-lowGasLimit > 1 {
+		gasLimit := lowGasLimit + (highGasLimit-lowGasLimit)/2
+		if host.succeedsWithGasLimit(input, gasLimit, estimate) {
+			highGasLimit = gasLimit
+		} else {
+			lowGasLimit = gasLimit
+		}
+	}
+
+	estimate.MinimumGasLimit = highGasLimit
+	safetyMargin := math.MulUint64(highGasLimit, vmhost.GasEstimationSafetyMarginPercent) / 100
+	estimate.RecommendedGasLimit = math.AddUint64(highGasLimit, safetyMargin)
+	if estimate.RecommendedGasLimit > maxGasLimit {
+		estimate.RecommendedGasLimit = maxGasLimit
+	}
+
+	log.Trace("EstimateGas end",
+		"function", input.Function,
+		"minimum", estimate.MinimumGasLimit,
+		"executions", estimate.Executions)
+
+	return estimate, nil
+}
+
+func (host *vmHost) succeedsWithGasLimit(input *vmcommon.ContractCallInput, gasLimit uint64, estimate *vmhost.GasEstimate) bool {
+	vmOutput := host.runWithGasLimit(input, gasLimit, estimate)
+	return vmOutput.ReturnCode == vmcommon.Ok
+}
+
+func (host *vmHost) runWithGasLimit(input *vmcommon.ContractCallInput, gasLimit uint64, estimate *vmhost.GasEstimate) *vmcommon.VMOutput {
+	estimateInput := *input
+	estimateInput.GasProvided = gasLimit
+	estimate.Executions++
+
+	return host.doRunSmartContractCall(&estimateInput)
+}
+
+// computeGasLockedForAsync returns the gas locked for an async callback into the given contract
+func (host *vmHost) computeGasLockedForAsync(address []byte) uint64 {
+	codeSize, err := host.Blockchain().GetCodeSize(address)
+	if err != nil || codeSize < 0 {
+		codeSize = 0
+	}
+
+	return host.Metering().ComputeGasLockedForAsyncWithCodeSize(uint64(codeSize))
+}
+
+// computeGasLockedInOutput sums up the gas locked for the callbacks of the cross-shard
+// async calls found in the given output
+func computeGasLockedInOutput(vmOutput *vmcommon.VMOutput) uint64 {
+	gasLocked := uint64(0)
+	for _, outputAccount := range vmOutput.OutputAccounts {
+		if outputAccount == nil {
+			continue
+		}
+		for _, outputTransfer := range outputAccount.OutputTransfers {
+			gasLocked = math.AddUint64(gasLocked, outputTransfer.GasLocked)
+		}
+	}
+
+	return gasLocked
+}
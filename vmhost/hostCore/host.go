@@ -12,6 +12,7 @@ import (
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/crypto"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/crypto/factory"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/codecache"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/contexts"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/cryptoapi"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/vmhooks"
@@ -47,6 +48,12 @@ type vmHost struct {
 	scAPIMethods         *wasmer.Imports
 	builtInFuncContainer vmcommon.BuiltInFunctionContainer
 	enableEpochsHandler  vmhost.EnableEpochsHandler
+	compiledCodeCache    vmhost.CompiledCodeCache
+	callTracer           vmhost.CallTracer
+	probeTracer          vmhost.ProbeTracer
+	useWarmInstance      bool
+	stateOverrideHook    *stateOverrideHook
+	accessList           vmhost.StorageAccessList
 }
 
 // NewVMHost creates a new VM vmHost
@@ -74,6 +81,8 @@ func NewVMHost(
 		scAPIMethods:         nil,
 		builtInFuncContainer: hostParameters.BuiltInFuncContainer,
 		enableEpochsHandler:  hostParameters.EnableEpochsHandler,
+		useWarmInstance:      hostParameters.UseWarmInstance,
+		stateOverrideHook:    newStateOverrideHook(blockChainHook),
 	}
 
 	imports, err := vmhooks.BaseOpsAPIImports()
@@ -96,14 +105,24 @@ func NewVMHost(
 		return nil, err
 	}
 
+	imports, err = vmhooks.ProbeImports(imports)
+	if err != nil {
+		return nil, err
+	}
+
 	err = wasmer.SetImports(imports)
 	if err != nil {
 		return nil, err
 	}
 
+	err = checkImportFlags(imports.Names())
+	if err != nil {
+		return nil, err
+	}
+
 	host.scAPIMethods = imports
 
-	host.blockchainContext, err = contexts.NewBlockchainContext(host, blockChainHook)
+	host.blockchainContext, err = contexts.NewBlockchainContext(host, host.stateOverrideHook)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +147,7 @@ func NewVMHost(
 		return nil, err
 	}
 
-	host.storageContext, err = contexts.NewStorageContext(host, blockChainHook, hostParameters.ProtectedKeyPrefix)
+	host.storageContext, err = contexts.NewStorageContext(host, host.stateOverrideHook, hostParameters.ProtectedKeyPrefix)
 	if err != nil {
 		return nil, err
 	}
@@ -145,6 +164,17 @@ func NewVMHost(
 
 	host.runtimeContext.SetMaxInstanceCount(MaximumWasmerInstanceCount)
 
+	if hostParameters.CompiledCodeCacheSize > 0 {
+		host.compiledCodeCache, err = codecache.NewCompiledCodeCache(codecache.ArgsCompiledCodeCache{
+			MaxSizeInBytes: hostParameters.CompiledCodeCacheSize,
+			Directory:      hostParameters.CompiledCodeCacheDirectory,
+		})
+		if err != nil {
+			return nil, err
+		}
+		host.runtimeContext.SetCompiledCodeCache(host.compiledCodeCache, config.GasScheduleVersion(host.gasSchedule))
+	}
+
 	opcodeCosts := gasCostConfig.WASMOpcodeCost.ToOpcodeCostsArray()
 	wasmer.SetOpcodeCosts(&opcodeCosts)
 
@@ -224,6 +254,31 @@ func (host *vmHost) IsDCDTFunctionsEnabled() bool {
 	return host.enableEpochsHandler.IsFlagEnabled(BuiltInFunctionsFlag)
 }
 
+// IsStrictReadOnlyEnabled returns whether state changes in read-only mode fail the execution
+func (host *vmHost) IsStrictReadOnlyEnabled() bool {
+	return host.enableEpochsHandler.IsFlagEnabled(StrictReadOnlyFlag)
+}
+
+// IsAsyncContextMeteringEnabled returns whether the async contexts are metered and limited
+func (host *vmHost) IsAsyncContextMeteringEnabled() bool {
+	return host.enableEpochsHandler.IsFlagEnabled(AsyncContextMeteringFlag)
+}
+
+// IsStorageSizeEnabled returns whether the storage size of the accounts is tracked during the execution
+func (host *vmHost) IsStorageSizeEnabled() bool {
+	return host.enableEpochsHandler.IsFlagEnabled(StorageSizeFlag)
+}
+
+// IsStorageAccessCostEnabled returns whether the storage accesses are priced as warm or cold
+func (host *vmHost) IsStorageAccessCostEnabled() bool {
+	return host.enableEpochsHandler.IsFlagEnabled(StorageAccessCostFlag)
+}
+
+// isAsyncContextCallbacksEnabled returns whether the async contexts are completed by callbacks and persisted across shards
+func (host *vmHost) isAsyncContextCallbacksEnabled() bool {
+	return host.enableEpochsHandler.IsFlagEnabled(AsyncContextCallbacksFlag)
+}
+
 // GetContexts returns the main contexts of the host
 func (host *vmHost) GetContexts() (
 	vmhost.BigIntContext,
@@ -265,11 +320,8 @@ func (host *vmHost) ClearContextStateStack() {
 	host.storageContext.ClearStateStack()
 }
 
-// Clean closes the currently running Wasmer instance
+// Clean closes the currently running Wasmer instance, or returns it to the warm instance pool
 func (host *vmHost) Clean() {
-	if host.runtimeContext.IsWarmInstance() {
-		return
-	}
 	host.runtimeContext.CleanWasmerInstance()
 }
 
@@ -294,6 +346,10 @@ func (host *vmHost) GasScheduleChange(newGasSchedule config.GasScheduleMap) {
 	wasmer.SetOpcodeCosts(&opcodeCosts)
 
 	host.meteringContext.SetGasSchedule(newGasSchedule)
+
+	if !check.IfNil(host.compiledCodeCache) {
+		host.runtimeContext.SetCompiledCodeCache(host.compiledCodeCache, config.GasScheduleVersion(newGasSchedule))
+	}
 }
 
 // GetGasScheduleMap returns the currently stored gas schedule
@@ -336,11 +392,16 @@ func (host *vmHost) RunSmartContractCall(input *vmcommon.ContractCallInput) (vmO
 		vmOutput = host.doRunSmartContractUpgrade(input)
 	}
 
+	host.traceCallStart(input)
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
 	tryCall := func() {
 		vmOutput = host.doRunSmartContractCall(input)
 
 		if host.hasRetriableExecutionError(vmOutput) {
-			log.Error("Retriable execution error detected. Will reset warm Wasmer instance.")
+			log.Error("Retriable execution error detected. Will reset warm Wasmer instances.")
 			host.runtimeContext.ResetWarmInstance()
 		}
 	}
@@ -360,6 +421,109 @@ func (host *vmHost) RunSmartContractCall(input *vmcommon.ContractCallInput) (vmO
 	return
 }
 
+// RunSmartContractQuery executes a call of an existing contract which must not change the state.
+// Storage writes, transfers, async calls and deployments anywhere in the call tree fail the
+// query with the ExecutionFailed return code, and the message names the offending VM hook.
+func (host *vmHost) RunSmartContractQuery(input *vmcommon.ContractCallInput) (vmOutput *vmcommon.VMOutput, err error) {
+	host.mutExecution.RLock()
+	defer host.mutExecution.RUnlock()
+
+	log.Trace("RunSmartContractQuery begin", "function", input.Function)
+
+	if input.Function == vmhost.UpgradeFunctionName {
+		err = fmt.Errorf("%w: %s", vmhost.ErrStateChangeInQuery, input.Function)
+		return host.createQueryViolationOutput(err), nil
+	}
+	if input.CallValue != nil && input.CallValue.Sign() > 0 {
+		err = fmt.Errorf("%w: call value", vmhost.ErrStateChangeInQuery)
+		return host.createQueryViolationOutput(err), nil
+	}
+
+	host.traceCallStart(input)
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
+	tryQuery := func() {
+		vmOutput = host.doRunSmartContractQuery(input)
+	}
+
+	catch := func(caught error) {
+		err = caught
+		log.Error("RunSmartContractQuery", "error", err)
+	}
+
+	TryCatch(tryQuery, catch, "vmhost.RunSmartContractQuery")
+
+	return
+}
+
+// SimulateCall executes the call of an existing contract against the current state, with
+// the given overrides applied on top of it. The overrides are discarded after the call, and
+// the resulting VMOutput is meant to be inspected, not applied. Built-in functions are
+// processed by the node and do not observe the overrides.
+func (host *vmHost) SimulateCall(input *vmcommon.ContractCallInput, overrides *vmhost.StateOverrides) (vmOutput *vmcommon.VMOutput, err error) {
+	host.mutExecution.Lock()
+	defer host.mutExecution.Unlock()
+
+	log.Trace("SimulateCall begin", "function", input.Function)
+
+	host.stateOverrideHook.setOverrides(overrides)
+	defer host.stateOverrideHook.setOverrides(nil)
+
+	host.traceCallStart(input)
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
+	trySimulate := func() {
+		vmOutput = host.doRunSmartContractCall(input)
+	}
+
+	catch := func(caught error) {
+		err = caught
+		log.Error("SimulateCall", "error", err)
+	}
+
+	TryCatch(trySimulate, catch, "vmhost.SimulateCall")
+
+	return
+}
+
+// RunSmartContractCallWithAccessList executes the call of an existing contract, after
+// pre-warming the storage keys declared in the access list. Each declared key is charged
+// the cold access cost up front, and all accesses to it during the call are charged at the
+// warm cost. Contract upgrades are not supported.
+func (host *vmHost) RunSmartContractCallWithAccessList(input *vmcommon.ContractCallInput, accessList vmhost.StorageAccessList) (vmOutput *vmcommon.VMOutput, err error) {
+	host.mutExecution.Lock()
+	defer host.mutExecution.Unlock()
+
+	log.Trace("RunSmartContractCallWithAccessList begin", "function", input.Function, "entries", len(accessList))
+
+	host.accessList = accessList
+	defer func() {
+		host.accessList = nil
+	}()
+
+	host.traceCallStart(input)
+	defer func() {
+		host.traceCallEnd(vmOutput)
+	}()
+
+	tryCall := func() {
+		vmOutput = host.doRunSmartContractCall(input)
+	}
+
+	catch := func(caught error) {
+		err = caught
+		log.Error("RunSmartContractCallWithAccessList", "error", err)
+	}
+
+	TryCatch(tryCall, catch, "vmhost.RunSmartContractCallWithAccessList")
+
+	return
+}
+
 // Close closes all internal instances of the vm
 func (host *vmHost) Close() error {
 	return nil
@@ -381,8 +545,15 @@ func TryCatch(try TryFunction, catch CatchFunction, catchFallbackMessage string)
 	try()
 }
 
+// hasRetriableExecutionError detects the allocation errors which may be caused by a reused instance.
+// Pooled instances are released before the call returns, so with the warm instance pool any
+// failed call may have used one; without the pool, the current instance is checked, as before.
 func (host *vmHost) hasRetriableExecutionError(vmOutput *vmcommon.VMOutput) bool {
-	if !host.runtimeContext.IsWarmInstance() {
+	mayUseReusedInstance := host.runtimeContext.IsWarmInstance()
+	if host.useWarmInstance {
+		mayUseReusedInstance = vmOutput != nil
+	}
+	if !mayUseReusedInstance {
 		return false
 	}
 
@@ -408,6 +579,43 @@ func (host *vmHost) SetRuntimeContext(runtime vmhost.RuntimeContext) {
 	host.runtimeContext = runtime
 }
 
+// SetCallTracer sets the tracer notified whenever a contract call starts or finishes executing
+func (host *vmHost) SetCallTracer(tracer vmhost.CallTracer) {
+	host.callTracer = tracer
+}
+
+// SetProbeTracer sets the tracer notified whenever an instrumented contract executes a coverage probe.
+// The probe import is only available to contracts while a tracer is set.
+func (host *vmHost) SetProbeTracer(tracer vmhost.ProbeTracer) {
+	host.probeTracer = tracer
+}
+
+// ProbeTracer returns the tracer of the coverage probes, or nil if none is set
+func (host *vmHost) ProbeTracer() vmhost.ProbeTracer {
+	return host.probeTracer
+}
+
+func (host *vmHost) traceCallStart(input *vmcommon.ContractCallInput) {
+	if check.IfNilReflect(host.callTracer) {
+		return
+	}
+
+	host.callTracer.TraceCallStart(input)
+}
+
+func (host *vmHost) traceCallEnd(vmOutput *vmcommon.VMOutput) {
+	if check.IfNilReflect(host.callTracer) {
+		return
+	}
+
+	if vmOutput == nil {
+		host.callTracer.TraceCallEnd(vmcommon.ExecutionFailed, 0)
+		return
+	}
+
+	host.callTracer.TraceCallEnd(vmOutput.ReturnCode, vmOutput.GasRemaining)
+}
+
 // GetRuntimeErrors obtains the cumultated error object after running the SC
 func (host *vmHost) GetRuntimeErrors() error {
 	if host.runtimeContext != nil {
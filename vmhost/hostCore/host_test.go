@@ -0,0 +1,24 @@
+package hostCore
+
+import (
+	"testing"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	contextmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVMHost_HasRetriableExecutionError(t *testing.T) {
+	allocationError := &vmcommon.VMOutput{ReturnMessage: "allocation error"}
+	otherError := &vmcommon.VMOutput{ReturnMessage: "other error"}
+
+	host := &vmHost{
+		runtimeContext: &contextmock.RuntimeContextMock{},
+	}
+	require.False(t, host.hasRetriableExecutionError(allocationError))
+
+	host.useWarmInstance = true
+	require.True(t, host.hasRetriableExecutionError(allocationError))
+	require.False(t, host.hasRetriableExecutionError(otherError))
+	require.False(t, host.hasRetriableExecutionError(nil))
+}
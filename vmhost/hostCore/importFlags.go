@@ -0,0 +1,84 @@
+package hostCore
+
+import (
+	"fmt"
+
+	"github.com/kalyan3104/k-chain-core-go/core"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+)
+
+// importFlags maps each VM hook import introduced after genesis to the flag that activates it.
+// Imports which are not listed are always available. A new hook must be registered here
+// together with its flag, so that contracts cannot use it before the flag is active.
+var importFlags = map[string]core.EnableEpochFlag{
+	"transferDCDTExecute":          BuiltInFunctionsFlag,
+	"transferDCDTNFTExecute":       BuiltInFunctionsFlag,
+	"multiTransferDCDTNFTExecute":  MultiDCDTTransferFlag,
+	"transferValueExecute":         BuiltInFunctionsFlag,
+	"getDCDTBalance":               BuiltInFunctionsFlag,
+	"getDCDTTokenData":             BuiltInFunctionsFlag,
+	"dcdtLocalMint":                DCDTManagementFlag,
+	"dcdtLocalBurn":                DCDTManagementFlag,
+	"dcdtNFTCreate":                DCDTManagementFlag,
+	"dcdtNFTAddQuantity":           DCDTManagementFlag,
+	"dcdtNFTBurn":                  DCDTManagementFlag,
+	"dcdtNFTUpdateAttributes":      DCDTManagementFlag,
+	"getDCDTLocalRoles":            DCDTManagementFlag,
+	"getDCDTTokenType":             BuiltInFunctionsFlag,
+	"getDCDTTokenNonce":            BuiltInFunctionsFlag,
+	"getNumDCDTTransfers":          MultiDCDTTransferFlag,
+	"getDCDTValueByIndex":          MultiDCDTTransferFlag,
+	"getDCDTTokenNameByIndex":      MultiDCDTTransferFlag,
+	"getDCDTTokenNonceByIndex":     MultiDCDTTransferFlag,
+	"getCurrentDCDTNFTNonce":       BuiltInFunctionsFlag,
+	"getDCDTNFTNameLength":         BuiltInFunctionsFlag,
+	"getDCDTNFTAttributeLength":    BuiltInFunctionsFlag,
+	"getDCDTNFTURILength":          BuiltInFunctionsFlag,
+	"bigIntGetDCDTExternalBalance": BuiltInFunctionsFlag,
+	"storageIterate":               StorageIterateFlag,
+	"getStorageSize":               StorageSizeFlag,
+}
+
+// ImportFlag returns the flag that activates the given import, if the import is gated by one
+func ImportFlag(importName string) (core.EnableEpochFlag, bool) {
+	flag, isGated := importFlags[importName]
+	return flag, isGated
+}
+
+// IsImportEnabled returns false if the import is gated by a flag which is not active yet.
+// The coverage probe import is only enabled while a probe tracer is set.
+func (host *vmHost) IsImportEnabled(importName string) bool {
+	if importName == wasmcov.ImportName {
+		return host.probeTracer != nil
+	}
+
+	flag, isGated := importFlags[importName]
+	if !isGated {
+		return true
+	}
+
+	return host.enableEpochsHandler.IsFlagEnabled(flag)
+}
+
+// checkImportFlags verifies that the registry only refers to existing imports and known flags
+func checkImportFlags(apiNames vmcommon.FunctionNames) error {
+	knownFlags := make(map[core.EnableEpochFlag]struct{})
+	for _, flag := range allFlags {
+		knownFlags[flag] = struct{}{}
+	}
+
+	for importName, flag := range importFlags {
+		_, exists := apiNames[importName]
+		if !exists {
+			return fmt.Errorf("%w: %s", vmhost.ErrInvalidImportFlag, importName)
+		}
+		_, isKnown := knownFlags[flag]
+		if !isKnown {
+			return fmt.Errorf("%w: %s gated by %s", vmhost.ErrInvalidImportFlag, importName, flag)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,356 @@
+package hostCore
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/kalyan3104/k-chain-core-go/data/dcdt"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// stateOverrideHook is the blockchain hook given to the contexts of the host. It passes
+// all requests through to the actual blockchain hook, except for the parts of the state
+// replaced by the overrides of a simulated call.
+type stateOverrideHook struct {
+	vmcommon.BlockchainHook
+	overrides *vmhost.StateOverrides
+}
+
+func newStateOverrideHook(blockChainHook vmcommon.BlockchainHook) *stateOverrideHook {
+	return &stateOverrideHook{
+		BlockchainHook: blockChainHook,
+	}
+}
+
+func (hook *stateOverrideHook) setOverrides(overrides *vmhost.StateOverrides) {
+	hook.overrides = overrides
+}
+
+// GetStorageData returns the overridden storage value, if any
+func (hook *stateOverrideHook) GetStorageData(accountAddress []byte, index []byte) ([]byte, uint32, error) {
+	accountOverride, ok := hook.overrides.GetAccount(accountAddress)
+	if ok {
+		value, isOverridden := accountOverride.Storage[string(index)]
+		if isOverridden {
+			return value, 0, nil
+		}
+	}
+
+	return hook.BlockchainHook.GetStorageData(accountAddress, index)
+}
+
+// IterateStorage enumerates the storage of an account through the actual blockchain hook,
+// with the overridden storage values applied on top
+func (hook *stateOverrideHook) IterateStorage(address []byte, prefix []byte, cursor []byte, limit int) ([]*vmhost.StorageEntry, error) {
+	iterator, ok := hook.BlockchainHook.(vmhost.StorageIterator)
+	if !ok {
+		return nil, vmhost.ErrStorageIterationNotSupported
+	}
+
+	accountOverride, isOverridden := hook.overrides.GetAccount(address)
+	if !isOverridden || len(accountOverride.Storage) == 0 {
+		return iterator.IterateStorage(address, prefix, cursor, limit)
+	}
+
+	entries, err := iterator.IterateStorage(address, prefix, cursor, limit+len(accountOverride.Storage))
+	if err != nil {
+		return nil, err
+	}
+
+	return vmhost.MergeStorageEntries(entries, accountOverride.Storage, prefix, cursor, limit), nil
+}
+
+// GetStorageSize returns the storage size of an account provided by the actual blockchain
+// hook, adjusted by the overridden storage values
+func (hook *stateOverrideHook) GetStorageSize(address []byte) (uint64, error) {
+	provider, ok := hook.BlockchainHook.(vmhost.StorageSizeProvider)
+	if !ok {
+		return 0, vmhost.ErrStorageSizeNotSupported
+	}
+
+	size, err := provider.GetStorageSize(address)
+	if err != nil {
+		return 0, err
+	}
+
+	accountOverride, isOverridden := hook.overrides.GetAccount(address)
+	if !isOverridden {
+		return size, nil
+	}
+
+	for key, value := range accountOverride.Storage {
+		actualValue, _, err := hook.BlockchainHook.GetStorageData(address, []byte(key))
+		if err != nil {
+			return 0, err
+		}
+
+		size = size + vmhost.StorageEntrySize([]byte(key), value) - vmhost.StorageEntrySize([]byte(key), actualValue)
+	}
+
+	return size, nil
+}
+
+// GetUserAccount returns the account, with its balance and code replaced by the overrides, if any.
+// Accounts which do not exist yet are created from their overrides.
+func (hook *stateOverrideHook) GetUserAccount(address []byte) (vmcommon.UserAccountHandler, error) {
+	account, err := hook.BlockchainHook.GetUserAccount(address)
+	accountOverride, ok := hook.overrides.GetAccount(address)
+	if !ok {
+		return account, err
+	}
+	if err != nil || vmhost.IfNil(account) {
+		account = newEmptyAccount(address)
+	}
+
+	return newOverriddenAccount(account, accountOverride), nil
+}
+
+// GetCode returns the replacement code of an overridden account, if any
+func (hook *stateOverrideHook) GetCode(account vmcommon.UserAccountHandler) []byte {
+	overridden, ok := account.(*overriddenAccount)
+	if !ok {
+		return hook.BlockchainHook.GetCode(account)
+	}
+	if overridden.accountOverride.Code != nil {
+		return overridden.accountOverride.Code
+	}
+	if _, isEmpty := overridden.UserAccountHandler.(*emptyAccount); isEmpty {
+		return nil
+	}
+
+	return hook.BlockchainHook.GetCode(overridden.UserAccountHandler)
+}
+
+// IsSmartContract returns true for accounts given replacement code
+func (hook *stateOverrideHook) IsSmartContract(address []byte) bool {
+	accountOverride, ok := hook.overrides.GetAccount(address)
+	if ok && len(accountOverride.Code) > 0 {
+		return true
+	}
+
+	return hook.BlockchainHook.IsSmartContract(address)
+}
+
+// GetDCDTToken returns the token, with its balance replaced by the overrides, if any
+func (hook *stateOverrideHook) GetDCDTToken(address []byte, tokenID []byte, nonce uint64) (*dcdt.DCDigitalToken, error) {
+	token, err := hook.BlockchainHook.GetDCDTToken(address, tokenID, nonce)
+	if err != nil || nonce > 0 {
+		return token, err
+	}
+
+	accountOverride, ok := hook.overrides.GetAccount(address)
+	if !ok {
+		return token, nil
+	}
+	balance, isOverridden := accountOverride.DCDTBalances[string(tokenID)]
+	if !isOverridden {
+		return token, nil
+	}
+
+	overriddenToken := &dcdt.DCDigitalToken{}
+	if token != nil {
+		*overriddenToken = *token
+	}
+	overriddenToken.Value = big.NewInt(0).Set(balance)
+
+	return overriddenToken, nil
+}
+
+// CurrentNonce returns the overridden block nonce, if any
+func (hook *stateOverrideHook) CurrentNonce() uint64 {
+	blockOverride := hook.blockOverride()
+	if blockOverride != nil && blockOverride.Nonce != nil {
+		return *blockOverride.Nonce
+	}
+
+	return hook.BlockchainHook.CurrentNonce()
+}
+
+// CurrentRound returns the overridden block round, if any
+func (hook *stateOverrideHook) CurrentRound() uint64 {
+	blockOverride := hook.blockOverride()
+	if blockOverride != nil && blockOverride.Round != nil {
+		return *blockOverride.Round
+	}
+
+	return hook.BlockchainHook.CurrentRound()
+}
+
+// CurrentTimeStamp returns the overridden block timestamp, if any
+func (hook *stateOverrideHook) CurrentTimeStamp() uint64 {
+	blockOverride := hook.blockOverride()
+	if blockOverride != nil && blockOverride.Timestamp != nil {
+		return *blockOverride.Timestamp
+	}
+
+	return hook.BlockchainHook.CurrentTimeStamp()
+}
+
+// CurrentEpoch returns the overridden block epoch, if any
+func (hook *stateOverrideHook) CurrentEpoch() uint32 {
+	blockOverride := hook.blockOverride()
+	if blockOverride != nil && blockOverride.Epoch != nil {
+		return *blockOverride.Epoch
+	}
+
+	return hook.BlockchainHook.CurrentEpoch()
+}
+
+// CurrentRandomSeed returns the overridden block random seed, if any
+func (hook *stateOverrideHook) CurrentRandomSeed() []byte {
+	blockOverride := hook.blockOverride()
+	if blockOverride != nil && blockOverride.RandomSeed != nil {
+		return blockOverride.RandomSeed
+	}
+
+	return hook.BlockchainHook.CurrentRandomSeed()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (hook *stateOverrideHook) IsInterfaceNil() bool {
+	return hook == nil
+}
+
+func (hook *stateOverrideHook) blockOverride() *vmhost.BlockOverride {
+	if hook.overrides == nil {
+		return nil
+	}
+
+	return hook.overrides.Block
+}
+
+// overriddenAccount is an account whose balance and code are replaced during a simulated call
+type overriddenAccount struct {
+	vmcommon.UserAccountHandler
+	accountOverride *vmhost.AccountOverride
+}
+
+func newOverriddenAccount(account vmcommon.UserAccountHandler, accountOverride *vmhost.AccountOverride) *overriddenAccount {
+	return &overriddenAccount{
+		UserAccountHandler: account,
+		accountOverride:    accountOverride,
+	}
+}
+
+// GetBalance returns the overridden balance, if any
+func (account *overriddenAccount) GetBalance() *big.Int {
+	if account.accountOverride.Balance != nil {
+		return big.NewInt(0).Set(account.accountOverride.Balance)
+	}
+
+	return account.UserAccountHandler.GetBalance()
+}
+
+// GetCodeHash returns the hash of the replacement code, if any, so that instances
+// compiled from the actual code are not reused for it
+func (account *overriddenAccount) GetCodeHash() []byte {
+	if account.accountOverride.Code != nil {
+		codeHash := sha256.Sum256(account.accountOverride.Code)
+		return codeHash[:]
+	}
+
+	return account.UserAccountHandler.GetCodeHash()
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (account *overriddenAccount) IsInterfaceNil() bool {
+	return account == nil
+}
+
+// emptyAccount stands for an account which does not exist yet, but is given overrides in a simulated call
+type emptyAccount struct {
+	address []byte
+}
+
+func newEmptyAccount(address []byte) *emptyAccount {
+	return &emptyAccount{
+		address: address,
+	}
+}
+
+// AddressBytes returns the address of the account
+func (account *emptyAccount) AddressBytes() []byte {
+	return account.address
+}
+
+// IncreaseNonce does nothing, since the simulated state changes are only reflected in the output
+func (account *emptyAccount) IncreaseNonce(_ uint64) {
+}
+
+// GetNonce returns 0
+func (account *emptyAccount) GetNonce() uint64 {
+	return 0
+}
+
+// GetCodeMetadata returns nil
+func (account *emptyAccount) GetCodeMetadata() []byte {
+	return nil
+}
+
+// SetCodeMetadata does nothing
+func (account *emptyAccount) SetCodeMetadata(_ []byte) {
+}
+
+// GetCodeHash returns nil
+func (account *emptyAccount) GetCodeHash() []byte {
+	return nil
+}
+
+// GetRootHash returns nil
+func (account *emptyAccount) GetRootHash() []byte {
+	return nil
+}
+
+// AccountDataHandler returns nil, the empty account has no storage
+func (account *emptyAccount) AccountDataHandler() vmcommon.AccountDataHandler {
+	return nil
+}
+
+// AddToBalance does nothing
+func (account *emptyAccount) AddToBalance(_ *big.Int) error {
+	return nil
+}
+
+// GetBalance returns 0
+func (account *emptyAccount) GetBalance() *big.Int {
+	return big.NewInt(0)
+}
+
+// ClaimDeveloperRewards returns 0
+func (account *emptyAccount) ClaimDeveloperRewards(_ []byte) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+// GetDeveloperReward returns 0
+func (account *emptyAccount) GetDeveloperReward() *big.Int {
+	return big.NewInt(0)
+}
+
+// ChangeOwnerAddress does nothing
+func (account *emptyAccount) ChangeOwnerAddress(_ []byte, _ []byte) error {
+	return nil
+}
+
+// SetOwnerAddress does nothing
+func (account *emptyAccount) SetOwnerAddress(_ []byte) {
+}
+
+// GetOwnerAddress returns nil
+func (account *emptyAccount) GetOwnerAddress() []byte {
+	return nil
+}
+
+// SetUserName does nothing
+func (account *emptyAccount) SetUserName(_ []byte) {
+}
+
+// GetUserName returns nil
+func (account *emptyAccount) GetUserName() []byte {
+	return nil
+}
+
+// IsInterfaceNil returns true if there is no value under the interface
+func (account *emptyAccount) IsInterfaceNil() bool {
+	return account == nil
+}
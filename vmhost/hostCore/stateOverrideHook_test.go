@@ -0,0 +1,73 @@
+package hostCore
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	contextmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/context"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/stretchr/testify/require"
+)
+
+var errAccountNotFound = errors.New("account not found")
+
+func newTestStateOverrideHook(overrides *vmhost.StateOverrides) *stateOverrideHook {
+	hook := newStateOverrideHook(&contextmock.BlockchainHookStub{
+		GetUserAccountCalled: func(address []byte) (vmcommon.UserAccountHandler, error) {
+			return nil, errAccountNotFound
+		},
+		GetCodeCalled: func(account vmcommon.UserAccountHandler) []byte {
+			return []byte("actual code")
+		},
+	})
+	hook.setOverrides(overrides)
+	return hook
+}
+
+func TestStateOverrideHook_GetUserAccount_MissingAccount(t *testing.T) {
+	address := []byte("new account")
+	code := []byte("replacement code")
+	hook := newTestStateOverrideHook(&vmhost.StateOverrides{
+		Accounts: map[string]*vmhost.AccountOverride{
+			string(address): {
+				Balance: big.NewInt(42),
+				Code:    code,
+			},
+		},
+	})
+
+	account, err := hook.GetUserAccount(address)
+	require.Nil(t, err)
+	require.Equal(t, address, account.AddressBytes())
+	require.Equal(t, uint64(0), account.GetNonce())
+	require.Equal(t, big.NewInt(42), account.GetBalance())
+	require.NotEmpty(t, account.GetCodeHash())
+	require.Equal(t, code, hook.GetCode(account))
+	require.True(t, hook.IsSmartContract(address))
+}
+
+func TestStateOverrideHook_GetUserAccount_MissingAccountWithoutCode(t *testing.T) {
+	address := []byte("new account")
+	hook := newTestStateOverrideHook(&vmhost.StateOverrides{
+		Accounts: map[string]*vmhost.AccountOverride{
+			string(address): {
+				Balance: big.NewInt(7),
+			},
+		},
+	})
+
+	account, err := hook.GetUserAccount(address)
+	require.Nil(t, err)
+	require.Equal(t, big.NewInt(7), account.GetBalance())
+	require.Nil(t, hook.GetCode(account))
+}
+
+func TestStateOverrideHook_GetUserAccount_MissingAccountWithoutOverride(t *testing.T) {
+	hook := newTestStateOverrideHook(&vmhost.StateOverrides{})
+
+	account, err := hook.GetUserAccount([]byte("missing"))
+	require.Equal(t, errAccountNotFound, err)
+	require.Nil(t, account)
+}
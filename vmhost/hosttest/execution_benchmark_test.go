@@ -6,7 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/kalyan3104/k-chain-core-go/core"
 	"github.com/kalyan3104/k-chain-core-go/data/vm"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
@@ -99,9 +98,7 @@ func deploy(tb testing.TB, totalTokenSupply *big.Int) (vmhost.VMHost, *worldmock
 		BuiltInFuncContainer: builtInFunctions.NewBuiltInFunctionContainer(),
 		ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
 		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-			},
+			IsFlagEnabledCalled: hostCore.IsKnownFlag,
 		},
 	})
 	require.Nil(tb, err)
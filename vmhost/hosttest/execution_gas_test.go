@@ -2,7 +2,9 @@ package hostCoretest
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
@@ -14,6 +16,7 @@ import (
 	worldmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
 	test "github.com/kalyan3104/k-chain-vm-v1_3-go/testcommon"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
 	"github.com/stretchr/testify/require"
 )
 
@@ -366,6 +369,686 @@ func TestGasUsed_DCDTTransferFailed(t *testing.T) {
 		})
 }
 
+func TestGasUsed_MultiDCDTTransfer_ThenExecuteCall_Success(t *testing.T) {
+	var parentAccount *worldmock.Account
+	initialDCDTTokenBalance := uint64(100)
+
+	testConfig := simpleGasTestConfig
+	testConfig.DCDTTokensToTransfer = 5
+	transferredValue := big.NewInt(int64(testConfig.DCDTTokensToTransfer)).Bytes()
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecMultiDCDTTransferWithAPICall),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.AcceptMultiPaymentChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execMultiDCDTTransferWithAPICall").
+			WithArguments(test.ChildAddress, []byte("acceptMultiPayment"), test.DCDTTestTokenName, test.DCDTTestSecondTokenName).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, initialDCDTTokenBalance)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestSecondTokenKey, initialDCDTTokenBalance)
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				GasUsed(test.ChildAddress, testConfig.GasUsedByChild).
+				ReturnData(
+					[]byte{2},
+					test.DCDTTestTokenName, transferredValue,
+					test.DCDTTestSecondTokenName, transferredValue)
+
+			childAccount := world.AcctMap.GetAccount(test.ChildAddress)
+			for _, tokenKey := range [][]byte{test.DCDTTestTokenKey, test.DCDTTestSecondTokenKey} {
+				parentDCDTBalance, _ := parentAccount.GetTokenBalanceUint64(tokenKey)
+				require.Equal(t, initialDCDTTokenBalance-testConfig.DCDTTokensToTransfer, parentDCDTBalance)
+
+				childDCDTBalance, _ := childAccount.GetTokenBalanceUint64(tokenKey)
+				require.Equal(t, testConfig.DCDTTokensToTransfer, childDCDTBalance)
+			}
+		})
+}
+
+func TestGasUsed_DCDTLocalMintAndBurn_Success(t *testing.T) {
+	var parentAccount *worldmock.Account
+	initialDCDTTokenBalance := uint64(100)
+	dcdtLocalMintGasCost := uint64(1)
+	dcdtLocalBurnGasCost := uint64(1)
+	getLocalRolesGasCost := uint64(1)
+
+	testConfig := simpleGasTestConfig
+	testConfig.GasProvided = 10000
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.DCDTLocalMintAndBurnMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("localMintAndBurn").
+			WithArguments(test.DCDTTestTokenName).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, initialDCDTTokenBalance)
+			_ = parentAccount.SetTokenRolesAsStrings(test.DCDTTestTokenName, []string{
+				core.DCDTRoleLocalMint,
+				core.DCDTRoleLocalBurn,
+			})
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			expectedRoles := int64(vmhost.RoleMint | vmhost.RoleBurn)
+			expectedGasUsed := testConfig.GasUsedByParent + dcdtLocalMintGasCost + dcdtLocalBurnGasCost + getLocalRolesGasCost
+			verify.
+				Ok().
+				GasUsed(test.ParentAddress, expectedGasUsed).
+				GasRemaining(testConfig.GasProvided - expectedGasUsed).
+				ReturnData(big.NewInt(expectedRoles).Bytes())
+
+			parentDCDTBalance, _ := parentAccount.GetTokenBalanceUint64(test.DCDTTestTokenKey)
+			require.Equal(t, initialDCDTTokenBalance+testConfig.DCDTTokensToTransfer, parentDCDTBalance)
+		})
+}
+
+func TestGasUsed_DCDTLocalMint_MissingRole(t *testing.T) {
+	testConfig := simpleGasTestConfig
+	testConfig.GasProvided = 10000
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.DCDTLocalMintAndBurnMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("localMintAndBurn").
+			WithArguments(test.DCDTTestTokenName).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				GasRemaining(0)
+		})
+}
+
+func TestGasUsed_DCDTLocalMint_FlagDisabled(t *testing.T) {
+	var parentAccount *worldmock.Account
+	initialDCDTTokenBalance := uint64(100)
+
+	testConfig := simpleGasTestConfig
+	testConfig.GasProvided = 10000
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.DCDTLocalMintAndBurnMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("localMintAndBurn").
+			WithArguments(test.DCDTTestTokenName).
+			Build()).
+		WithDisabledFlags(hostCore.DCDTManagementFlag).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, initialDCDTTokenBalance)
+			_ = parentAccount.SetTokenRolesAsStrings(test.DCDTTestTokenName, []string{
+				core.DCDTRoleLocalMint,
+				core.DCDTRoleLocalBurn,
+			})
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(fmt.Sprintf("%s: %s", vmhost.ErrImportNotEnabled, "dcdtLocalMint")).
+				GasRemaining(0)
+
+			parentDCDTBalance, _ := parentAccount.GetTokenBalanceUint64(test.DCDTTestTokenKey)
+			require.Equal(t, initialDCDTTokenBalance, parentDCDTBalance)
+		})
+}
+
+func TestGasUsed_DCDTNFTCreateAndBurn_Success(t *testing.T) {
+	var parentAccount *worldmock.Account
+	// the NFT create builtin also charges StorePerByte for each byte of its arguments
+	nftCreateArgumentsLength := uint64(len(test.DCDTTestTokenName) + len("name") + len("hash") + len("attributes") + len("uri") + 1)
+	dcdtNFTCreateGasCost := uint64(1) + nftCreateArgumentsLength
+	dcdtNFTBurnGasCost := uint64(1)
+
+	testConfig := simpleGasTestConfig
+	testConfig.GasProvided = 10000
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.DCDTNFTCreateAndBurnMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("nftCreateAndBurn").
+			WithArguments(test.DCDTTestTokenName).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenRolesAsStrings(test.DCDTTestTokenName, []string{
+				core.DCDTRoleNFTCreate,
+				core.DCDTRoleNFTAddQuantity,
+				core.DCDTRoleNFTBurn,
+			})
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			expectedGasUsed := testConfig.GasUsedByParent + dcdtNFTCreateGasCost + dcdtNFTBurnGasCost
+			verify.
+				Ok().
+				GasUsed(test.ParentAddress, expectedGasUsed).
+				GasRemaining(testConfig.GasProvided - expectedGasUsed).
+				ReturnData([]byte{1})
+
+			nftBalance, _ := parentAccount.GetTokenBalanceUint64(worldmock.MakeTokenKey(test.DCDTTestTokenName, 1))
+			require.Equal(t, testConfig.DCDTTokensToTransfer-1, nftBalance)
+		})
+}
+
+func TestGasUsed_DCDTNFTAddQuantityAndUpdateAttributes_Success(t *testing.T) {
+	var parentAccount *worldmock.Account
+	newAttributes := []byte("new attributes")
+	nftCreateArgumentsLength := uint64(len(test.DCDTTestTokenName) + len("name") + len("hash") + len("attributes") + len("uri") + 1)
+	dcdtNFTCreateGasCost := uint64(1) + nftCreateArgumentsLength
+	dcdtNFTAddQuantityGasCost := uint64(1)
+	// the update attributes builtin also charges StorePerByte for each byte of the new attributes
+	dcdtNFTUpdateAttributesGasCost := uint64(1) + uint64(len(newAttributes))
+
+	testConfig := simpleGasTestConfig
+	testConfig.GasProvided = 10000
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.DCDTNFTAddQuantityAndUpdateAttributesMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("nftAddQuantityAndUpdateAttributes").
+			WithArguments(test.DCDTTestTokenName, newAttributes).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenRolesAsStrings(test.DCDTTestTokenName, []string{
+				core.DCDTRoleNFTCreate,
+				core.DCDTRoleNFTAddQuantity,
+				core.DCDTRoleNFTUpdateAttributes,
+			})
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			expectedGasUsed := testConfig.GasUsedByParent + dcdtNFTCreateGasCost + dcdtNFTAddQuantityGasCost + dcdtNFTUpdateAttributesGasCost
+			verify.
+				Ok().
+				GasUsed(test.ParentAddress, expectedGasUsed).
+				GasRemaining(testConfig.GasProvided - expectedGasUsed).
+				ReturnData([]byte{1})
+
+			nftKey := worldmock.MakeTokenKey(test.DCDTTestTokenName, 1)
+			nftBalance, _ := parentAccount.GetTokenBalanceUint64(nftKey)
+			require.Equal(t, testConfig.DCDTTokensToTransfer+3, nftBalance)
+
+			nftData, err := parentAccount.GetTokenData(nftKey)
+			require.Nil(t, err)
+			require.Equal(t, newAttributes, nftData.TokenMetaData.Attributes)
+		})
+}
+
+func TestGasUsed_ExecuteReadOnly_StorageWriteRejected(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecReadOnlyParentMock),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.WriteStorageChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execReadOnly").
+			WithArguments(test.ChildAddress, []byte("writeStorage")).
+			Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ReturnCode(vmcommon.ExecutionFailed)
+
+			parentAccount := world.AcctMap.GetAccount(test.ParentAddress)
+			require.Empty(t, parentAccount.Storage[string(test.ChildKey)])
+		})
+}
+
+func TestGasUsed_ExecuteReadOnly_StorageWriteRejectedInQuery(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecReadOnlyParentMock),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.WriteStorageChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execReadOnly").
+			WithArguments(test.ChildAddress, []byte("writeStorage")).
+			Build()).
+		AsQuery().
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessageContains(vmhost.ErrStateChangeInReadOnly.Error())
+		})
+}
+
+func TestGasUsed_ExecuteReadOnly_TransferRejected(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecReadOnlyParentMock),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.TransferValueChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execReadOnly").
+			WithArguments(test.ChildAddress, []byte("transferValue")).
+			Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				HasRuntimeErrors(vmhost.ErrStateChangeInReadOnly.Error() + ": value transfer")
+		})
+}
+
+func TestGasUsed_ExecuteReadOnly_StorageIterate(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	runStorageIterateTest := func(cursor []byte, limit int64, expectedReturnData ...[]byte) {
+		test.BuildMockInstanceCallTest(t).
+			WithContracts(
+				test.CreateMockContract(test.ParentAddress).
+					WithBalance(testConfig.ParentBalance).
+					WithConfig(testConfig).
+					WithMethods(contracts.ExecReadOnlyParentMock),
+				test.CreateMockContract(test.ChildAddress).
+					WithBalance(testConfig.ChildBalance).
+					WithConfig(testConfig).
+					WithMethods(contracts.IterateStorageChildMock),
+			).
+			WithInput(test.CreateTestContractCallInputBuilder().
+				WithRecipientAddr(test.ParentAddress).
+				WithGasProvided(testConfig.GasProvided).
+				WithFunction("execReadOnly").
+				WithArguments(
+					test.ChildAddress,
+					[]byte("iterateStorage"),
+					[]byte("parentKey"),
+					cursor,
+					big.NewInt(limit).Bytes(),
+				).
+				Build()).
+			WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+				parentAccount := world.AcctMap.GetAccount(test.ParentAddress)
+				parentAccount.Storage[string(test.ParentKeyB)] = test.ParentDataB
+				parentAccount.Storage[string(test.ParentKeyA)] = test.ParentDataA
+				parentAccount.Storage[string(test.ChildKey)] = test.ChildData
+			}).
+			AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+				verify.Ok().
+					ReturnData(expectedReturnData...)
+			})
+	}
+
+	runStorageIterateTest(nil, 10, test.ParentKeyA, test.ParentDataA, test.ParentKeyB, test.ParentDataB)
+	runStorageIterateTest(nil, 1, test.ParentKeyA, test.ParentDataA)
+	runStorageIterateTest(test.ParentKeyA, 10, test.ParentKeyB, test.ParentDataB)
+}
+
+func TestGasUsed_StorageIterate_NotReadOnly(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.IterateStorageChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ChildAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("iterateStorage").
+			WithArguments([]byte("childKey"), nil, big.NewInt(10).Bytes()).
+			Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ReturnCode(vmcommon.ExecutionFailed).
+				HasRuntimeErrors(vmhost.ErrStorageIterationNotAllowed.Error())
+		})
+}
+
+func TestGasUsed_ExecuteReadOnly_StorageIterate_FlagDisabled(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecReadOnlyParentMock),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.IterateStorageChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execReadOnly").
+			WithArguments(test.ChildAddress, []byte("iterateStorage"), []byte("parentKey"), nil, big.NewInt(10).Bytes()).
+			Build()).
+		WithDisabledFlags(hostCore.StorageIterateFlag).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ReturnCode(vmcommon.ExecutionFailed).
+				HasRuntimeErrors(fmt.Sprintf("%s: %s", vmhost.ErrImportNotEnabled, "storageIterate"))
+		})
+}
+
+func TestGasUsed_StorageSize(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.StorageSizeParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("writeAndGetStorageSize").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount := world.AcctMap.GetAccount(test.ParentAddress)
+			parentAccount.Storage[string(test.ParentKeyA)] = test.ParentDataA
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			existingSize := len(test.ParentKeyA) + len(test.ParentDataA)
+			addedSize := len(test.ChildKey) + len(test.ChildData)
+			verify.Ok().
+				ReturnData(big.NewInt(int64(existingSize + addedSize)).Bytes())
+
+			parentAccount := verify.VmOutput.OutputAccounts[string(test.ParentAddress)]
+			require.Equal(t, uint64(addedSize), parentAccount.BytesAddedToStorage)
+			require.Equal(t, uint64(0), parentAccount.BytesDeletedFromStorage)
+		})
+}
+
+func TestGasUsed_StorageSize_FlagDisabled(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.StorageSizeParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("writeAndGetStorageSize").
+			Build()).
+		WithDisabledFlags(hostCore.StorageSizeFlag).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ReturnCode(vmcommon.ExecutionFailed).
+				HasRuntimeErrors(fmt.Sprintf("%s: %s", vmhost.ErrImportNotEnabled, "getStorageSize"))
+		})
+}
+
+func TestGasUsed_DCDTTransfer_StorageSizeOfBuiltinFunction(t *testing.T) {
+	testConfig := simpleGasTestConfig
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecDCDTTransferAndCallChild),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.WasteGasChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execDCDTTransferAndCall").
+			WithArguments(test.ChildAddress, []byte("DCDTTransfer"), []byte("wasteGas")).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount := world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, 100)
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok()
+
+			childAccount := world.AcctMap.GetAccount(test.ChildAddress)
+			childOutputAccount := verify.VmOutput.OutputAccounts[string(test.ChildAddress)]
+			require.NotZero(t, childAccount.StorageSize())
+			require.Equal(t, childAccount.StorageSize(), childOutputAccount.BytesAddedToStorage)
+			require.Equal(t, uint64(0), childOutputAccount.BytesDeletedFromStorage)
+		})
+}
+
+func TestGasUsed_MultiDCDTTransfer_ThenReadByIndex(t *testing.T) {
+	testConfig := simpleGasTestConfig
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecMultiDCDTTransferWithAPICall),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ReadMultiPaymentByIndexChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execMultiDCDTTransferWithAPICall").
+			WithArguments(test.ChildAddress, []byte("readMultiPaymentByIndex"), test.DCDTTestTokenName, test.DCDTTestSecondTokenName).
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount := world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, 100)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestSecondTokenKey, 100)
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(
+					[]byte{2},
+					test.DCDTTestTokenName, []byte{},
+					test.DCDTTestSecondTokenName, []byte{})
+		})
+}
+
+func TestMultiDCDTTransfer_FlagDisabled_ImportsRejected(t *testing.T) {
+	imports := []string{
+		"multiTransferDCDTNFTExecute",
+		"getNumDCDTTransfers",
+		"getDCDTValueByIndex",
+		"getDCDTTokenNameByIndex",
+		"getDCDTTokenNonceByIndex",
+	}
+
+	for _, importName := range imports {
+		host, _, instanceBuilder := test.DefaultTestVMForCallWithInstanceMocksAndDisabledFlags(t, hostCore.MultiDCDTTransferFlag)
+		instance := instanceBuilder.CreateAndStoreInstanceMock(t, host, test.ParentAddress, 0, 0)
+		instance.AddMockImport(importName)
+
+		host.Runtime().MustVerifyNextContractCode()
+		err := host.Runtime().StartWasmerInstance(test.ParentAddress, simpleGasTestConfig.GasProvided, true)
+		require.True(t, errors.Is(err, vmhost.ErrImportNotEnabled), importName)
+		require.Contains(t, err.Error(), importName)
+	}
+
+	host, _, instanceBuilder := test.DefaultTestVMForCallWithInstanceMocksAndDisabledFlags(t, hostCore.BuiltInFunctionsFlag)
+	instance := instanceBuilder.CreateAndStoreInstanceMock(t, host, test.ParentAddress, 0, 0)
+	for _, importName := range imports {
+		instance.AddMockImport(importName)
+	}
+
+	host.Runtime().MustVerifyNextContractCode()
+	err := host.Runtime().StartWasmerInstance(test.ParentAddress, simpleGasTestConfig.GasProvided, true)
+	require.Nil(t, err)
+}
+
+func TestMultiDCDTTransfer_FlagDisabled_TransferFails(t *testing.T) {
+	var parentAccount *worldmock.Account
+	initialDCDTTokenBalance := uint64(100)
+
+	testConfig := simpleGasTestConfig
+	testConfig.DCDTTokensToTransfer = 5
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ExecMultiDCDTTransferWithAPICall),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.AcceptMultiPaymentChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("execMultiDCDTTransferWithAPICall").
+			WithArguments(test.ChildAddress, []byte("acceptMultiPayment"), test.DCDTTestTokenName, test.DCDTTestSecondTokenName).
+			Build()).
+		WithDisabledFlags(hostCore.MultiDCDTTransferFlag).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			parentAccount = world.AcctMap.GetAccount(test.ParentAddress)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestTokenKey, initialDCDTTokenBalance)
+			_ = parentAccount.SetTokenBalanceUint64(test.DCDTTestSecondTokenKey, initialDCDTTokenBalance)
+			createMockBuiltinFunctions(t, host, world)
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(fmt.Sprintf("%s: %s", vmhost.ErrImportNotEnabled, "multiTransferDCDTNFTExecute"))
+
+			for _, tokenKey := range [][]byte{test.DCDTTestTokenKey, test.DCDTTestSecondTokenKey} {
+				parentDCDTBalance, _ := parentAccount.GetTokenBalanceUint64(tokenKey)
+				require.Equal(t, initialDCDTTokenBalance, parentDCDTBalance)
+			}
+		})
+}
+
+func TestMultiDCDTTransfer_FlagDisabled_GettersFail(t *testing.T) {
+	testConfig := simpleGasTestConfig
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(testConfig).
+				WithMethods(contracts.ReadMultiPaymentByIndexChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ChildAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("readMultiPaymentByIndex").
+			Build()).
+		WithDisabledFlags(hostCore.MultiDCDTTransferFlag).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(fmt.Sprintf("%s: %s", vmhost.ErrImportNotEnabled, "getNumDCDTTransfers")).
+				GasRemaining(0)
+		})
+}
+
 func TestGasUsed_DCDTTransferFromParent_ChildBurnsAndThenFails(t *testing.T) {
 	var parentAccount *worldmock.Account
 	initialDCDTTokenBalance := uint64(100)
@@ -1283,6 +1966,226 @@ func createMockBuiltinFunctions(tb testing.TB, host vmhost.VMHost, world *worldm
 	host.SetBuiltInFunctionsContainer(world.BuiltinFuncs.Container)
 }
 
+var asyncContextTestConfig = &contracts.AsyncContextTestConfig{
+	AsyncCallBaseTestConfig: asyncBaseTestConfig,
+	AsyncCalls:              2,
+	GasForAsyncCall:         50,
+}
+
+func TestGasUsed_CreateAsyncCall(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+
+	createAsyncCallCost := uint64(10)
+	setCallbackCost := uint64(5)
+	storedLength := len(contracts.AsyncContextIdentifier) + len(contracts.AsyncContextCallData) +
+		len(contracts.AsyncContextSuccessCallback) + len(contracts.AsyncContextErrorCallback)
+	gasPerAsyncCall := createAsyncCallCost + uint64(storedLength) + testConfig.GasLockCost
+	gasForCallback := setCallbackCost + uint64(len(testConfig.Callback))
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, createAsyncCallCost, setCallbackCost)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(
+					big.NewInt(int64(gasPerAsyncCall)).Bytes(),
+					big.NewInt(int64(gasPerAsyncCall)).Bytes(),
+					big.NewInt(int64(gasForCallback)).Bytes(),
+				)
+		})
+}
+
+func TestGasUsed_CreateAsyncCall_MeteringDisabled(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+	testConfig.AsyncCalls = 4
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithDisabledFlags(hostCore.AsyncContextMeteringFlag).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, 10, 5)
+			host.Metering().GasSchedule().AsyncContextCost.MaxAsyncCallsPerContext = 3
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(
+					[]byte{},
+					[]byte{},
+					[]byte{},
+					[]byte{},
+					[]byte{},
+				)
+		})
+}
+
+func TestGasUsed_CreateAsyncCall_NotEnoughGasForCallback(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.GasProvided = testConfig.GasLockCost
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, 0, 0)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(vmhost.ErrNotEnoughGas.Error()).
+				GasRemaining(0)
+		})
+}
+
+func TestGasUsed_CreateAsyncCall_NotEnoughGasForAsyncCall(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.AsyncCalls = 1
+	testConfig.GasForAsyncCall = int64(testConfig.GasProvided)
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, 0, 0)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(vmhost.ErrNotEnoughGas.Error()).
+				GasRemaining(0)
+		})
+}
+
+func TestGasUsed_CreateAsyncCall_TooManyAsyncCalls(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.AsyncCalls = 4
+	testConfig.GasForAsyncCall = 0
+	testConfig.GasProvided = 1_000_000
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, 1, 1)
+			host.Metering().GasSchedule().AsyncContextCost.MaxAsyncCallsPerContext = 3
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				ReturnCode(vmcommon.ExecutionFailed).
+				ReturnMessage(vmhost.ErrTooManyAsyncCalls.Error()).
+				GasRemaining(0)
+		})
+}
+
+func TestGasUsed_CreateAsyncCall_CompletedContext(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+	testConfig.GasProvided = 10_000
+	testConfig.GasForAsyncCall = int64(testConfig.GasUsedByChild + testConfig.GasLockCost)
+
+	gasPerAsyncCall := big.NewInt(int64(testConfig.GasLockCost)).Bytes()
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.CreateAsyncCallsParentMock, contracts.AsyncContextCallbacksParentMock),
+			test.CreateMockContract(test.ChildAddress).
+				WithBalance(testConfig.ChildBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.AsyncContextChildMock),
+		).
+		WithInput(test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(test.ParentAddress).
+			WithGasProvided(testConfig.GasProvided).
+			WithFunction("createAsyncCalls").
+			Build()).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			setAsyncCosts(host, testConfig.GasLockCost)
+			setAsyncContextCosts(host, 0, 0)
+			host.Metering().GasSchedule().BaseOperationCost.DataCopyPerByte = 0
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				GasUsed(test.ChildAddress, testConfig.GasUsedByChild*uint64(testConfig.AsyncCalls)).
+				ReturnData(
+					gasPerAsyncCall,
+					gasPerAsyncCall,
+					[]byte{},
+					[]byte("child"),
+					contracts.AsyncContextSuccessCallback,
+					[]byte("child"),
+					contracts.AsyncContextSuccessCallback,
+					[]byte(testConfig.Callback),
+				)
+		})
+}
+
 func setZeroCodeCosts(host vmhost.VMHost) {
 	host.Metering().GasSchedule().BaseOperationCost.CompilePerByte = 0
 	host.Metering().GasSchedule().BaseOperationCost.AoTPreparePerByte = 0
@@ -1299,6 +2202,12 @@ func setAsyncCosts(host vmhost.VMHost, gasLock uint64) {
 	host.Metering().GasSchedule().BaseOpsAPICost.AsyncCallbackGasLock = gasLock
 }
 
+func setAsyncContextCosts(host vmhost.VMHost, createAsyncCallCost uint64, setCallbackCost uint64) {
+	host.Metering().GasSchedule().BaseOperationCost.DataCopyPerByte = 1
+	host.Metering().GasSchedule().AsyncContextCost.CreateAsyncCall = createAsyncCallCost
+	host.Metering().GasSchedule().AsyncContextCost.SetAsyncContextCallback = setCallbackCost
+}
+
 func computeReturnDataForCallback(returnCode vmcommon.ReturnCode, returnData [][]byte) []byte {
 	retData := []byte("@" + hex.EncodeToString([]byte(returnCode.String())))
 	for _, data := range returnData {
@@ -1306,3 +2215,161 @@ func computeReturnDataForCallback(returnCode vmcommon.ReturnCode, returnData [][
 	}
 	return retData
 }
+
+func TestAsyncContext_ResumeFromPersistedContext(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+	testConfig.GasProvided = 10_000
+
+	callbackInput := createPersistedAsyncContextCallbackInput(&testConfig, []byte("ok"))
+	asyncInfo := createPersistedAsyncContext(testConfig.Callback, 2)
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.AsyncContextCallbacksParentMock),
+		).
+		WithInput(callbackInput).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			persistAsyncContext(t, world, callbackInput.OriginalTxHash, asyncInfo)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(contracts.AsyncContextSuccessCallback)
+
+			storedAsyncInfo := getPersistedAsyncContext(t, verify.VmOutput, callbackInput.OriginalTxHash)
+			require.Len(t, storedAsyncInfo.AsyncContextMap, 1)
+			storedContext := storedAsyncInfo.AsyncContextMap[string(contracts.AsyncContextIdentifier)]
+			require.Len(t, storedContext.AsyncCalls, 1)
+			require.Equal(t, testConfig.Callback, storedContext.Callback)
+		})
+}
+
+func TestAsyncContext_ResumeFromPersistedContext_CompletesContext(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+	testConfig.GasProvided = 10_000
+
+	callbackInput := createPersistedAsyncContextCallbackInput(&testConfig, []byte("user error"))
+	asyncInfo := createPersistedAsyncContext(testConfig.Callback, 1)
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.AsyncContextCallbacksParentMock),
+		).
+		WithInput(callbackInput).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			persistAsyncContext(t, world, callbackInput.OriginalTxHash, asyncInfo)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(
+					contracts.AsyncContextErrorCallback,
+					[]byte(testConfig.Callback),
+				)
+
+			storedAsyncInfo := getPersistedAsyncContext(t, verify.VmOutput, callbackInput.OriginalTxHash)
+			require.Empty(t, storedAsyncInfo.AsyncContextMap)
+		})
+}
+
+func TestAsyncContext_ResumeFromPersistedContext_CallbacksDisabled(t *testing.T) {
+	testConfig := *asyncContextTestConfig
+	testConfig.Callback = "contextCallback"
+	testConfig.GasProvided = 10_000
+
+	callbackInput := createPersistedAsyncContextCallbackInput(&testConfig, []byte("user error"))
+	asyncInfo := createPersistedAsyncContext(testConfig.Callback, 1)
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(
+			test.CreateMockContract(test.ParentAddress).
+				WithBalance(testConfig.ParentBalance).
+				WithConfig(&testConfig).
+				WithMethods(contracts.AsyncContextCallbacksParentMock),
+		).
+		WithInput(callbackInput).
+		WithDisabledFlags(hostCore.AsyncContextCallbacksFlag).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			setZeroCodeCosts(host)
+			persistAsyncContext(t, world, callbackInput.OriginalTxHash, asyncInfo)
+		}).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.
+				Ok().
+				ReturnData(contracts.AsyncContextSuccessCallback)
+
+			storedAsyncInfo := getPersistedAsyncContext(t, verify.VmOutput, callbackInput.OriginalTxHash)
+			require.Empty(t, storedAsyncInfo.AsyncContextMap)
+		})
+}
+
+func createPersistedAsyncContextCallbackInput(testConfig *contracts.AsyncContextTestConfig, returnCode []byte) *vmcommon.ContractCallInput {
+	input := test.CreateTestContractCallInputBuilder().
+		WithRecipientAddr(test.ParentAddress).
+		WithCallerAddr(test.ChildAddress).
+		WithGasProvided(testConfig.GasProvided).
+		WithFunction(vmhost.CallbackFunctionName).
+		WithCallType(vm.AsynchronousCallBack).
+		WithArguments(returnCode).
+		Build()
+	input.OriginalTxHash = []byte("original tx hash")
+	return input
+}
+
+// createPersistedAsyncContext creates the async context left behind by a transaction
+// whose async calls were sent to the child in another shard
+func createPersistedAsyncContext(callback string, numAsyncCalls int) *vmhost.AsyncContextInfo {
+	asyncContext := &vmhost.AsyncContext{
+		Callback:   callback,
+		AsyncCalls: make([]*vmhost.AsyncGeneratedCall, 0, numAsyncCalls),
+	}
+	for i := 0; i < numAsyncCalls; i++ {
+		asyncContext.AsyncCalls = append(asyncContext.AsyncCalls, &vmhost.AsyncGeneratedCall{
+			Status:          vmhost.AsyncCallPending,
+			Destination:     test.ChildAddress,
+			Data:            contracts.AsyncContextCallData,
+			SuccessCallback: string(contracts.AsyncContextSuccessCallback),
+			ErrorCallback:   string(contracts.AsyncContextErrorCallback),
+		})
+	}
+
+	return &vmhost.AsyncContextInfo{
+		CallerAddr: test.UserAddress,
+		AsyncContextMap: map[string]*vmhost.AsyncContext{
+			string(contracts.AsyncContextIdentifier): asyncContext,
+		},
+	}
+}
+
+func persistAsyncContext(t *testing.T, world *worldmock.MockWorld, txHash []byte, asyncInfo *vmhost.AsyncContextInfo) {
+	data, err := json.Marshal(asyncInfo)
+	require.Nil(t, err)
+
+	storageKey := vmhost.CustomStorageKey(vmhost.AsyncDataPrefix, txHash)
+	world.AcctMap.GetAccount(test.ParentAddress).Storage[string(storageKey)] = data
+}
+
+func getPersistedAsyncContext(t *testing.T, vmOutput *vmcommon.VMOutput, txHash []byte) *vmhost.AsyncContextInfo {
+	outputAccount := vmOutput.OutputAccounts[string(test.ParentAddress)]
+	require.NotNil(t, outputAccount)
+
+	storageKey := vmhost.CustomStorageKey(vmhost.AsyncDataPrefix, txHash)
+	storageUpdate := outputAccount.StorageUpdates[string(storageKey)]
+	require.NotNil(t, storageUpdate)
+
+	asyncInfo := &vmhost.AsyncContextInfo{}
+	if len(storageUpdate.Data) > 0 {
+		require.Nil(t, json.Unmarshal(storageUpdate.Data, asyncInfo))
+	}
+	return asyncInfo
+}
@@ -289,6 +289,130 @@ func TestExecution_ManyDeployments(t *testing.T) {
 	}
 }
 
+func TestExecution_WarmInstance_SharedCodeDoesNotShareGlobals(t *testing.T) {
+	code := test.GetTestSCCode("global-counter", "../../")
+	host, world := test.DefaultTestVMWithWorldMockAndWarmInstances(t)
+	world.AcctMap.CreateAccount(test.UserAddress, world)
+
+	contractA := test.AddTestSmartContractToWorld(world, "contractA", code)
+	contractB := test.AddTestSmartContractToWorld(world, "contractB", code)
+	contractA.CodeHash = []byte("global-counter code hash")
+	contractB.CodeHash = contractA.CodeHash
+
+	for _, contract := range []*worldmock.Account{contractA, contractB, contractA} {
+		input := test.CreateTestContractCallInputBuilder().
+			WithRecipientAddr(contract.Address).
+			WithFunction(increment).
+			WithGasProvided(1000000).
+			Build()
+
+		vmOutput, err := host.RunSmartContractCall(input)
+		verify := test.NewVMOutputVerifier(t, vmOutput, err)
+		verify.
+			Ok().
+			ReturnData([]byte{1})
+	}
+
+	require.Equal(t, uint64(2), host.Runtime().GetWarmInstancePoolMetrics().Hits)
+}
+
+func TestExecution_EstimateGas(t *testing.T) {
+	code := test.GetTestSCCode("counter", "../../")
+	host, _ := test.DefaultTestVMForCall(t, code, nil)
+
+	input := test.DefaultTestContractCallInput()
+	input.GasProvided = 1000000
+	input.Function = increment
+
+	estimate, err := host.EstimateGas(input)
+	require.Nil(t, err)
+	require.NotNil(t, estimate)
+	require.Equal(t, estimate.GasUsed, estimate.MinimumGasLimit)
+	require.Zero(t, estimate.GasLocked)
+	require.Greater(t, estimate.RecommendedGasLimit, estimate.MinimumGasLimit)
+	require.LessOrEqual(t, estimate.RecommendedGasLimit, input.GasProvided)
+
+	input.GasProvided = estimate.MinimumGasLimit
+	vmOutput, err := host.RunSmartContractCall(input)
+	verify := test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.Ok()
+
+	input.GasProvided = estimate.MinimumGasLimit - 1
+	vmOutput, err = host.RunSmartContractCall(input)
+	verify = test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.ReturnCode(vmcommon.OutOfGas)
+}
+
+func TestExecution_EstimateGas_FailingCall(t *testing.T) {
+	code := test.GetTestSCCode("counter", "../../")
+	host, _ := test.DefaultTestVMForCall(t, code, nil)
+
+	input := test.DefaultTestContractCallInput()
+	input.GasProvided = 1000000
+	input.Function = "missingFunction"
+
+	estimate, err := host.EstimateGas(input)
+	require.Nil(t, estimate)
+	require.True(t, errors.Is(err, vmhost.ErrGasEstimationFailed))
+}
+
+func TestExecution_Query_ViewFunction(t *testing.T) {
+	code := test.GetTestSCCode("counter", "../../")
+	host, _ := test.DefaultTestVMForCall(t, code, nil)
+
+	input := test.DefaultTestContractCallInput()
+	input.GasProvided = 1000000
+	input.Function = get
+
+	vmOutput, err := host.RunSmartContractQuery(input)
+
+	verify := test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.
+		Ok().
+		ReturnData([]byte{})
+}
+
+func TestExecution_Query_StorageWriteRejected(t *testing.T) {
+	code := test.GetTestSCCode("counter", "../../")
+	host, _ := test.DefaultTestVMForCall(t, code, nil)
+
+	input := test.DefaultTestContractCallInput()
+	input.GasProvided = 1000000
+	input.Function = increment
+
+	vmOutput, err := host.RunSmartContractQuery(input)
+
+	verify := test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.
+		ReturnCode(vmcommon.ExecutionFailed).
+		ReturnMessage(vmhost.ErrStateChangeInQuery.Error() + ": int64storageStore")
+	require.False(t, host.Runtime().QueryMode())
+
+	vmOutput, err = host.RunSmartContractCall(input)
+
+	verify = test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.
+		Ok().
+		ReturnData([]byte{1})
+}
+
+func TestExecution_Query_CallValueRejected(t *testing.T) {
+	code := test.GetTestSCCode("counter", "../../")
+	host, _ := test.DefaultTestVMForCall(t, code, nil)
+
+	input := test.DefaultTestContractCallInput()
+	input.GasProvided = 1000000
+	input.Function = get
+	input.CallValue = big.NewInt(1)
+
+	vmOutput, err := host.RunSmartContractQuery(input)
+
+	verify := test.NewVMOutputVerifier(t, vmOutput, err)
+	verify.
+		ReturnCode(vmcommon.ExecutionFailed).
+		ReturnMessageContains(vmhost.ErrStateChangeInQuery.Error())
+}
+
 func TestExecution_MultipleVMs_OverlappingContractInstanceData(t *testing.T) {
 	code := test.GetTestSCCode("counter", "../../")
 
@@ -1,6 +1,7 @@
 package vmhost
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/kalyan3104/k-chain-core-go/core"
@@ -42,8 +43,14 @@ type VMHost interface {
 	IsDynamicGasLockingEnabled() bool
 	IsVMV3Enabled() bool
 	IsDCDTFunctionsEnabled() bool
+	IsStrictReadOnlyEnabled() bool
+	IsAsyncContextMeteringEnabled() bool
+	IsStorageSizeEnabled() bool
+	IsStorageAccessCostEnabled() bool
+	IsImportEnabled(importName string) bool
 
 	ExecuteDCDTTransfer(destination []byte, sender []byte, tokenIdentifier []byte, nonce uint64, value *big.Int, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
+	ExecuteMultiDCDTTransfer(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
 	CreateNewContract(input *vmcommon.ContractCreateInput) ([]byte, error)
 	ExecuteOnSameContext(input *vmcommon.ContractCallInput) (*AsyncContextInfo, error)
 	ExecuteOnDestContext(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, *AsyncContextInfo, error)
@@ -54,6 +61,13 @@ type VMHost interface {
 	GetGasScheduleMap() config.GasScheduleMap
 	GetContexts() (BigIntContext, BlockchainContext, MeteringContext, OutputContext, RuntimeContext, StorageContext)
 	SetRuntimeContext(runtime RuntimeContext)
+	SetCallTracer(tracer CallTracer)
+	SetProbeTracer(tracer ProbeTracer)
+	ProbeTracer() ProbeTracer
+	RunSmartContractQuery(input *vmcommon.ContractCallInput) (*vmcommon.VMOutput, error)
+	SimulateCall(input *vmcommon.ContractCallInput, overrides *StateOverrides) (*vmcommon.VMOutput, error)
+	RunSmartContractCallWithAccessList(input *vmcommon.ContractCallInput, accessList StorageAccessList) (*vmcommon.VMOutput, error)
+	EstimateGas(input *vmcommon.ContractCallInput) (*GasEstimate, error)
 
 	SetBuiltInFunctionsContainer(builtInFuncs vmcommon.BuiltInFunctionContainer)
 	InitState()
@@ -130,11 +144,15 @@ type RuntimeContext interface {
 	IsFunctionImported(name string) bool
 	IsWarmInstance() bool
 	ResetWarmInstance()
+	GetWarmInstancePoolMetrics() WarmInstancePoolMetrics
 	ReadOnly() bool
 	SetReadOnly(readOnly bool)
+	QueryMode() bool
+	SetQueryMode(queryMode bool)
 	StartWasmerInstance(contract []byte, gasLimit uint64, newCode bool) error
 	CleanWasmerInstance()
 	SetMaxInstanceCount(uint64)
+	SetCompiledCodeCache(cache CompiledCodeCache, gasScheduleVersion []byte)
 	VerifyContractCode() error
 	GetInstance() wasmer.InstanceHandler
 	GetInstanceExports() wasmer.ExportsMap
@@ -154,6 +172,8 @@ type RuntimeContext interface {
 	AddError(err error, otherInfo ...string)
 	GetAllErrors() error
 
+	SetFunctionCallTracer(tracer FunctionCallTracer)
+
 	// TODO remove after implementing proper mocking of Wasmer instances; this is
 	// used for tests only
 	ReplaceInstanceBuilder(builder InstanceBuilder)
@@ -183,6 +203,7 @@ type OutputContext interface {
 	TransferValueOnly(destination []byte, sender []byte, value *big.Int, checkPayable bool) error
 	Transfer(destination []byte, sender []byte, gasLimit uint64, gasLocked uint64, value *big.Int, input []byte, callType vm.CallType) error
 	TransferDCDT(destination []byte, sender []byte, tokenIdentifier []byte, nonce uint64, value *big.Int, callInput *vmcommon.ContractCallInput) (uint64, error)
+	TransferMultiDCDT(destination []byte, sender []byte, transfers []*vmcommon.DCDTTransfer, callInput *vmcommon.ContractCallInput) (uint64, error)
 	SelfDestruct(address []byte, beneficiary []byte)
 	GetRefund() uint64
 	SetRefund(refund uint64)
@@ -223,6 +244,7 @@ type MeteringContext interface {
 	DeductInitialGasForDirectDeployment(input CodeDeployInput) error
 	DeductInitialGasForIndirectDeployment(input CodeDeployInput) error
 	ComputeGasLockedForAsync() uint64
+	ComputeGasLockedForAsyncWithCodeSize(codeSize uint64) uint64
 	UseGasForAsyncStep() error
 	UseGasBounded(gasToUse uint64) error
 	GetGasLocked() uint64
@@ -248,10 +270,27 @@ const (
 	StorageDeleted
 )
 
+// String returns the name of the storage status
+func (status StorageStatus) String() string {
+	switch status {
+	case StorageUnchanged:
+		return "unchanged"
+	case StorageModified:
+		return "modified"
+	case StorageAdded:
+		return "added"
+	case StorageDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(status))
+	}
+}
+
 // StorageContext defines the functionality needed for interacting with the storage context
 type StorageContext interface {
 	StateStack
 
+	PopMergeActiveState()
 	SetAddress(address []byte)
 	GetStorageUpdates(address []byte) map[string]*vmcommon.StorageUpdate
 	GetStorageFromAddress(address []byte, key []byte) []byte
@@ -259,6 +298,43 @@ type StorageContext interface {
 	GetStorageUnmetered(key []byte) []byte
 	SetStorage(key []byte, value []byte) (StorageStatus, error)
 	SetProtectedStorage(key []byte, value []byte) (StorageStatus, error)
+	PrewarmStorageKeys(accessList StorageAccessList) error
+	IterateStorage(prefix []byte, cursor []byte, limit int) ([]*StorageEntry, error)
+	GetStorageSize() (uint64, error)
+	GetBlockchainStorageSizes(addresses [][]byte) map[string]uint64
+	AddStorageSizeChanges(vmOutput *vmcommon.VMOutput, sizesBefore map[string]uint64)
+}
+
+// StorageIterator is implemented by blockchain hooks which can enumerate the storage of an
+// account; it returns at most limit entries whose keys start with the prefix and come strictly
+// after the cursor, sorted by key
+type StorageIterator interface {
+	IterateStorage(address []byte, prefix []byte, cursor []byte, limit int) ([]*StorageEntry, error)
+}
+
+// StorageSizeProvider is implemented by blockchain hooks which keep track of the number of bytes
+// stored by each account, counting the length of each key holding a value plus the length of the value
+type StorageSizeProvider interface {
+	GetStorageSize(address []byte) (uint64, error)
+}
+
+// FunctionCallTracer is notified each time the runtime resolves an exported
+// contract function for execution, including init and callbacks
+type FunctionCallTracer interface {
+	TraceFunctionCall(codeHash []byte, functionName string, exportedFunctions []string)
+}
+
+// CallTracer is notified each time the host starts and finishes executing a
+// contract call, including nested calls, synchronous async calls and callbacks
+type CallTracer interface {
+	TraceCallStart(input *vmcommon.ContractCallInput)
+	TraceCallEnd(returnCode vmcommon.ReturnCode, gasRemaining uint64)
+}
+
+// ProbeTracer is notified each time a contract instrumented with coverage probes
+// executes one of them, see the wasmcov package
+type ProbeTracer interface {
+	TraceProbe(probeID int32)
 }
 
 // AsyncCallInfoHandler defines the functionality for working with AsyncCallInfo
@@ -276,6 +352,13 @@ type InstanceBuilder interface {
 	NewInstanceFromCompiledCodeWithOptions(compiledCode []byte, options wasmer.CompilationOptions) (wasmer.InstanceHandler, error)
 }
 
+// CompiledCodeCache stores the compiled code of contracts on the host side
+type CompiledCodeCache interface {
+	Get(key []byte) ([]byte, bool)
+	Put(key []byte, compiledCode []byte)
+	IsInterfaceNil() bool
+}
+
 // EnableEpochsHandler is used to verify which flags are set in a specific epoch based on EnableEpochs config
 type EnableEpochsHandler interface {
 	IsFlagDefined(flag core.EnableEpochFlag) bool
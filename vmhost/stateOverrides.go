@@ -0,0 +1,48 @@
+package vmhost
+
+import (
+	"math/big"
+)
+
+// StateOverrides is a hypothetical state against which a contract call is simulated,
+// applied on top of the actual state and discarded once the simulation ends
+type StateOverrides struct {
+	// Accounts holds the overrides of existing accounts, keyed by address
+	Accounts map[string]*AccountOverride
+	Block    *BlockOverride
+}
+
+// AccountOverride replaces parts of the state of an existing account during a simulation;
+// nil fields keep the actual values
+type AccountOverride struct {
+	Code    []byte
+	Balance *big.Int
+	// Storage holds the overridden storage values, keyed by storage key
+	Storage map[string][]byte
+	// DCDTBalances holds the overridden fungible token balances, keyed by token identifier
+	DCDTBalances map[string]*big.Int
+}
+
+// BlockOverride replaces the current block information during a simulation;
+// nil fields keep the actual values
+type BlockOverride struct {
+	Nonce      *uint64
+	Round      *uint64
+	Timestamp  *uint64
+	Epoch      *uint32
+	RandomSeed []byte
+}
+
+// GetAccount returns the override of the account with the given address, if any
+func (so *StateOverrides) GetAccount(address []byte) (*AccountOverride, bool) {
+	if so == nil {
+		return nil, false
+	}
+
+	accountOverride, ok := so.Accounts[string(address)]
+	if !ok || accountOverride == nil {
+		return nil, false
+	}
+
+	return accountOverride, true
+}
@@ -0,0 +1,80 @@
+package vmhost
+
+import (
+	"bytes"
+	"sort"
+)
+
+// StorageEntry is a storage key of an account, together with its value
+type StorageEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// StorageEntrySize returns the number of bytes occupied in storage by the given key and value;
+// keys without a value occupy no space
+func StorageEntrySize(key []byte, value []byte) uint64 {
+	if len(value) == 0 {
+		return 0
+	}
+
+	return uint64(len(key) + len(value))
+}
+
+// IsStorageKeyInRange returns true if the key starts with the given prefix and comes strictly
+// after the cursor; an empty cursor places no lower bound on the key
+func IsStorageKeyInRange(key []byte, prefix []byte, cursor []byte) bool {
+	if !bytes.HasPrefix(key, prefix) {
+		return false
+	}
+
+	return len(cursor) == 0 || bytes.Compare(key, cursor) > 0
+}
+
+// MergeStorageEntries overlays the given storage values on top of the given entries and returns at
+// most limit of the resulting entries which are in range, sorted by key. Overlaid empty values
+// signify deleted keys, which are left out of the result.
+func MergeStorageEntries(
+	entries []*StorageEntry,
+	overlay map[string][]byte,
+	prefix []byte,
+	cursor []byte,
+	limit int,
+) []*StorageEntry {
+	merged := make(map[string][]byte, len(entries)+len(overlay))
+	for _, entry := range entries {
+		if entry == nil || !IsStorageKeyInRange(entry.Key, prefix, cursor) {
+			continue
+		}
+		merged[string(entry.Key)] = entry.Value
+	}
+	for key, value := range overlay {
+		if !IsStorageKeyInRange([]byte(key), prefix, cursor) {
+			continue
+		}
+		merged[key] = value
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key, value := range merged {
+		if len(value) == 0 {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if limit >= 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	result := make([]*StorageEntry, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, &StorageEntry{
+			Key:   []byte(key),
+			Value: merged[key],
+		})
+	}
+
+	return result
+}
@@ -16,6 +16,7 @@ package vmhooks
 // extern int32_t		v1_3_transferDCDT(void *context, int32_t dstOffset, int32_t tokenIDOffset, int32_t tokenIdLen, int32_t valueOffset, long long gasLimit, int32_t dataOffset, int32_t length);
 // extern int32_t		v1_3_transferDCDTExecute(void *context, int32_t dstOffset, int32_t tokenIDOffset, int32_t tokenIdLen, int32_t valueOffset, long long gasLimit, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
 // extern int32_t		v1_3_transferDCDTNFTExecute(void *context, int32_t dstOffset, int32_t tokenIDOffset, int32_t tokenIdLen, int32_t valueOffset, long long nonce, long long gasLimit, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
+// extern int32_t		v1_3_multiTransferDCDTNFTExecute(void *context, int32_t dstOffset, int32_t numTokenTransfers, int32_t tokenTransfersArgsLengthOffset, int32_t tokenTransferDataOffset, long long gasLimit, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
 // extern int32_t		v1_3_transferValueExecute(void *context, int32_t dstOffset, int32_t valueOffset, long long gasLimit, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
 // extern int32_t		v1_3_getArgumentLength(void *context, int32_t id);
 // extern int32_t		v1_3_getArgument(void *context, int32_t id, int32_t argOffset);
@@ -25,6 +26,8 @@ package vmhooks
 // extern int32_t		v1_3_storageLoadLength(void *context, int32_t keyOffset, int32_t keyLength );
 // extern int32_t		v1_3_storageLoad(void *context, int32_t keyOffset, int32_t keyLength , int32_t dataOffset);
 // extern int32_t		v1_3_storageLoadFromAddress(void *context, int32_t addressOffset, int32_t keyOffset, int32_t keyLength , int32_t dataOffset);
+// extern long long	v1_3_getStorageSize(void *context);
+// extern int32_t		v1_3_storageIterate(void *context, int32_t prefixOffset, int32_t prefixLength, int32_t cursorOffset, int32_t cursorLength, int32_t limit, int32_t lengthsOffset, int32_t dataOffset);
 // extern void			v1_3_getCaller(void *context, int32_t resultOffset);
 // extern void			v1_3_checkNoPayment(void *context);
 // extern int32_t		v1_3_callValue(void *context, int32_t resultOffset);
@@ -32,6 +35,10 @@ package vmhooks
 // extern int32_t		v1_3_getDCDTTokenName(void *context, int32_t resultOffset);
 // extern long long	v1_3_getDCDTTokenNonce(void *context);
 // extern int32_t		v1_3_getDCDTTokenType(void *context);
+// extern int32_t		v1_3_getNumDCDTTransfers(void *context);
+// extern int32_t		v1_3_getDCDTValueByIndex(void *context, int32_t resultOffset, int32_t index);
+// extern int32_t		v1_3_getDCDTTokenNameByIndex(void *context, int32_t resultOffset, int32_t index);
+// extern long long	v1_3_getDCDTTokenNonceByIndex(void *context, int32_t index);
 // extern long long v1_3_getCurrentDCDTNFTNonce(void *context, int32_t addressOffset, int32_t tokenIDOffset, int32_t tokenIDLen);
 // extern int32_t		v1_3_getCallValueTokenName(void *context, int32_t callValueOffset, int32_t tokenNameOffset);
 // extern void			v1_3_writeLog(void *context, int32_t pointer, int32_t length, int32_t topicPtr, int32_t numTopics);
@@ -44,6 +51,13 @@ package vmhooks
 // extern int32_t		v1_3_getDCDTNFTAttributeLength(void *context, int32_t addressOffset, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce);
 // extern int32_t		v1_3_getDCDTNFTURILength(void *context, int32_t addressOffset, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce);
 // extern int32_t		v1_3_getDCDTTokenData(void *context, int32_t addressOffset, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce, int32_t valueOffset, int32_t propertiesOffset, int32_t hashOffset, int32_t nameOffset, int32_t attributesOffset, int32_t creatorOffset, int32_t royaltiesOffset, int32_t urisOffset);
+// extern int32_t		v1_3_dcdtLocalMint(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, int32_t valueOffset);
+// extern int32_t		v1_3_dcdtLocalBurn(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, int32_t valueOffset);
+// extern long long	v1_3_dcdtNFTCreate(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, int32_t valueOffset, int32_t nameOffset, int32_t nameLength, int32_t royalties, int32_t hashOffset, int32_t hashLength, int32_t attributesOffset, int32_t attributesLength, int32_t numURIs, int32_t urisLengthOffset, int32_t urisDataOffset);
+// extern int32_t		v1_3_dcdtNFTAddQuantity(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce, int32_t valueOffset);
+// extern int32_t		v1_3_dcdtNFTBurn(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce, int32_t valueOffset);
+// extern int32_t		v1_3_dcdtNFTUpdateAttributes(void *context, int32_t tokenIDOffset, int32_t tokenIDLen, long long nonce, int32_t attributesOffset, int32_t attributesLength);
+// extern long long	v1_3_getDCDTLocalRoles(void *context, int32_t tokenIDOffset, int32_t tokenIDLen);
 //
 // extern int32_t		v1_3_executeOnDestContext(void *context, long long gas, int32_t addressOffset, int32_t valueOffset, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
 // extern int32_t		v1_3_executeOnDestContextByCaller(void *context, long long gas, int32_t addressOffset, int32_t valueOffset, int32_t functionOffset, int32_t functionLength, int32_t numArguments, int32_t argumentsLengthOffset, int32_t dataOffset);
@@ -113,6 +127,14 @@ func getFirstDCDTTransferIfExist(vmInput *vmcommon.VMInput) *vmcommon.DCDTTransf
 	}
 }
 
+func getDCDTTransferFromInput(vmInput *vmcommon.VMInput, index int32) (*vmcommon.DCDTTransfer, error) {
+	dcdtTransfers := vmInput.DCDTTransfers
+	if index < 0 || int(index) >= len(dcdtTransfers) {
+		return nil, vmhost.ErrInvalidTokenIndex
+	}
+	return dcdtTransfers[index], nil
+}
+
 // BaseOpsAPIImports creates a new wasmer.Imports populated with the BaseOpsAPI API methods
 func BaseOpsAPIImports() (*wasmer.Imports, error) {
 	imports := wasmer.NewImports()
@@ -163,6 +185,11 @@ func BaseOpsAPIImports() (*wasmer.Imports, error) {
 		return nil, err
 	}
 
+	imports, err = imports.Append("multiTransferDCDTNFTExecute", v1_3_multiTransferDCDTNFTExecute, C.v1_3_multiTransferDCDTNFTExecute)
+	if err != nil {
+		return nil, err
+	}
+
 	imports, err = imports.Append("transferDCDT", v1_3_transferDCDT, C.v1_3_transferDCDT)
 	if err != nil {
 		return nil, err
@@ -223,6 +250,16 @@ func BaseOpsAPIImports() (*wasmer.Imports, error) {
 		return nil, err
 	}
 
+	imports, err = imports.Append("storageIterate", v1_3_storageIterate, C.v1_3_storageIterate)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("getStorageSize", v1_3_getStorageSize, C.v1_3_getStorageSize)
+	if err != nil {
+		return nil, err
+	}
+
 	imports, err = imports.Append("storageLoadFromAddress", v1_3_storageLoadFromAddress, C.v1_3_storageLoadFromAddress)
 	if err != nil {
 		return nil, err
@@ -283,6 +320,26 @@ func BaseOpsAPIImports() (*wasmer.Imports, error) {
 		return nil, err
 	}
 
+	imports, err = imports.Append("getNumDCDTTransfers", v1_3_getNumDCDTTransfers, C.v1_3_getNumDCDTTransfers)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("getDCDTValueByIndex", v1_3_getDCDTValueByIndex, C.v1_3_getDCDTValueByIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("getDCDTTokenNameByIndex", v1_3_getDCDTTokenNameByIndex, C.v1_3_getDCDTTokenNameByIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("getDCDTTokenNonceByIndex", v1_3_getDCDTTokenNonceByIndex, C.v1_3_getDCDTTokenNonceByIndex)
+	if err != nil {
+		return nil, err
+	}
+
 	imports, err = imports.Append("getCurrentDCDTNFTNonce", v1_3_getCurrentDCDTNFTNonce, C.v1_3_getCurrentDCDTNFTNonce)
 	if err != nil {
 		return nil, err
@@ -448,6 +505,41 @@ func BaseOpsAPIImports() (*wasmer.Imports, error) {
 		return nil, err
 	}
 
+	imports, err = imports.Append("dcdtLocalMint", v1_3_dcdtLocalMint, C.v1_3_dcdtLocalMint)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("dcdtLocalBurn", v1_3_dcdtLocalBurn, C.v1_3_dcdtLocalBurn)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("dcdtNFTCreate", v1_3_dcdtNFTCreate, C.v1_3_dcdtNFTCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("dcdtNFTAddQuantity", v1_3_dcdtNFTAddQuantity, C.v1_3_dcdtNFTAddQuantity)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("dcdtNFTBurn", v1_3_dcdtNFTBurn, C.v1_3_dcdtNFTBurn)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("dcdtNFTUpdateAttributes", v1_3_dcdtNFTUpdateAttributes, C.v1_3_dcdtNFTUpdateAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err = imports.Append("getDCDTLocalRoles", v1_3_getDCDTLocalRoles, C.v1_3_getDCDTLocalRoles)
+	if err != nil {
+		return nil, err
+	}
+
 	imports, err = imports.Append("getDCDTNFTNameLength", v1_3_getDCDTNFTNameLength, C.v1_3_getDCDTNFTNameLength)
 	if err != nil {
 		return nil, err
@@ -649,6 +741,10 @@ func v1_3_getDCDTBalance(
 	nonce int64,
 	resultOffset int32,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTBalance") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	dcdtData, err := getDCDTDataFromBlockchainHook(context, addressOffset, tokenIDOffset, tokenIDLen, nonce)
 	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
@@ -670,6 +766,10 @@ func v1_3_getDCDTNFTNameLength(
 	tokenIDLen int32,
 	nonce int64,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTNFTNameLength") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	dcdtData, err := getDCDTDataFromBlockchainHook(context, addressOffset, tokenIDOffset, tokenIDLen, nonce)
 	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
@@ -690,6 +790,10 @@ func v1_3_getDCDTNFTAttributeLength(
 	tokenIDLen int32,
 	nonce int64,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTNFTAttributeLength") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	dcdtData, err := getDCDTDataFromBlockchainHook(context, addressOffset, tokenIDOffset, tokenIDLen, nonce)
 	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
@@ -710,6 +814,10 @@ func v1_3_getDCDTNFTURILength(
 	tokenIDLen int32,
 	nonce int64,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTNFTURILength") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	dcdtData, err := getDCDTDataFromBlockchainHook(context, addressOffset, tokenIDOffset, tokenIDLen, nonce)
 	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
@@ -741,6 +849,10 @@ func v1_3_getDCDTTokenData(
 	royaltiesHandle int32,
 	urisOffset int32,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTTokenData") {
+		return 0
+	}
+
 	bigInt := vmhost.GetBigIntContext(context)
 	runtime := vmhost.GetRuntimeContext(context)
 	dcdtData, err := getDCDTDataFromBlockchainHook(context, addressOffset, tokenIDOffset, tokenIDLen, nonce)
@@ -789,6 +901,10 @@ func v1_3_getDCDTTokenData(
 
 //export v1_3_transferValue
 func v1_3_transferValue(context unsafe.Pointer, destOffset int32, valueOffset int32, dataOffset int32, length int32) int32 {
+	if vmhost.FailIfStateChange(context, "transferValue") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -946,6 +1062,13 @@ func v1_3_transferValueExecute(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "transferValueExecute") {
+		return 1
+	}
+	if vmhost.FailIfStateChange(context, "transferValueExecute") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	return TransferValueExecuteWithHost(
 		host,
@@ -1082,6 +1205,10 @@ func v1_3_transferDCDT(
 	dataOffset int32,
 	length int32,
 ) int32 {
+	if vmhost.FailIfStateChange(context, "transferDCDT") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	metering := host.Metering()
 
@@ -1109,6 +1236,13 @@ func v1_3_transferDCDTExecute(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "transferDCDTExecute") {
+		return 1
+	}
+	if vmhost.FailIfStateChange(context, "transferDCDTExecute") {
+		return 1
+	}
+
 	return v1_3_transferDCDTNFTExecute(context, destOffset, tokenIDOffset, tokenIDLen, valueOffset, 0,
 		gasLimit, functionOffset, functionLength, numArguments, argumentsLengthOffset, dataOffset)
 }
@@ -1128,6 +1262,13 @@ func v1_3_transferDCDTNFTExecute(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "transferDCDTNFTExecute") {
+		return 1
+	}
+	if vmhost.FailIfStateChange(context, "transferDCDTNFTExecute") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	return TransferDCDTNFTExecuteWithHost(
 		host,
@@ -1265,6 +1406,571 @@ func TransferDCDTNFTExecuteWithTypedArgs(
 	return 0
 }
 
+//export v1_3_multiTransferDCDTNFTExecute
+func v1_3_multiTransferDCDTNFTExecute(
+	context unsafe.Pointer,
+	destOffset int32,
+	numTokenTransfers int32,
+	tokenTransfersArgsLengthOffset int32,
+	tokenTransferDataOffset int32,
+	gasLimit int64,
+	functionOffset int32,
+	functionLength int32,
+	numArguments int32,
+	argumentsLengthOffset int32,
+	dataOffset int32,
+) int32 {
+	if vmhost.FailIfStateChange(context, "multiTransferDCDTNFTExecute") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	return MultiTransferDCDTNFTExecuteWithHost(
+		host,
+		destOffset,
+		numTokenTransfers,
+		tokenTransfersArgsLengthOffset,
+		tokenTransferDataOffset,
+		gasLimit,
+		functionOffset,
+		functionLength,
+		numArguments,
+		argumentsLengthOffset,
+		dataOffset)
+}
+
+// MultiTransferDCDTNFTExecuteWithHost contains only memory reading of arguments; the tokens are
+// given as consecutive (token identifier, nonce, value) triples of arguments
+func MultiTransferDCDTNFTExecuteWithHost(
+	host vmhost.VMHost,
+	destOffset int32,
+	numTokenTransfers int32,
+	tokenTransfersArgsLengthOffset int32,
+	tokenTransferDataOffset int32,
+	gasLimit int64,
+	functionOffset int32,
+	functionLength int32,
+	numArguments int32,
+	argumentsLengthOffset int32,
+	dataOffset int32,
+) int32 {
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	if numTokenTransfers <= 0 {
+		_ = vmhost.WithFaultAndHost(host, vmhost.ErrFailedTransfer, runtime.BaseOpsErrorShouldFailExecution())
+		return 1
+	}
+
+	callArgs, err := extractIndirectContractCallArgumentsWithoutValue(
+		host, destOffset, functionOffset, functionLength, numArguments, argumentsLengthOffset, dataOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	transferArgs, actualLen, err := getArgumentsFromMemory(
+		host,
+		numTokenTransfers*parsers.ArgsPerTransfer,
+		tokenTransfersArgsLengthOffset,
+		tokenTransferDataOffset,
+	)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	actualLen = math.AddInt32(actualLen, callArgs.actualLen)
+	gasToUse := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, uint64(actualLen))
+	metering.UseGas(gasToUse)
+
+	transfers := make([]*vmcommon.DCDTTransfer, numTokenTransfers)
+	for i := int32(0); i < numTokenTransfers; i++ {
+		tokenStartIndex := i * parsers.ArgsPerTransfer
+		transfer := &vmcommon.DCDTTransfer{
+			DCDTTokenName:  transferArgs[tokenStartIndex],
+			DCDTTokenNonce: big.NewInt(0).SetBytes(transferArgs[tokenStartIndex+1]).Uint64(),
+			DCDTValue:      big.NewInt(0).SetBytes(transferArgs[tokenStartIndex+2]),
+			DCDTTokenType:  uint32(core.Fungible),
+		}
+		if transfer.DCDTTokenNonce > 0 {
+			transfer.DCDTTokenType = uint32(core.NonFungible)
+		}
+		transfers[i] = transfer
+	}
+
+	return MultiTransferDCDTNFTExecuteWithTypedArgs(
+		host,
+		callArgs.dest,
+		transfers,
+		gasLimit,
+		callArgs.function,
+		callArgs.args,
+	)
+}
+
+// MultiTransferDCDTNFTExecuteWithTypedArgs defines the actual multi transfer DCDT execute logic
+func MultiTransferDCDTNFTExecuteWithTypedArgs(
+	host vmhost.VMHost,
+	dest []byte,
+	transfers []*vmcommon.DCDTTransfer,
+	gasLimit int64,
+	function []byte,
+	data [][]byte,
+) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "multiTransferDCDTNFTExecute") {
+		return 1
+	}
+
+	var executeErr error
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+	output := host.Output()
+
+	gasToUse := math.MulUint64(metering.GasSchedule().BaseOpsAPICost.TransferValue, uint64(len(transfers)))
+	metering.UseGas(gasToUse)
+
+	sender := runtime.GetSCAddress()
+
+	var contractCallInput *vmcommon.ContractCallInput
+	if function != nil {
+		contractCallInput, executeErr = prepareIndirectContractCallInput(
+			host,
+			sender,
+			big.NewInt(0),
+			gasLimit,
+			dest,
+			function,
+			data,
+			gasToUse,
+			false,
+		)
+		if vmhost.WithFaultAndHost(host, executeErr, runtime.SyncExecAPIErrorShouldFailExecution()) {
+			return 1
+		}
+
+		contractCallInput.DCDTTransfers = transfers
+	}
+
+	snapshotBeforeTransfer := host.Blockchain().GetSnapshot()
+
+	gasLimitForExec, executeErr := output.TransferMultiDCDT(dest, sender, transfers, contractCallInput)
+	if vmhost.WithFaultAndHost(host, executeErr, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	if host.AreInSameShard(sender, dest) && contractCallInput != nil && host.Blockchain().IsSmartContract(dest) {
+		contractCallInput.GasProvided = gasLimitForExec
+		logEEI.Trace("multi DCDT post-transfer execution begin")
+		_, _, executeErr = host.ExecuteOnDestContext(contractCallInput)
+		if executeErr != nil {
+			logEEI.Trace("multi DCDT post-transfer execution failed", "error", executeErr)
+			host.Blockchain().RevertToSnapshot(snapshotBeforeTransfer)
+			return 1
+		}
+
+		return 0
+	}
+
+	return 0
+}
+
+//export v1_3_dcdtLocalMint
+func v1_3_dcdtLocalMint(context unsafe.Pointer, tokenIDOffset int32, tokenIDLen int32, valueOffset int32) int32 {
+	if vmhost.FailIfStateChange(context, "dcdtLocalMint") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	tokenID, value, err := loadTokenIDAndValue(host, tokenIDOffset, tokenIDLen, valueOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return DCDTLocalMintWithTypedArgs(host, tokenID, value)
+}
+
+// DCDTLocalMintWithTypedArgs mints the given quantity of a fungible token into
+// the balance of the current contract, which must hold the local mint role
+func DCDTLocalMintWithTypedArgs(host vmhost.VMHost, tokenID []byte, value *big.Int) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtLocalMint") {
+		return 1
+	}
+
+	_, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTLocalMint, [][]byte{tokenID, value.Bytes()})
+	if vmhost.WithFaultAndHost(host, err, host.Runtime().BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return 0
+}
+
+//export v1_3_dcdtLocalBurn
+func v1_3_dcdtLocalBurn(context unsafe.Pointer, tokenIDOffset int32, tokenIDLen int32, valueOffset int32) int32 {
+	if vmhost.FailIfStateChange(context, "dcdtLocalBurn") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	tokenID, value, err := loadTokenIDAndValue(host, tokenIDOffset, tokenIDLen, valueOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return DCDTLocalBurnWithTypedArgs(host, tokenID, value)
+}
+
+// DCDTLocalBurnWithTypedArgs burns the given quantity of a fungible token from
+// the balance of the current contract, which must hold the local burn role
+func DCDTLocalBurnWithTypedArgs(host vmhost.VMHost, tokenID []byte, value *big.Int) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtLocalBurn") {
+		return 1
+	}
+
+	_, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTLocalBurn, [][]byte{tokenID, value.Bytes()})
+	if vmhost.WithFaultAndHost(host, err, host.Runtime().BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return 0
+}
+
+//export v1_3_dcdtNFTCreate
+func v1_3_dcdtNFTCreate(
+	context unsafe.Pointer,
+	tokenIDOffset int32,
+	tokenIDLen int32,
+	valueOffset int32,
+	nameOffset int32,
+	nameLength int32,
+	royalties int32,
+	hashOffset int32,
+	hashLength int32,
+	attributesOffset int32,
+	attributesLength int32,
+	numURIs int32,
+	urisLengthOffset int32,
+	urisDataOffset int32,
+) int64 {
+	if vmhost.FailIfStateChange(context, "dcdtNFTCreate") {
+		return -1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	if royalties < 0 {
+		_ = vmhost.WithFaultAndHost(host, vmhost.ErrNegativeRoyalties, runtime.BaseOpsErrorShouldFailExecution())
+		return -1
+	}
+
+	tokenID, value, err := loadTokenIDAndValue(host, tokenIDOffset, tokenIDLen, valueOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	name, err := runtime.MemLoad(nameOffset, nameLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	hash, err := runtime.MemLoad(hashOffset, hashLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	attributes, err := runtime.MemLoad(attributesOffset, attributesLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	uris, urisLen, err := getArgumentsFromMemory(host, numURIs, urisLengthOffset, urisDataOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	actualLen := math.AddInt32(math.AddInt32(nameLength, hashLength), math.AddInt32(attributesLength, urisLen))
+	gasToUse := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, uint64(actualLen))
+	metering.UseGas(gasToUse)
+
+	return DCDTNFTCreateWithTypedArgs(host, tokenID, value, name, uint32(royalties), hash, attributes, uris)
+}
+
+// DCDTNFTCreateWithTypedArgs creates a new NFT of the given token in the balance of the
+// current contract, which must hold the NFT create role, and returns its nonce, or -1 on failure
+func DCDTNFTCreateWithTypedArgs(
+	host vmhost.VMHost,
+	tokenID []byte,
+	value *big.Int,
+	name []byte,
+	royalties uint32,
+	hash []byte,
+	attributes []byte,
+	uris [][]byte,
+) int64 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtNFTCreate") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+
+	arguments := [][]byte{
+		tokenID,
+		value.Bytes(),
+		name,
+		big.NewInt(int64(royalties)).Bytes(),
+		hash,
+		attributes,
+	}
+	arguments = append(arguments, uris...)
+
+	vmOutput, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTNFTCreate, arguments)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+	if len(vmOutput.ReturnData) == 0 {
+		_ = vmhost.WithFaultAndHost(host, vmhost.ErrExecutionFailed, runtime.BaseOpsErrorShouldFailExecution())
+		return -1
+	}
+
+	return int64(big.NewInt(0).SetBytes(vmOutput.ReturnData[0]).Uint64())
+}
+
+//export v1_3_dcdtNFTAddQuantity
+func v1_3_dcdtNFTAddQuantity(context unsafe.Pointer, tokenIDOffset int32, tokenIDLen int32, nonce int64, valueOffset int32) int32 {
+	if vmhost.FailIfStateChange(context, "dcdtNFTAddQuantity") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	tokenID, value, err := loadTokenIDAndValue(host, tokenIDOffset, tokenIDLen, valueOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return DCDTNFTAddQuantityWithTypedArgs(host, tokenID, uint64(nonce), value)
+}
+
+// DCDTNFTAddQuantityWithTypedArgs adds the given quantity to an existing NFT held by
+// the current contract, which must hold the NFT add quantity role
+func DCDTNFTAddQuantityWithTypedArgs(host vmhost.VMHost, tokenID []byte, nonce uint64, value *big.Int) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtNFTAddQuantity") {
+		return 1
+	}
+
+	arguments := [][]byte{tokenID, big.NewInt(0).SetUint64(nonce).Bytes(), value.Bytes()}
+	_, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTNFTAddQuantity, arguments)
+	if vmhost.WithFaultAndHost(host, err, host.Runtime().BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return 0
+}
+
+//export v1_3_dcdtNFTBurn
+func v1_3_dcdtNFTBurn(context unsafe.Pointer, tokenIDOffset int32, tokenIDLen int32, nonce int64, valueOffset int32) int32 {
+	if vmhost.FailIfStateChange(context, "dcdtNFTBurn") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	tokenID, value, err := loadTokenIDAndValue(host, tokenIDOffset, tokenIDLen, valueOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return DCDTNFTBurnWithTypedArgs(host, tokenID, uint64(nonce), value)
+}
+
+// DCDTNFTBurnWithTypedArgs burns the given quantity of an NFT held by the
+// current contract, which must hold the NFT burn role
+func DCDTNFTBurnWithTypedArgs(host vmhost.VMHost, tokenID []byte, nonce uint64, value *big.Int) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtNFTBurn") {
+		return 1
+	}
+
+	arguments := [][]byte{tokenID, big.NewInt(0).SetUint64(nonce).Bytes(), value.Bytes()}
+	_, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTNFTBurn, arguments)
+	if vmhost.WithFaultAndHost(host, err, host.Runtime().BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return 0
+}
+
+//export v1_3_dcdtNFTUpdateAttributes
+func v1_3_dcdtNFTUpdateAttributes(
+	context unsafe.Pointer,
+	tokenIDOffset int32,
+	tokenIDLen int32,
+	nonce int64,
+	attributesOffset int32,
+	attributesLength int32,
+) int32 {
+	if vmhost.FailIfStateChange(context, "dcdtNFTUpdateAttributes") {
+		return 1
+	}
+
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	tokenID, err := runtime.MemLoad(tokenIDOffset, tokenIDLen)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	attributes, err := runtime.MemLoad(attributesOffset, attributesLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	gasToUse := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, uint64(attributesLength))
+	metering.UseGas(gasToUse)
+
+	return DCDTNFTUpdateAttributesWithTypedArgs(host, tokenID, uint64(nonce), attributes)
+}
+
+// DCDTNFTUpdateAttributesWithTypedArgs replaces the attributes of an NFT held by the
+// current contract, which must hold the NFT update attributes role
+func DCDTNFTUpdateAttributesWithTypedArgs(host vmhost.VMHost, tokenID []byte, nonce uint64, attributes []byte) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "dcdtNFTUpdateAttributes") {
+		return 1
+	}
+
+	arguments := [][]byte{tokenID, big.NewInt(0).SetUint64(nonce).Bytes(), attributes}
+	_, err := executeDCDTLocalBuiltinFunction(host, core.BuiltInFunctionDCDTNFTUpdateAttributes, arguments)
+	if vmhost.WithFaultAndHost(host, err, host.Runtime().BaseOpsErrorShouldFailExecution()) {
+		return 1
+	}
+
+	return 0
+}
+
+//export v1_3_getDCDTLocalRoles
+func v1_3_getDCDTLocalRoles(context unsafe.Pointer, tokenIDOffset int32, tokenIDLen int32) int64 {
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	tokenID, err := runtime.MemLoad(tokenIDOffset, tokenIDLen)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return GetDCDTLocalRolesWithTypedArgs(host, tokenID)
+}
+
+// GetDCDTLocalRolesWithTypedArgs returns the DCDT roles held by the current contract
+// for the given token, as a mask of vmhost.DCDTLocalRole bits
+func GetDCDTLocalRolesWithTypedArgs(host vmhost.VMHost, tokenID []byte) int64 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getDCDTLocalRoles") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+	storage := host.Storage()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.StorageLoad
+	metering.UseGas(gasToUse)
+
+	key := []byte(core.ProtectedKeyPrefix + core.DCDTRoleIdentifier + core.DCDTKeyIdentifier + string(tokenID))
+	data := storage.GetStorage(key)
+	if len(data) == 0 {
+		return 0
+	}
+
+	dcdtRoles := &dcdt.DCDTRoles{}
+	err := dcdtRoles.Unmarshal(data)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	roles := int64(0)
+	for _, role := range dcdtRoles.Roles {
+		roles |= int64(dcdtLocalRoles[string(role)])
+	}
+
+	return roles
+}
+
+var dcdtLocalRoles = map[string]vmhost.DCDTLocalRole{
+	core.DCDTRoleLocalMint:           vmhost.RoleMint,
+	core.DCDTRoleLocalBurn:           vmhost.RoleBurn,
+	core.DCDTRoleNFTCreate:           vmhost.RoleNFTCreate,
+	core.DCDTRoleNFTAddQuantity:      vmhost.RoleNFTAddQuantity,
+	core.DCDTRoleNFTBurn:             vmhost.RoleNFTBurn,
+	core.DCDTRoleNFTAddURI:           vmhost.RoleNFTAddURI,
+	core.DCDTRoleNFTUpdateAttributes: vmhost.RoleNFTUpdateAttributes,
+	core.DCDTRoleTransfer:            vmhost.RoleTransfer,
+}
+
+func loadTokenIDAndValue(host vmhost.VMHost, tokenIDOffset int32, tokenIDLen int32, valueOffset int32) ([]byte, *big.Int, error) {
+	runtime := host.Runtime()
+
+	tokenID, err := runtime.MemLoad(tokenIDOffset, tokenIDLen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	valueBytes, err := runtime.MemLoad(valueOffset, vmhost.BalanceLen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tokenID, big.NewInt(0).SetBytes(valueBytes), nil
+}
+
+// executeDCDTLocalBuiltinFunction calls a DCDT builtin function on the tokens of the
+// current contract, consumes the gas it used and merges its output into the current one
+func executeDCDTLocalBuiltinFunction(host vmhost.VMHost, function string, arguments [][]byte) (*vmcommon.VMOutput, error) {
+	runtime := host.Runtime()
+	metering := host.Metering()
+	output := host.Output()
+
+	scAddress := runtime.GetSCAddress()
+	input := &vmcommon.ContractCallInput{
+		VMInput: vmcommon.VMInput{
+			CallerAddr:  scAddress,
+			Arguments:   arguments,
+			CallValue:   big.NewInt(0),
+			CallType:    vm.DirectCall,
+			GasPrice:    runtime.GetVMInput().GasPrice,
+			GasProvided: metering.GasLeft(),
+		},
+		RecipientAddr: scAddress,
+		Function:      function,
+	}
+
+	vmOutput, err := host.Blockchain().ProcessBuiltInFunction(input)
+	if err != nil {
+		metering.UseGas(input.GasProvided)
+		return nil, err
+	}
+	if vmOutput.ReturnCode != vmcommon.Ok {
+		metering.UseGas(input.GasProvided)
+		return nil, fmt.Errorf("%w: %s", vmhost.ErrExecutionFailed, vmOutput.ReturnMessage)
+	}
+
+	metering.TrackGasUsedByBuiltinFunction(input, vmOutput, nil)
+
+	// The results of the builtin function are returned by the hooks themselves,
+	// so they must not end up in the return data of the contract
+	outputToMerge := *vmOutput
+	outputToMerge.ReturnData = nil
+	output.AddToActiveState(&outputToMerge)
+
+	return vmOutput, nil
+}
+
 //export v1_3_createAsyncCall
 func v1_3_createAsyncCall(context unsafe.Pointer,
 	asyncContextIdentifier int32,
@@ -1279,41 +1985,106 @@ func v1_3_createAsyncCall(context unsafe.Pointer,
 	errorLength int32,
 	gas int64,
 ) {
+	if vmhost.FailIfStateChange(context, "createAsyncCall") {
+		return
+	}
+
 	host := vmhost.GetVMHost(context)
+	CreateAsyncCallWithHost(
+		host,
+		asyncContextIdentifier,
+		identifierLength,
+		destOffset,
+		valueOffset,
+		dataOffset,
+		length,
+		successOffset,
+		successLength,
+		errorOffset,
+		errorLength,
+		gas,
+	)
+}
+
+// CreateAsyncCallWithHost - createAsyncCall with host instead of pointer context
+func CreateAsyncCallWithHost(host vmhost.VMHost,
+	asyncContextIdentifier int32,
+	identifierLength int32,
+	destOffset int32,
+	valueOffset int32,
+	dataOffset int32,
+	length int32,
+	successOffset int32,
+	successLength int32,
+	errorOffset int32,
+	errorLength int32,
+	gas int64,
+) {
 	runtime := host.Runtime()
+	metering := host.Metering()
+	isMetered := host.IsAsyncContextMeteringEnabled()
+
+	gasSchedule := metering.GasSchedule()
+	if isMetered {
+		gasToUse := gasSchedule.AsyncContextCost.CreateAsyncCall
+		metering.UseGas(gasToUse)
 
-	// TODO consume gas
+		if gas < 0 {
+			_ = vmhost.WithFaultAndHost(host, vmhost.ErrInvalidAsyncCallGas, runtime.BaseOpsErrorShouldFailExecution())
+			return
+		}
+	}
 
 	acIdentifier, err := runtime.MemLoad(asyncContextIdentifier, identifierLength)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
 	calledSCAddress, err := runtime.MemLoad(destOffset, vmhost.AddressLen)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
 	value, err := runtime.MemLoad(valueOffset, vmhost.BalanceLen)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
 	data, err := runtime.MemLoad(dataOffset, length)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
 	successFunc, err := runtime.MemLoad(successOffset, successLength)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
 	errorFunc, err := runtime.MemLoad(errorOffset, errorLength)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 
+	gasToLock := uint64(0)
+	if isMetered {
+		// the identifier, the data and the callback names are all kept in the async context
+		storedLength := len(acIdentifier) + len(data) + len(successFunc) + len(errorFunc)
+		gasToUse := math.MulUint64(gasSchedule.BaseOperationCost.DataCopyPerByte, uint64(storedLength))
+		metering.UseGas(gasToUse)
+
+		// the callback is executed on the caller instance, so its gas is set aside now
+		gasToLock = metering.ComputeGasLockedForAsync()
+		err = metering.UseGasBounded(gasToLock)
+		if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+			return
+		}
+
+		if uint64(gas) > metering.GasLeft() {
+			_ = vmhost.WithFaultAndHost(host, vmhost.ErrNotEnoughGas, runtime.BaseOpsErrorShouldFailExecution())
+			return
+		}
+	}
+
 	err = runtime.AddAsyncContextCall(acIdentifier, &vmhost.AsyncGeneratedCall{
 		Destination:     calledSCAddress,
 		Data:            data,
@@ -1321,8 +2092,9 @@ func v1_3_createAsyncCall(context unsafe.Pointer,
 		SuccessCallback: string(successFunc),
 		ErrorCallback:   string(errorFunc),
 		ProvidedGas:     uint64(gas),
+		GasLocked:       gasToLock,
 	})
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return
 	}
 }
@@ -1335,25 +2107,52 @@ func v1_3_setAsyncContextCallback(context unsafe.Pointer,
 	callbackLength int32,
 ) int32 {
 	host := vmhost.GetVMHost(context)
+	return SetAsyncContextCallbackWithHost(
+		host,
+		asyncContextIdentifier,
+		identifierLength,
+		callback,
+		callbackLength,
+	)
+}
+
+// SetAsyncContextCallbackWithHost - setAsyncContextCallback with host instead of pointer context
+func SetAsyncContextCallbackWithHost(host vmhost.VMHost,
+	asyncContextIdentifier int32,
+	identifierLength int32,
+	callback int32,
+	callbackLength int32,
+) int32 {
 	runtime := host.Runtime()
+	metering := host.Metering()
+	isMetered := host.IsAsyncContextMeteringEnabled()
 
-	// TODO consume gas
+	gasSchedule := metering.GasSchedule()
+	if isMetered {
+		gasToUse := gasSchedule.AsyncContextCost.SetAsyncContextCallback
+		metering.UseGas(gasToUse)
+	}
 
 	acIdentifier, err := runtime.MemLoad(asyncContextIdentifier, identifierLength)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return -1
 	}
 
 	asyncContext, err := runtime.GetAsyncContext(acIdentifier)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return -1
 	}
 
 	callbackFunc, err := runtime.MemLoad(callback, callbackLength)
-	if vmhost.WithFault(err, context, runtime.BaseOpsErrorShouldFailExecution()) {
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
 		return -1
 	}
 
+	if isMetered {
+		gasToUse := math.MulUint64(gasSchedule.BaseOperationCost.DataCopyPerByte, uint64(len(callbackFunc)))
+		metering.UseGas(gasToUse)
+	}
+
 	asyncContext.Callback = string(callbackFunc)
 
 	return 0
@@ -1372,6 +2171,10 @@ func v1_3_upgradeContract(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) {
+	if vmhost.FailIfStateChangeInQuery(context, "upgradeContract") {
+		return
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -1432,6 +2235,10 @@ func v1_3_upgradeFromSourceContract(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) {
+	if vmhost.FailIfStateChangeInQuery(context, "upgradeFromSourceContract") {
+		return
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -1543,6 +2350,10 @@ func upgradeContract(
 
 //export v1_3_asyncCall
 func v1_3_asyncCall(context unsafe.Pointer, destOffset int32, valueOffset int32, dataOffset int32, length int32) {
+	if vmhost.FailIfStateChange(context, "asyncCall") {
+		return
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -1647,6 +2458,10 @@ func v1_3_getNumArguments(context unsafe.Pointer) int32 {
 
 //export v1_3_storageStore
 func v1_3_storageStore(context unsafe.Pointer, keyOffset int32, keyLength int32, dataOffset int32, dataLength int32) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "storageStore") {
+		return 1
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	storage := vmhost.GetStorageContext(context)
 	metering := vmhost.GetMeteringContext(context)
@@ -1744,8 +2559,130 @@ func v1_3_storageLoad(context unsafe.Pointer, keyOffset int32, keyLength int32,
 	return int32(len(data))
 }
 
+//export v1_3_storageIterate
+func v1_3_storageIterate(
+	context unsafe.Pointer,
+	prefixOffset int32,
+	prefixLength int32,
+	cursorOffset int32,
+	cursorLength int32,
+	limit int32,
+	lengthsOffset int32,
+	dataOffset int32,
+) int32 {
+	host := vmhost.GetVMHost(context)
+	runtime := host.Runtime()
+
+	prefix, err := runtime.MemLoad(prefixOffset, prefixLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	cursor, err := runtime.MemLoad(cursorOffset, cursorLength)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	entries, ok := StorageIterateWithTypedArgs(host, prefix, cursor, limit)
+	if !ok {
+		return -1
+	}
+
+	lengths := make([]byte, 0, len(entries)*8)
+	data := make([]byte, 0)
+	for _, entry := range entries {
+		lengths = binary.LittleEndian.AppendUint32(lengths, uint32(len(entry.Key)))
+		lengths = binary.LittleEndian.AppendUint32(lengths, uint32(len(entry.Value)))
+		data = append(data, entry.Key...)
+		data = append(data, entry.Value...)
+	}
+
+	err = runtime.MemStore(lengthsOffset, lengths)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	err = runtime.MemStore(dataOffset, data)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return int32(len(entries))
+}
+
+// StorageIterateWithTypedArgs returns at most limit storage entries of the current contract whose
+// keys start with the given prefix and come strictly after the given cursor, sorted by key, and
+// whether the iteration succeeded. The last key returned is the cursor of the next page. Only
+// allowed in queries and read-only calls. The storageIterate hook writes the lengths of the keys
+// and of the values, as little endian int32 pairs, at lengthsOffset, and the keys followed by
+// their values at dataOffset.
+func StorageIterateWithTypedArgs(host vmhost.VMHost, prefix []byte, cursor []byte, limit int32) ([]*vmhost.StorageEntry, bool) {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "storageIterate") {
+		return nil, false
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+	storage := host.Storage()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.StorageLoad
+	metering.UseGas(gasToUse)
+
+	entries, err := storage.IterateStorage(prefix, cursor, int(limit))
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return nil, false
+	}
+
+	gasToUse = 0
+	for _, entry := range entries {
+		entryLength := uint64(len(entry.Key) + len(entry.Value))
+		gasForEntry := math.MulUint64(metering.GasSchedule().BaseOperationCost.DataCopyPerByte, entryLength)
+		gasForEntry = math.AddUint64(gasForEntry, metering.GasSchedule().BaseOpsAPICost.StorageLoad)
+		gasToUse = math.AddUint64(gasToUse, gasForEntry)
+	}
+
+	err = metering.UseGasBounded(gasToUse)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return nil, false
+	}
+
+	return entries, true
+}
+
+//export v1_3_getStorageSize
+func v1_3_getStorageSize(context unsafe.Pointer) int64 {
+	host := vmhost.GetVMHost(context)
+	return GetStorageSizeWithHost(host)
+}
+
+// GetStorageSizeWithHost returns the number of bytes stored by the current contract,
+// counting the length of each key holding a value plus the length of the value
+func GetStorageSizeWithHost(host vmhost.VMHost) int64 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getStorageSize") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+	storage := host.Storage()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.StorageLoad
+	metering.UseGas(gasToUse)
+
+	size, err := storage.GetStorageSize()
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return int64(size)
+}
+
 //export v1_3_setStorageLock
 func v1_3_setStorageLock(context unsafe.Pointer, keyOffset int32, keyLength int32, lockTimestamp int64) int32 {
+	if vmhost.FailIfStateChange(context, "setStorageLock") {
+		return 1
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	storage := vmhost.GetStorageContext(context)
 	metering := vmhost.GetMeteringContext(context)
@@ -1805,6 +2742,10 @@ func v1_3_isStorageLocked(context unsafe.Pointer, keyOffset int32, keyLength int
 
 //export v1_3_clearStorageLock
 func v1_3_clearStorageLock(context unsafe.Pointer, keyOffset int32, keyLength int32) int32 {
+	if vmhost.FailIfStateChange(context, "clearStorageLock") {
+		return 1
+	}
+
 	return v1_3_setStorageLock(context, keyOffset, keyLength, 0)
 }
 
@@ -1909,6 +2850,10 @@ func v1_3_getDCDTTokenName(context unsafe.Pointer, resultOffset int32) int32 {
 
 //export v1_3_getDCDTTokenNonce
 func v1_3_getDCDTTokenNonce(context unsafe.Pointer) int64 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTTokenNonce") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	metering := vmhost.GetMeteringContext(context)
 
@@ -1921,6 +2866,10 @@ func v1_3_getDCDTTokenNonce(context unsafe.Pointer) int64 {
 
 //export v1_3_getCurrentDCDTNFTNonce
 func v1_3_getCurrentDCDTNFTNonce(context unsafe.Pointer, addressOffset int32, tokenIDOffset int32, tokenIDLen int32) int64 {
+	if vmhost.FailIfImportNotEnabled(context, "getCurrentDCDTNFTNonce") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	metering := vmhost.GetMeteringContext(context)
 	storage := vmhost.GetStorageContext(context)
@@ -1947,6 +2896,10 @@ func v1_3_getCurrentDCDTNFTNonce(context unsafe.Pointer, addressOffset int32, to
 
 //export v1_3_getDCDTTokenType
 func v1_3_getDCDTTokenType(context unsafe.Pointer) int32 {
+	if vmhost.FailIfImportNotEnabled(context, "getDCDTTokenType") {
+		return 0
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	metering := vmhost.GetMeteringContext(context)
 
@@ -1957,6 +2910,122 @@ func v1_3_getDCDTTokenType(context unsafe.Pointer) int32 {
 	return int32(dcdtTransfer.DCDTTokenType)
 }
 
+//export v1_3_getNumDCDTTransfers
+func v1_3_getNumDCDTTransfers(context unsafe.Pointer) int32 {
+	host := vmhost.GetVMHost(context)
+	return GetNumDCDTTransfersWithHost(host)
+}
+
+// GetNumDCDTTransfersWithHost returns the number of tokens received by the current call
+func GetNumDCDTTransfersWithHost(host vmhost.VMHost) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getNumDCDTTransfers") {
+		return 0
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.GetCallValue
+	metering.UseGas(gasToUse)
+
+	return int32(len(runtime.GetVMInput().DCDTTransfers))
+}
+
+//export v1_3_getDCDTValueByIndex
+func v1_3_getDCDTValueByIndex(context unsafe.Pointer, resultOffset int32, index int32) int32 {
+	host := vmhost.GetVMHost(context)
+	return GetDCDTValueByIndexWithHost(host, resultOffset, index)
+}
+
+// GetDCDTValueByIndexWithHost writes the value of the token received at the given index into memory
+func GetDCDTValueByIndexWithHost(host vmhost.VMHost, resultOffset int32, index int32) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getDCDTValueByIndex") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.GetCallValue
+	metering.UseGas(gasToUse)
+
+	dcdtTransfer, err := getDCDTTransferFromInput(runtime.GetVMInput(), index)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	var value []byte
+	if dcdtTransfer.DCDTValue.Cmp(vmhost.Zero) > 0 {
+		value = dcdtTransfer.DCDTValue.Bytes()
+		value = vmhost.PadBytesLeft(value, vmhost.BalanceLen)
+	}
+
+	err = runtime.MemStore(resultOffset, value)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return int32(len(value))
+}
+
+//export v1_3_getDCDTTokenNameByIndex
+func v1_3_getDCDTTokenNameByIndex(context unsafe.Pointer, resultOffset int32, index int32) int32 {
+	host := vmhost.GetVMHost(context)
+	return GetDCDTTokenNameByIndexWithHost(host, resultOffset, index)
+}
+
+// GetDCDTTokenNameByIndexWithHost writes the identifier of the token received at the given index into memory
+func GetDCDTTokenNameByIndexWithHost(host vmhost.VMHost, resultOffset int32, index int32) int32 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getDCDTTokenNameByIndex") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.GetCallValue
+	metering.UseGas(gasToUse)
+
+	dcdtTransfer, err := getDCDTTransferFromInput(runtime.GetVMInput(), index)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	tokenName := dcdtTransfer.DCDTTokenName
+	err = runtime.MemStore(resultOffset, tokenName)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return int32(len(tokenName))
+}
+
+//export v1_3_getDCDTTokenNonceByIndex
+func v1_3_getDCDTTokenNonceByIndex(context unsafe.Pointer, index int32) int64 {
+	host := vmhost.GetVMHost(context)
+	return GetDCDTTokenNonceByIndexWithHost(host, index)
+}
+
+// GetDCDTTokenNonceByIndexWithHost returns the nonce of the token received at the given index
+func GetDCDTTokenNonceByIndexWithHost(host vmhost.VMHost, index int32) int64 {
+	if vmhost.FailIfImportNotEnabledWithHost(host, "getDCDTTokenNonceByIndex") {
+		return -1
+	}
+
+	runtime := host.Runtime()
+	metering := host.Metering()
+
+	gasToUse := metering.GasSchedule().BaseOpsAPICost.GetCallValue
+	metering.UseGas(gasToUse)
+
+	dcdtTransfer, err := getDCDTTransferFromInput(runtime.GetVMInput(), index)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	return int64(dcdtTransfer.DCDTTokenNonce)
+}
+
 //export v1_3_getCallValueTokenName
 func v1_3_getCallValueTokenName(context unsafe.Pointer, callValueOffset int32, tokenNameOffset int32) int32 {
 	runtime := vmhost.GetRuntimeContext(context)
@@ -2707,6 +3776,10 @@ func v1_3_createContract(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "createContract") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -2771,6 +3844,10 @@ func v1_3_deployFromSourceContract(
 	argumentsLengthOffset int32,
 	dataOffset int32,
 ) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "deployFromSourceContract") {
+		return 1
+	}
+
 	host := vmhost.GetVMHost(context)
 	runtime := host.Runtime()
 	metering := host.Metering()
@@ -304,6 +304,10 @@ func v1_3_bigIntGetSignedArgument(context unsafe.Pointer, id int32, destination
 
 //export v1_3_bigIntStorageStoreUnsigned
 func v1_3_bigIntStorageStoreUnsigned(context unsafe.Pointer, keyOffset int32, keyLength int32, source int32) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "bigIntStorageStoreUnsigned") {
+		return 1
+	}
+
 	bigInt := vmhost.GetBigIntContext(context)
 	runtime := vmhost.GetRuntimeContext(context)
 	storage := vmhost.GetStorageContext(context)
@@ -401,6 +405,10 @@ func v1_3_bigIntGetExternalBalance(context unsafe.Pointer, addressOffset int32,
 
 //export v1_3_bigIntGetDCDTExternalBalance
 func v1_3_bigIntGetDCDTExternalBalance(context unsafe.Pointer, addressOffset int32, tokenIDOffset int32, tokenIDLen int32, nonce int64, result int32) {
+	if vmhost.FailIfImportNotEnabled(context, "bigIntGetDCDTExternalBalance") {
+		return
+	}
+
 	bigInt := vmhost.GetBigIntContext(context)
 	runtime := vmhost.GetRuntimeContext(context)
 	metering := vmhost.GetMeteringContext(context)
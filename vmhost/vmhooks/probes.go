@@ -0,0 +1,55 @@
+package vmhooks
+
+// // Declare the function signatures (see [cgo](https://golang.org/cmd/cgo/)).
+//
+// #include <stdlib.h>
+// typedef int int32_t;
+//
+// extern void			v1_3_coverageProbe(void *context, int32_t probeID);
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer/wasmcov"
+)
+
+// ProbeImports populates imports with the function called by the coverage probes of instrumented contracts
+func ProbeImports(imports *wasmer.Imports) (*wasmer.Imports, error) {
+	imports = imports.Namespace(wasmcov.ImportModule)
+
+	imports, err := imports.Append(wasmcov.ImportName, v1_3_coverageProbe, C.v1_3_coverageProbe)
+	if err != nil {
+		return nil, err
+	}
+
+	return imports, nil
+}
+
+//export v1_3_coverageProbe
+func v1_3_coverageProbe(context unsafe.Pointer, probeID int32) {
+	host := vmhost.GetVMHost(context)
+	CoverageProbeWithHost(host, probeID)
+}
+
+// CoverageProbeWithHost reports a probe to the probe tracer of the host, if any. Contracts can only
+// import the probe while a tracer is set, see VMHost.IsImportEnabled, but instances instrumented
+// earlier keep running their probes after the tracer is removed.
+// The probe is not part of the contract, so the gas of its i32.const and call instructions,
+// already charged by Wasmer, is given back: the contract consumes the same gas as when it is not instrumented.
+func CoverageProbeWithHost(host vmhost.VMHost, probeID int32) {
+	tracer := host.ProbeTracer()
+	if tracer != nil {
+		tracer.TraceProbe(probeID)
+	}
+
+	runtime := host.Runtime()
+	opcodeCosts := host.Metering().GasSchedule().WASMOpcodeCost
+	probeCost := uint64(opcodeCosts.I32Const) + uint64(opcodeCosts.Call)
+	pointsUsed := runtime.GetPointsUsed()
+	if pointsUsed >= probeCost {
+		runtime.SetPointsUsed(pointsUsed - probeCost)
+	}
+}
@@ -174,6 +174,10 @@ func v1_3_smallIntFinishSigned(context unsafe.Pointer, value int64) {
 
 //export v1_3_smallIntStorageStoreUnsigned
 func v1_3_smallIntStorageStoreUnsigned(context unsafe.Pointer, keyOffset int32, keyLength int32, value int64) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "smallIntStorageStoreUnsigned") {
+		return 1
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	storage := vmhost.GetStorageContext(context)
 	metering := vmhost.GetMeteringContext(context)
@@ -197,6 +201,10 @@ func v1_3_smallIntStorageStoreUnsigned(context unsafe.Pointer, keyOffset int32,
 
 //export v1_3_smallIntStorageStoreSigned
 func v1_3_smallIntStorageStoreSigned(context unsafe.Pointer, keyOffset int32, keyLength int32, value int64) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "smallIntStorageStoreSigned") {
+		return 1
+	}
+
 	runtime := vmhost.GetRuntimeContext(context)
 	storage := vmhost.GetStorageContext(context)
 	metering := vmhost.GetMeteringContext(context)
@@ -280,6 +288,10 @@ func v1_3_int64finish(context unsafe.Pointer, value int64) {
 
 //export v1_3_int64storageStore
 func v1_3_int64storageStore(context unsafe.Pointer, keyOffset int32, keyLength int32, value int64) int32 {
+	if vmhost.FailIfStateChangeInQuery(context, "int64storageStore") {
+		return 1
+	}
+
 	// backwards compatibility
 	return v1_3_smallIntStorageStoreUnsigned(context, keyOffset, keyLength, value)
 }
@@ -2,3 +2,9 @@ package vmserver
 
 // DefaultGasPrice is the default gas price for debugging
 const DefaultGasPrice = 200000000000
+
+// DefaultEstimateGasLimit is the maximum gas limit considered when estimating gas, if none is requested
+const DefaultEstimateGasLimit = 10000000
+
+// EventSubscriptionBufferSize is the number of events kept for a subscriber that is not keeping up; further events are dropped
+const EventSubscriptionBufferSize = 1024
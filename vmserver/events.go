@@ -0,0 +1,252 @@
+package vmserver
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
+)
+
+// EventKindLog is the kind of events emitted for the logs of a transaction
+const EventKindLog = "log"
+
+// EventKindTransfer is the kind of events emitted for the transfers and direct calls of a transaction
+const EventKindTransfer = "transfer"
+
+// EventKindAsyncCall is the kind of events emitted for the async calls and callbacks of a transaction
+const EventKindAsyncCall = "asyncCall"
+
+// EventKindStorage is the kind of events emitted for the storage changes of a transaction
+const EventKindStorage = "storage"
+
+// ExecutionEvent is something that happened while executing a transaction against a world.
+// Only the field corresponding to the Kind is set, out of Log, Transfer and Storage.
+type ExecutionEvent struct {
+	Sequence    uint64
+	World       string
+	Transaction string
+	Kind        string
+	AddressHex  string
+	Identifier  string
+	Log         *LogEvent
+	Transfer    *TransferEvent
+	Storage     *scenarioexec.StorageChange
+}
+
+// LogEvent holds a log entry; it is also decoded if the request provided an ABI with its event
+type LogEvent struct {
+	TopicsHex []string
+	DataHex   []string
+	Decoded   *scenabi.DecodedEvent
+}
+
+// TransferEvent holds an output transfer, which can also be a call or an async call
+type TransferEvent struct {
+	ReceiverHex string
+	Value       string
+	DataHex     string
+	GasLimit    uint64
+	CallType    string
+}
+
+// EventFilter selects the events of a subscription. Empty fields match all events.
+// The address matches the account emitting the event, as well as the receiver of transfers.
+type EventFilter struct {
+	World      string
+	AddressHex string
+	Identifier string
+}
+
+func (filter EventFilter) matches(event *ExecutionEvent) bool {
+	if len(filter.World) > 0 && filter.World != event.World {
+		return false
+	}
+	if len(filter.Identifier) > 0 && filter.Identifier != event.Identifier {
+		return false
+	}
+	if len(filter.AddressHex) > 0 {
+		addressHex := strings.ToLower(filter.AddressHex)
+		isReceiver := event.Transfer != nil && event.Transfer.ReceiverHex == addressHex
+		if event.AddressHex != addressHex && !isReceiver {
+			return false
+		}
+	}
+	return true
+}
+
+// EventSubscription receives the events matching its filter, until it is unsubscribed
+type EventSubscription struct {
+	id     uint64
+	filter EventFilter
+	events chan *ExecutionEvent
+}
+
+// Events yields the channel on which the events are received; it is closed on unsubscribe
+func (subscription *EventSubscription) Events() <-chan *ExecutionEvent {
+	return subscription.events
+}
+
+// eventBroker dispatches the events to the subscriptions; slow subscribers lose events instead of blocking execution
+type eventBroker struct {
+	mutex         sync.Mutex
+	lastID        uint64
+	lastSequence  uint64
+	subscriptions map[uint64]*EventSubscription
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		subscriptions: make(map[uint64]*EventSubscription),
+	}
+}
+
+func (broker *eventBroker) subscribe(filter EventFilter) *EventSubscription {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.lastID++
+	subscription := &EventSubscription{
+		id:     broker.lastID,
+		filter: filter,
+		events: make(chan *ExecutionEvent, EventSubscriptionBufferSize),
+	}
+	broker.subscriptions[subscription.id] = subscription
+	return subscription
+}
+
+func (broker *eventBroker) unsubscribe(subscription *EventSubscription) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	_, isSubscribed := broker.subscriptions[subscription.id]
+	if !isSubscribed {
+		return
+	}
+	delete(broker.subscriptions, subscription.id)
+	close(subscription.events)
+}
+
+func (broker *eventBroker) publish(events []*ExecutionEvent) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	for _, event := range events {
+		broker.lastSequence++
+		event.Sequence = broker.lastSequence
+
+		for _, subscription := range broker.subscriptions {
+			if !subscription.filter.matches(event) {
+				continue
+			}
+			select {
+			case subscription.events <- event:
+			default:
+				log.Warn("eventBroker.publish: subscription is full, event dropped", "sequence", event.Sequence)
+			}
+		}
+	}
+}
+
+// createExecutionEvents lists the events of a transaction: logs, then transfers and async calls, then storage changes
+func createExecutionEvents(
+	worldID string,
+	transaction string,
+	response *ContractResponseBase,
+	storageDiff scenarioexec.StorageDiffReport,
+) []*ExecutionEvent {
+	if response.Error != nil || response.Output == nil {
+		return nil
+	}
+
+	newEvent := func(kind string, address []byte, identifier string) *ExecutionEvent {
+		return &ExecutionEvent{
+			World:       worldID,
+			Transaction: transaction,
+			Kind:        kind,
+			AddressHex:  toHex(address),
+			Identifier:  identifier,
+		}
+	}
+
+	events := make([]*ExecutionEvent, 0)
+	decodedLogs := response.DecodedLogs
+	for _, logEntry := range response.Output.Logs {
+		event := newEvent(EventKindLog, logEntry.Address, string(logEntry.Identifier))
+		event.Log = &LogEvent{
+			TopicsHex: toHexList(logEntry.Topics),
+			DataHex:   toHexList(logEntry.Data),
+		}
+		// decoded logs only contain the logs of known events, in the same order as the output logs
+		if len(decodedLogs) > 0 && decodedLogs[0].Identifier == event.Identifier {
+			event.Log.Decoded = decodedLogs[0]
+			decodedLogs = decodedLogs[1:]
+		}
+		events = append(events, event)
+	}
+
+	for _, outputAccount := range sortedOutputAccounts(response.Output) {
+		for _, transfer := range outputAccount.OutputTransfers {
+			kind := EventKindTransfer
+			if transfer.CallType == vm.AsynchronousCall || transfer.CallType == vm.AsynchronousCallBack {
+				kind = EventKindAsyncCall
+			}
+			event := newEvent(kind, transfer.SenderAddress, calledFunction(transfer.Data))
+			event.Transfer = &TransferEvent{
+				ReceiverHex: toHex(outputAccount.Address),
+				Value:       "0",
+				DataHex:     toHex(transfer.Data),
+				GasLimit:    transfer.GasLimit,
+				CallType:    transfer.CallType.ToString(),
+			}
+			if transfer.Value != nil {
+				event.Transfer.Value = transfer.Value.String()
+			}
+			events = append(events, event)
+		}
+	}
+
+	for _, contractDiff := range storageDiff {
+		for _, change := range contractDiff.Changes {
+			event := newEvent(EventKindStorage, nil, change.Key)
+			event.AddressHex = contractDiff.AddressHex
+			event.Storage = change
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func sortedOutputAccounts(output *vmcommon.VMOutput) []*vmcommon.OutputAccount {
+	outputAccounts := make([]*vmcommon.OutputAccount, 0, len(output.OutputAccounts))
+	for _, outputAccount := range output.OutputAccounts {
+		outputAccounts = append(outputAccounts, outputAccount)
+	}
+	sort.Slice(outputAccounts, func(i, j int) bool {
+		return bytes.Compare(outputAccounts[i].Address, outputAccounts[j].Address) < 0
+	})
+	return outputAccounts
+}
+
+// calls are encoded as "function@arg1@arg2..."
+func calledFunction(data []byte) string {
+	function := data
+	separator := bytes.IndexByte(data, '@')
+	if separator >= 0 {
+		function = data[:separator]
+	}
+	return string(function)
+}
+
+func toHexList(values [][]byte) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = toHex(value)
+	}
+	return result
+}
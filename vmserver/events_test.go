@@ -0,0 +1,81 @@
+package vmserver
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kalyan3104/k-chain-core-go/data/vm"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CreateExecutionEvents(t *testing.T) {
+	contract := []byte("contract")
+	receiver := []byte("receiver")
+	response := &ContractResponseBase{
+		Output: &vmcommon.VMOutput{
+			Logs: []*vmcommon.LogEntry{
+				{Identifier: []byte("transfer"), Address: contract, Topics: [][]byte{{1}}},
+			},
+			OutputAccounts: map[string]*vmcommon.OutputAccount{
+				string(receiver): {
+					Address: receiver,
+					OutputTransfers: []vmcommon.OutputTransfer{
+						{Value: big.NewInt(5), SenderAddress: contract, CallType: vm.DirectCall},
+						{Data: []byte("callMe@01"), SenderAddress: contract, CallType: vm.AsynchronousCall},
+					},
+				},
+			},
+		},
+	}
+
+	events := createExecutionEvents("w", "run", response, nil)
+	require.Len(t, events, 3)
+
+	require.Equal(t, EventKindLog, events[0].Kind)
+	require.Equal(t, toHex(contract), events[0].AddressHex)
+	require.Equal(t, "transfer", events[0].Identifier)
+	require.Equal(t, []string{"01"}, events[0].Log.TopicsHex)
+
+	require.Equal(t, EventKindTransfer, events[1].Kind)
+	require.Equal(t, toHex(receiver), events[1].Transfer.ReceiverHex)
+	require.Equal(t, "5", events[1].Transfer.Value)
+
+	require.Equal(t, EventKindAsyncCall, events[2].Kind)
+	require.Equal(t, "callMe", events[2].Identifier)
+	require.Equal(t, "0", events[2].Transfer.Value)
+}
+
+func Test_EventFilter(t *testing.T) {
+	event := &ExecutionEvent{
+		World:      "w",
+		AddressHex: "aa",
+		Identifier: "transfer",
+		Transfer:   &TransferEvent{ReceiverHex: "bb"},
+	}
+
+	require.True(t, EventFilter{}.matches(event))
+	require.True(t, EventFilter{World: "w", AddressHex: "AA", Identifier: "transfer"}.matches(event))
+	require.True(t, EventFilter{AddressHex: "bb"}.matches(event))
+	require.False(t, EventFilter{World: "other"}.matches(event))
+	require.False(t, EventFilter{AddressHex: "cc"}.matches(event))
+	require.False(t, EventFilter{Identifier: "other"}.matches(event))
+}
+
+func Test_EventBroker(t *testing.T) {
+	broker := newEventBroker()
+	subscription := broker.subscribe(EventFilter{})
+
+	events := make([]*ExecutionEvent, EventSubscriptionBufferSize+1)
+	for i := range events {
+		events[i] = &ExecutionEvent{}
+	}
+	broker.publish(events)
+	require.Len(t, subscription.Events(), EventSubscriptionBufferSize)
+	require.Equal(t, uint64(1), (<-subscription.Events()).Sequence)
+
+	broker.unsubscribe(subscription)
+	broker.unsubscribe(subscription)
+	broker.publish([]*ExecutionEvent{{}})
+	require.Len(t, subscription.Events(), EventSubscriptionBufferSize-1)
+}
@@ -11,11 +11,24 @@ var log = logger.GetOrCreate("vmserver")
 
 // DebugFacade is the debug facade
 type DebugFacade struct {
+	events *eventBroker
 }
 
 // NewDebugFacade creates a new debug facade
 func NewDebugFacade() *DebugFacade {
-	return &DebugFacade{}
+	return &DebugFacade{
+		events: newEventBroker(),
+	}
+}
+
+// SubscribeEvents starts receiving the execution events matching the filter, for the transactions run from now on
+func (f *DebugFacade) SubscribeEvents(filter EventFilter) *EventSubscription {
+	return f.events.subscribe(filter)
+}
+
+// UnsubscribeEvents stops receiving execution events and closes the channel of the subscription
+func (f *DebugFacade) UnsubscribeEvents(subscription *EventSubscription) {
+	f.events.unsubscribe(subscription)
 }
 
 // DeploySmartContract deploys a smart contract
@@ -45,6 +58,8 @@ func (f *DebugFacade) DeploySmartContract(request DeployRequest) (*DeployRespons
 		return nil, err
 	}
 
+	f.events.publish(createExecutionEvents(request.World, "deploy", &response.ContractResponseBase, response.StorageDiff))
+
 	dumpOutcome(&response)
 	return response, err
 }
@@ -81,6 +96,8 @@ func (f *DebugFacade) UpgradeSmartContract(request UpgradeRequest) (*UpgradeResp
 		return nil, err
 	}
 
+	f.events.publish(createExecutionEvents(request.World, "upgrade", &response.ContractResponseBase, response.StorageDiff))
+
 	dumpOutcome(&response)
 	return response, err
 }
@@ -112,6 +129,8 @@ func (f *DebugFacade) RunSmartContract(request RunRequest) (*RunResponse, error)
 		return nil, err
 	}
 
+	f.events.publish(createExecutionEvents(request.World, "run", &response.ContractResponseBase, response.StorageDiff))
+
 	dumpOutcome(&response)
 	return response, err
 }
@@ -142,6 +161,60 @@ func (f *DebugFacade) QuerySmartContract(request QueryRequest) (*QueryResponse,
 	return response, err
 }
 
+// SimulateSmartContract runs a smart contract function against the world with the requested
+// state overrides, without changing the world
+func (f *DebugFacade) SimulateSmartContract(request SimulateRequest) (*SimulateResponse, error) {
+	log.Debug("Debugf.SimulateSmartContract()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	database := f.loadDatabase(request.DatabasePath)
+	world, err := database.loadWorld(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	response := world.simulateSmartContract(request)
+
+	err = database.storeOutcome(request.Outcome, response)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, err
+}
+
+// EstimateGas finds the minimum and the recommended gas limits of a smart contract function call,
+// without changing the world
+func (f *DebugFacade) EstimateGas(request EstimateRequest) (*EstimateResponse, error) {
+	log.Debug("Debugf.EstimateGas()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	database := f.loadDatabase(request.DatabasePath)
+	world, err := database.loadWorld(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	response := world.estimateGas(request)
+
+	err = database.storeOutcome(request.Outcome, response)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, err
+}
+
 // CreateAccount creates a test account
 func (f *DebugFacade) CreateAccount(request CreateAccountRequest) (*CreateAccountResponse, error) {
 	log.Debug("Debugf.CreateAccount()")
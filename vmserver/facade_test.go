@@ -4,7 +4,10 @@ import (
 	"os"
 	"testing"
 
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	worldmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +48,190 @@ func TestFacade_RunContract_Counter(t *testing.T) {
 	require.Equal(t, []byte{2}, state["COUNTER"])
 }
 
+func TestFacade_RunContract_CounterStorageDiff(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+	require.Len(t, deployResponse.StorageDiff, 1)
+	require.Equal(t, []*scenarioexec.StorageChange{
+		{Key: "0x434f554e544552 (str:COUNTER)", OldValue: "", NewValue: "0x01 (1)", Status: "added"},
+	}, deployResponse.StorageDiff[0].Changes)
+
+	runResponse := context.runContract(deployResponse.ContractAddressHex, alice.hex, "increment")
+	require.Len(t, runResponse.StorageDiff, 1)
+	require.Equal(t, []*scenarioexec.StorageChange{
+		{Key: "0x434f554e544552 (str:COUNTER)", OldValue: "0x01 (1)", NewValue: "0x02 (2)", Status: "modified"},
+	}, runResponse.StorageDiff[0].Changes)
+}
+
+func TestFacade_RunContract_CounterWithAccessList(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+	contractAddressHex := deployResponse.ContractAddressHex
+
+	accessList := map[string][]string{
+		contractAddressHex: {toHex([]byte("COUNTER"))},
+	}
+	runResponse := context.runContractWithAccessList(contractAddressHex, alice.hex, accessList, "increment")
+	require.Len(t, runResponse.StorageDiff, 1)
+	require.Equal(t, []*scenarioexec.StorageChange{
+		{Key: "0x434f554e544552 (str:COUNTER)", OldValue: "0x01 (1)", NewValue: "0x02 (2)", Status: "modified"},
+	}, runResponse.StorageDiff[0].Changes)
+
+	counterValue := context.queryContract(contractAddressHex, alice.hex, "get").getFirstResultAsInt64()
+	require.Equal(t, int64(2), counterValue)
+}
+
+func TestFacade_RunContract_InvalidAccessList(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+
+	request := RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: deployResponse.ContractAddressHex,
+		Function:           "increment",
+		AccessListHex: map[string][]string{
+			deployResponse.ContractAddressHex: {"foo"},
+		},
+	}
+
+	_, err := context.facade.RunSmartContract(request)
+	require.NotNil(t, err)
+}
+
+func TestFacade_RunContract_InvalidABI(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+
+	request := RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+			ABI:             []byte(`{"events": [{"identifier": "e", "inputs": [{"name": "x", "type": "Unknown"}]}]}`),
+		},
+		ContractAddressHex: deployResponse.ContractAddressHex,
+		Function:           "increment",
+	}
+
+	_, err := context.facade.RunSmartContract(request)
+	require.NotNil(t, err)
+}
+
+func TestDecodeLogs_ErrorKeptApart(t *testing.T) {
+	abi, err := scenabi.ParseABI([]byte(`{"events": [{"identifier": "counted", "inputs": [{"name": "value", "type": "u8"}]}]}`))
+	require.Nil(t, err)
+
+	vmOutput := &vmcommon.VMOutput{
+		Logs: []*vmcommon.LogEntry{
+			{Identifier: []byte("counted"), Data: [][]byte{{7}}},
+		},
+	}
+	decodedLogs, decodeError := decodeLogs(abi, vmOutput)
+	require.Empty(t, decodeError)
+	require.Len(t, decodedLogs, 1)
+	require.Equal(t, "7", decodedLogs[0].Field("value").Value)
+
+	vmOutput.Logs[0].Data = [][]byte{{1, 2}}
+	decodedLogs, decodeError = decodeLogs(abi, vmOutput)
+	require.Nil(t, decodedLogs)
+	require.NotEmpty(t, decodeError)
+
+	decodedLogs, decodeError = decodeLogs(nil, vmOutput)
+	require.Nil(t, decodedLogs)
+	require.Empty(t, decodeError)
+}
+
+func TestFacade_RunContract_CounterEvents(t *testing.T) {
+	context := newTestContext(t)
+	subscription := context.facade.SubscribeEvents(EventFilter{World: context.worldID})
+	otherSubscription := context.facade.SubscribeEvents(EventFilter{World: "other"})
+	defer context.facade.UnsubscribeEvents(subscription)
+	defer context.facade.UnsubscribeEvents(otherSubscription)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+	context.runContract(deployResponse.ContractAddressHex, alice.hex, "increment")
+
+	require.Len(t, subscription.Events(), 2)
+	require.Len(t, otherSubscription.Events(), 0)
+
+	deployEvent := <-subscription.Events()
+	require.Equal(t, "deploy", deployEvent.Transaction)
+	require.Equal(t, EventKindStorage, deployEvent.Kind)
+	require.Equal(t, deployResponse.ContractAddressHex, deployEvent.AddressHex)
+	require.Equal(t, "added", deployEvent.Storage.Status)
+
+	runEvent := <-subscription.Events()
+	require.Equal(t, "run", runEvent.Transaction)
+	require.Equal(t, "0x434f554e544552 (str:COUNTER)", runEvent.Identifier)
+	require.Equal(t, "0x02 (2)", runEvent.Storage.NewValue)
+	require.Greater(t, runEvent.Sequence, deployEvent.Sequence)
+}
+
+func TestFacade_SimulateContract_CounterWithStorageOverride(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+	contractAddress := deployResponse.ContractAddress
+	contractAddressHex := deployResponse.ContractAddressHex
+
+	overrides := StateOverrides{
+		AccountOverrides: map[string]AccountOverride{
+			contractAddressHex: {
+				StorageHex: map[string]string{toHex([]byte("COUNTER")): "05"},
+			},
+		},
+	}
+
+	counterValue := context.simulateContract(contractAddressHex, alice.hex, overrides, "get").getFirstResultAsInt64()
+	require.Equal(t, int64(5), counterValue)
+
+	// The overrides are discarded once the simulation ends
+	counterValue = context.queryContract(contractAddressHex, alice.hex, "get").getFirstResultAsInt64()
+	require.Equal(t, int64(1), counterValue)
+
+	world := context.loadWorld()
+	state, err := world.blockchainHook.GetAllState([]byte(contractAddress))
+	require.Nil(t, err)
+	require.Equal(t, []byte{1}, state["COUNTER"])
+}
+
+func TestFacade_EstimateGas_Counter(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+	deployResponse := context.deployContract(wasmCounterPath, alice.hex)
+	contractAddressHex := deployResponse.ContractAddressHex
+
+	estimateResponse := context.estimateGas(contractAddressHex, alice.hex, "increment")
+	require.NotZero(t, estimateResponse.MinimumGasLimit)
+	require.GreaterOrEqual(t, estimateResponse.RecommendedGasLimit, estimateResponse.MinimumGasLimit)
+
+	// The estimation does not change the world
+	counterValue := context.queryContract(contractAddressHex, alice.hex, "get").getFirstResultAsInt64()
+	require.Equal(t, int64(1), counterValue)
+}
+
 func TestFacade_RunContract_ERC20(t *testing.T) {
 	context := newTestContext(t)
 
@@ -1,9 +1,11 @@
 package vmserver
 
 import (
+	"encoding/json"
 	"math/big"
 
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 )
 
 // RequestBase is a CLI / REST request message
@@ -39,6 +41,9 @@ type ContractRequestBase struct {
 	ValueAsBigInt   *big.Int
 	GasPrice        uint64
 	GasLimit        uint64
+	// ABI is the JSON ABI of the contract, given inline; the logs of its events are decoded
+	ABI         json.RawMessage
+	ContractABI *scenabi.ContractABI
 }
 
 func (request *ContractRequestBase) digest() error {
@@ -69,6 +74,13 @@ func (request *ContractRequestBase) digest() error {
 		return err
 	}
 
+	if len(request.ABI) > 0 {
+		request.ContractABI, err = scenabi.ParseABI(request.ABI)
+		if err != nil {
+			return NewRequestErrorMessageInner("invalid ABI", err)
+		}
+	}
+
 	return nil
 }
 
@@ -78,6 +90,10 @@ type ContractResponseBase struct {
 	Input            *vmcommon.VMInput
 	Output           *vmcommon.VMOutput
 	ReturnCodeString string
+	DecodedLogs      []*scenabi.DecodedEvent
+	// DecodeLogsError is set if the logs could not be decoded with the ABI of the request,
+	// while the execution itself may have succeeded
+	DecodeLogsError string
 }
 
 func createContractResponseBase(input *vmcommon.VMInput, output *vmcommon.VMOutput) ContractResponseBase {
@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
 )
 
 // DeployRequest is a CLI / REST request message
@@ -63,4 +64,5 @@ type DeployResponse struct {
 	ContractResponseBase
 	ContractAddress    []byte
 	ContractAddressHex string
+	StorageDiff        scenarioexec.StorageDiffReport
 }
@@ -0,0 +1,24 @@
+package vmserver
+
+// EstimateRequest is a CLI / REST request message
+type EstimateRequest struct {
+	RunRequest
+}
+
+func (request *EstimateRequest) digest() error {
+	if request.GasLimit == 0 {
+		request.GasLimit = DefaultEstimateGasLimit
+	}
+
+	return request.RunRequest.digest()
+}
+
+// EstimateResponse is a CLI / REST response message
+type EstimateResponse struct {
+	ContractResponseBase
+	GasUsed             uint64
+	GasLocked           uint64
+	MinimumGasLimit     uint64
+	RecommendedGasLimit uint64
+	Executions          int
+}
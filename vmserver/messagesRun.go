@@ -1,5 +1,10 @@
 package vmserver
 
+import (
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
 // RunRequest is a CLI / REST request message
 type RunRequest struct {
 	ContractRequestBase
@@ -8,6 +13,10 @@ type RunRequest struct {
 	Function           string
 	ArgumentsHex       []string
 	Arguments          [][]byte
+	// AccessListHex maps hex-encoded account addresses to the hex-encoded storage keys
+	// the call intends to access, which are charged at the warm cost
+	AccessListHex map[string][]string
+	AccessList    vmhost.StorageAccessList
 }
 
 func (request *RunRequest) digest() error {
@@ -26,10 +35,38 @@ func (request *RunRequest) digest() error {
 		return err
 	}
 
+	request.AccessList, err = decodeAccessList(request.AccessListHex)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+func decodeAccessList(accessListHex map[string][]string) (vmhost.StorageAccessList, error) {
+	accessList := make(vmhost.StorageAccessList, 0, len(accessListHex))
+	for addressHex, keysHex := range accessListHex {
+		address, err := fromHex(addressHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid access list address", err)
+		}
+
+		keys, err := decodeArguments(keysHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid access list key", err)
+		}
+
+		accessList = append(accessList, &vmhost.StorageAccessListEntry{
+			Address: address,
+			Keys:    keys,
+		})
+	}
+
+	return accessList, nil
+}
+
 // RunResponse is a CLI / REST response message
 type RunResponse struct {
 	ContractResponseBase
+	StorageDiff scenarioexec.StorageDiffReport
 }
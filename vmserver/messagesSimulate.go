@@ -0,0 +1,164 @@
+package vmserver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+)
+
+// SimulateRequest is a CLI / REST request message
+type SimulateRequest struct {
+	RunRequest
+	StateOverrides
+	StateOverridesPath string
+	Overrides          *vmhost.StateOverrides
+}
+
+// StateOverrides is the hypothetical state of a SimulateRequest, keyed by hex-encoded account address
+type StateOverrides struct {
+	AccountOverrides map[string]AccountOverride
+	BlockOverride    *BlockOverride
+}
+
+// AccountOverride replaces parts of the state of an account during a simulation
+type AccountOverride struct {
+	CodeHex      string
+	CodePath     string
+	Balance      string
+	StorageHex   map[string]string
+	DCDTBalances map[string]string
+}
+
+// BlockOverride replaces the current block information during a simulation
+type BlockOverride struct {
+	Nonce         *uint64
+	Round         *uint64
+	Timestamp     *uint64
+	Epoch         *uint32
+	RandomSeedHex string
+}
+
+func (request *SimulateRequest) digest() error {
+	err := request.RunRequest.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.StateOverridesPath) > 0 {
+		data, err := ioutil.ReadFile(request.StateOverridesPath)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(data, &request.StateOverrides)
+		if err != nil {
+			return NewRequestErrorMessageInner("invalid state overrides", err)
+		}
+	}
+
+	request.Overrides = &vmhost.StateOverrides{
+		Accounts: make(map[string]*vmhost.AccountOverride),
+	}
+
+	for addressHex, accountOverride := range request.AccountOverrides {
+		address, err := fromHex(addressHex)
+		if err != nil {
+			return NewRequestErrorMessageInner("invalid overridden account address", err)
+		}
+
+		digested, err := accountOverride.digest()
+		if err != nil {
+			return err
+		}
+
+		request.Overrides.Accounts[string(address)] = digested
+	}
+
+	if request.BlockOverride != nil {
+		request.Overrides.Block, err = request.BlockOverride.digest()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (override *AccountOverride) digest() (*vmhost.AccountOverride, error) {
+	digested := &vmhost.AccountOverride{
+		Storage:      make(map[string][]byte),
+		DCDTBalances: make(map[string]*big.Int),
+	}
+
+	var err error
+	if len(override.CodeHex) > 0 {
+		digested.Code, err = fromHex(override.CodeHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid overridden contract code", err)
+		}
+	}
+
+	if len(override.CodePath) > 0 {
+		digested.Code, err = ioutil.ReadFile(override.CodePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(override.Balance) > 0 {
+		digested.Balance, err = parseValue(override.Balance)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for keyHex, valueHex := range override.StorageHex {
+		key, err := fromHex(keyHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid overridden storage key", err)
+		}
+
+		value, err := fromHex(valueHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid overridden storage value", err)
+		}
+
+		digested.Storage[string(key)] = value
+	}
+
+	for tokenIdentifier, balance := range override.DCDTBalances {
+		digested.DCDTBalances[tokenIdentifier], err = parseValue(balance)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return digested, nil
+}
+
+func (override *BlockOverride) digest() (*vmhost.BlockOverride, error) {
+	digested := &vmhost.BlockOverride{
+		Nonce:     override.Nonce,
+		Round:     override.Round,
+		Timestamp: override.Timestamp,
+		Epoch:     override.Epoch,
+	}
+
+	if len(override.RandomSeedHex) > 0 {
+		randomSeed, err := fromHex(override.RandomSeedHex)
+		if err != nil {
+			return nil, NewRequestErrorMessageInner("invalid overridden random seed", err)
+		}
+
+		digested.RandomSeed = randomSeed
+	}
+
+	return digested, nil
+}
+
+// SimulateResponse is a CLI / REST response message
+type SimulateResponse struct {
+	ContractResponseBase
+}
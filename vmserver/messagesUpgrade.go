@@ -1,5 +1,9 @@
 package vmserver
 
+import (
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+)
+
 // UpgradeRequest is a CLI / REST request message
 type UpgradeRequest struct {
 	DeployRequest
@@ -24,4 +28,5 @@ func (request *UpgradeRequest) digest() error {
 // UpgradeResponse is a CLI / REST response message
 type UpgradeResponse struct {
 	ContractResponseBase
+	StorageDiff scenarioexec.StorageDiffReport
 }
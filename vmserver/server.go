@@ -2,6 +2,7 @@ package vmserver
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -32,6 +33,9 @@ func (server *DebugServer) Start() error {
 	router.POST("/upgrade", server.handleUpgrade)
 	router.POST("/run", server.handleRun)
 	router.POST("/query", server.handleQuery)
+	router.POST("/simulate", server.handleSimulate)
+	router.POST("/estimate", server.handleEstimate)
+	router.GET("/events", server.handleEvents)
 
 	return router.Run(server.address)
 }
@@ -126,6 +130,68 @@ func (server *DebugServer) handleQuery(ginContext *gin.Context) {
 	returnOkResponse(ginContext, response)
 }
 
+func (server *DebugServer) handleSimulate(ginContext *gin.Context) {
+	request := SimulateRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSimulate.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.SimulateSmartContract(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSimulate.SimulateSmartContract", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleEstimate(ginContext *gin.Context) {
+	request := EstimateRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleEstimate.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.EstimateGas(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleEstimate.EstimateGas", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+// handleEvents streams the execution events as server-sent events, until the client disconnects.
+// The events can be filtered by the "world", "address" (hex) and "identifier" query parameters.
+func (server *DebugServer) handleEvents(ginContext *gin.Context) {
+	filter := EventFilter{
+		World:      ginContext.Query("world"),
+		AddressHex: ginContext.Query("address"),
+		Identifier: ginContext.Query("identifier"),
+	}
+
+	subscription := server.facade.SubscribeEvents(filter)
+	defer server.facade.UnsubscribeEvents(subscription)
+
+	ginContext.Stream(func(writer io.Writer) bool {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return false
+			}
+			ginContext.SSEvent(event.Kind, event)
+			return true
+		case <-ginContext.Request.Context().Done():
+			return false
+		}
+	})
+}
+
 func returnBadRequest(context *gin.Context, errScope string, err error) {
 	context.JSON(http.StatusBadRequest, gin.H{
 		"error":        fmt.Sprintf("%T", err),
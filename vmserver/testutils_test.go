@@ -101,6 +101,10 @@ func (context *testContext) upgradeContract(contract string, codePath string, im
 }
 
 func (context *testContext) runContract(contract string, impersonated string, function string, arguments ...string) *RunResponse {
+	return context.runContractWithAccessList(contract, impersonated, nil, function, arguments...)
+}
+
+func (context *testContext) runContractWithAccessList(contract string, impersonated string, accessList map[string][]string, function string, arguments ...string) *RunResponse {
 	request := RunRequest{
 		ContractRequestBase: ContractRequestBase{
 			RequestBase:     context.createRequestBase(),
@@ -110,6 +114,7 @@ func (context *testContext) runContract(contract string, impersonated string, fu
 		ContractAddressHex: contract,
 		Function:           function,
 		ArgumentsHex:       arguments,
+		AccessListHex:      accessList,
 	}
 
 	response, err := context.facade.RunSmartContract(request)
@@ -150,6 +155,56 @@ func (context *testContext) queryContract(contract string, impersonated string,
 	return response
 }
 
+func (context *testContext) simulateContract(contract string, impersonated string, overrides StateOverrides, function string, arguments ...string) *SimulateResponse {
+	request := SimulateRequest{
+		RunRequest: RunRequest{
+			ContractRequestBase: ContractRequestBase{
+				RequestBase:     context.createRequestBase(),
+				ImpersonatedHex: impersonated,
+				GasLimit:        gasLimit,
+			},
+			ContractAddressHex: contract,
+			Function:           function,
+			ArgumentsHex:       arguments,
+		},
+		StateOverrides: overrides,
+	}
+
+	response, err := context.facade.SimulateSmartContract(request)
+
+	t := context.t
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.NotNil(t, response.Output)
+	require.Nil(t, response.Error)
+	require.Equal(t, vmcommon.Ok.String(), response.Output.ReturnCode.String(), response.Output.ReturnMessage)
+
+	return response
+}
+
+func (context *testContext) estimateGas(contract string, impersonated string, function string, arguments ...string) *EstimateResponse {
+	request := EstimateRequest{
+		RunRequest: RunRequest{
+			ContractRequestBase: ContractRequestBase{
+				RequestBase:     context.createRequestBase(),
+				ImpersonatedHex: impersonated,
+			},
+			ContractAddressHex: contract,
+			Function:           function,
+			ArgumentsHex:       arguments,
+		},
+	}
+
+	response, err := context.facade.EstimateGas(request)
+
+	t := context.t
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.Nil(t, response.Error)
+
+	return response
+}
+
 func (response *ContractResponseBase) getFirstResultAsInt64() int64 {
 	result, err := response.Output.GetFirstReturnData(vm.AsBigInt)
 	if err != nil {
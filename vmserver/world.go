@@ -3,12 +3,13 @@ package vmserver
 import (
 	"math/big"
 
-	"github.com/kalyan3104/k-chain-core-go/core"
 	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
 	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/config"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/mock"
 	worldmock "github.com/kalyan3104/k-chain-vm-v1_3-go/mock/world"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/scenarioexec"
+	scenabi "github.com/kalyan3104/k-chain-vm-v1_3-go/scenarios/abi"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
 	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
 )
@@ -21,7 +22,7 @@ type worldDataModel struct {
 type world struct {
 	id             string
 	blockchainHook *worldmock.MockWorld
-	vm             vmcommon.VMExecutionHandler
+	vm             vmhost.VMHost
 }
 
 func newWorldDataModel(worldID string) *worldDataModel {
@@ -59,9 +60,7 @@ func getHostParameters() *vmhost.VMHostParameters {
 		ProtectedKeyPrefix:   []byte("E" + "L" + "R" + "O" + "N" + "D"),
 		BuiltInFuncContainer: builtInFunctions.NewBuiltInFunctionContainer(),
 		EnableEpochsHandler: &mock.EnableEpochsHandlerStub{
-			IsFlagEnabledCalled: func(flag core.EnableEpochFlag) bool {
-				return flag == hostCore.SCDeployFlag || flag == hostCore.AheadOfTimeGasUsageFlag || flag == hostCore.RepairCallbackFlag || flag == hostCore.BuiltInFunctionsFlag
-			},
+			IsFlagEnabledCalled: hostCore.IsKnownFlag,
 		},
 	}
 }
@@ -71,13 +70,15 @@ func (w *world) deploySmartContract(request DeployRequest) *DeployResponse {
 	log.Trace("w.deploySmartContract()", "input", prettyJson(input))
 
 	vmOutput, err := w.vm.RunSmartContractCreate(input)
-	if err == nil {
-		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
-	}
 
 	response := &DeployResponse{}
 	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
 	response.Error = err
+	if err == nil {
+		response.StorageDiff = scenarioexec.NewStorageDiffReport(w.blockchainHook, vmOutput)
+		response.DecodedLogs, response.DecodeLogsError = decodeLogs(request.ContractABI, vmOutput)
+		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+	}
 	response.ContractAddress = w.blockchainHook.LastCreatedContractAddress
 	response.ContractAddressHex = toHex(response.ContractAddress)
 	return response
@@ -88,13 +89,15 @@ func (w *world) upgradeSmartContract(request UpgradeRequest) *UpgradeResponse {
 	log.Trace("w.upgradeSmartContract()", "input", prettyJson(input))
 
 	vmOutput, err := w.vm.RunSmartContractCall(input)
-	if err == nil {
-		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
-	}
 
 	response := &UpgradeResponse{}
 	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
 	response.Error = err
+	if err == nil {
+		response.StorageDiff = scenarioexec.NewStorageDiffReport(w.blockchainHook, vmOutput)
+		response.DecodedLogs, response.DecodeLogsError = decodeLogs(request.ContractABI, vmOutput)
+		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+	}
 
 	return response
 }
@@ -103,23 +106,48 @@ func (w *world) runSmartContract(request RunRequest) *RunResponse {
 	input := w.prepareCallInput(request)
 	log.Trace("w.runSmartContract()", "input", prettyJson(input))
 
-	vmOutput, err := w.vm.RunSmartContractCall(input)
-	if err == nil {
-		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+	var vmOutput *vmcommon.VMOutput
+	var err error
+	if len(request.AccessList) > 0 {
+		vmOutput, err = w.vm.RunSmartContractCallWithAccessList(input, request.AccessList)
+	} else {
+		vmOutput, err = w.vm.RunSmartContractCall(input)
 	}
 
 	response := &RunResponse{}
 	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
 	response.Error = err
+	if err == nil {
+		response.StorageDiff = scenarioexec.NewStorageDiffReport(w.blockchainHook, vmOutput)
+		response.DecodedLogs, response.DecodeLogsError = decodeLogs(request.ContractABI, vmOutput)
+		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+	}
 
 	return response
 }
 
+// decodeLogs decodes the output logs with the events of the given ABI, if any;
+// a decoding failure is reported separately from the outcome of the execution
+func decodeLogs(abi *scenabi.ContractABI, vmOutput *vmcommon.VMOutput) ([]*scenabi.DecodedEvent, string) {
+	if abi == nil {
+		return nil, ""
+	}
+
+	decoder := scenabi.NewEventDecoder()
+	decoder.RegisterABI(abi)
+	decodedLogs, err := decoder.DecodeLogs(vmOutput.Logs)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return decodedLogs, ""
+}
+
 func (w *world) querySmartContract(request QueryRequest) *QueryResponse {
 	input := w.prepareCallInput(request.RunRequest)
 	log.Trace("w.querySmartContract()", "input", prettyJson(input))
 
-	vmOutput, err := w.vm.RunSmartContractCall(input)
+	vmOutput, err := w.vm.RunSmartContractQuery(input)
 
 	response := &QueryResponse{}
 	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
@@ -128,6 +156,39 @@ func (w *world) querySmartContract(request QueryRequest) *QueryResponse {
 	return response
 }
 
+func (w *world) simulateSmartContract(request SimulateRequest) *SimulateResponse {
+	input := w.prepareCallInput(request.RunRequest)
+	log.Trace("w.simulateSmartContract()", "input", prettyJson(input))
+
+	vmOutput, err := w.vm.SimulateCall(input, request.Overrides)
+
+	response := &SimulateResponse{}
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
+	response.Error = err
+
+	return response
+}
+
+func (w *world) estimateGas(request EstimateRequest) *EstimateResponse {
+	input := w.prepareCallInput(request.RunRequest)
+	log.Trace("w.estimateGas()", "input", prettyJson(input))
+
+	estimate, err := w.vm.EstimateGas(input)
+
+	response := &EstimateResponse{}
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, nil)
+	response.Error = err
+	if estimate != nil {
+		response.GasUsed = estimate.GasUsed
+		response.GasLocked = estimate.GasLocked
+		response.MinimumGasLimit = estimate.MinimumGasLimit
+		response.RecommendedGasLimit = estimate.RecommendedGasLimit
+		response.Executions = estimate.Executions
+	}
+
+	return response
+}
+
 func (w *world) createAccount(request CreateAccountRequest) *CreateAccountResponse {
 	log.Trace("w.createAccount()", "request", prettyJson(request))
 
@@ -0,0 +1,332 @@
+package wasmcheck
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kalyan3104/k-chain-core-go/core/check"
+	vmcommon "github.com/kalyan3104/k-chain-vm-common-go"
+	"github.com/kalyan3104/k-chain-vm-common-go/builtInFunctions"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/config"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/contexts"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/cryptoapi"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/hostCore"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/vmhost/vmhooks"
+	"github.com/kalyan3104/k-chain-vm-v1_3-go/wasmer"
+)
+
+const importNamespace = "env"
+
+const wasmPageSize = 65536
+
+// ArgsAnalyzer holds the arguments needed to create an Analyzer
+type ArgsAnalyzer struct {
+	GasSchedule          config.GasScheduleMap
+	BuiltInFuncContainer vmcommon.BuiltInFunctionContainer
+	EnableEpochsHandler  vmhost.EnableEpochsHandler
+}
+
+// ImportReport describes a single import of the contract
+type ImportReport struct {
+	Module      string `json:"module"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	GasCategory string `json:"gasCategory,omitempty"`
+	Known       bool   `json:"known"`
+	Flag        string `json:"flag,omitempty"`
+}
+
+// ExportReport describes a single export of the contract
+type ExportReport struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	NumParams  int    `json:"numParams"`
+	NumResults int    `json:"numResults"`
+}
+
+// MemoryReport describes the memory declared by the contract, and how far the VM lets it grow
+type MemoryReport struct {
+	Declared           bool   `json:"declared"`
+	Imported           bool   `json:"imported"`
+	InitialPages       uint32 `json:"initialPages"`
+	MaximumPages       uint32 `json:"maximumPages,omitempty"`
+	HasMaximum         bool   `json:"hasMaximum"`
+	MaxMemoryGrow      uint64 `json:"maxMemoryGrow"`
+	MaxMemoryGrowDelta uint64 `json:"maxMemoryGrowDelta"`
+	MaxReachablePages  uint64 `json:"maxReachablePages"`
+}
+
+// Report is the result of analyzing a contract. The contract would be rejected on deploy if Errors is not empty.
+type Report struct {
+	Imports         []*ImportReport `json:"imports"`
+	Exports         []*ExportReport `json:"exports"`
+	Memory          MemoryReport    `json:"memory"`
+	CodeSectionSize uint32          `json:"codeSectionSize"`
+	DataSectionSize uint32          `json:"dataSectionSize"`
+	NumFunctions    uint32          `json:"numFunctions"`
+	NumDataSegments uint32          `json:"numDataSegments"`
+	Errors          []string        `json:"errors"`
+	Warnings        []string        `json:"warnings"`
+}
+
+// IsValid returns true if no errors were found
+func (report *Report) IsValid() bool {
+	return len(report.Errors) == 0
+}
+
+func (report *Report) addError(format string, args ...interface{}) {
+	report.Errors = append(report.Errors, fmt.Sprintf(format, args...))
+}
+
+func (report *Report) addWarning(format string, args ...interface{}) {
+	report.Warnings = append(report.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Analyzer checks contract code statically, then instantiates it and runs the deploy-time validator
+type Analyzer struct {
+	apiNames             vmcommon.FunctionNames
+	gasCategories        map[string]string
+	builtInFuncContainer vmcommon.BuiltInFunctionContainer
+	enableEpochsHandler  vmhost.EnableEpochsHandler
+	gasSchedule          config.GasScheduleMap
+}
+
+// NewAnalyzer creates a new Analyzer. It registers the VM hooks and opcode costs with Wasmer,
+// like the VM host does, so it must not be used in the same process as a running VM.
+func NewAnalyzer(args ArgsAnalyzer) (*Analyzer, error) {
+	if args.GasSchedule == nil {
+		return nil, ErrNilGasSchedule
+	}
+	if check.IfNil(args.EnableEpochsHandler) {
+		return nil, vmhost.ErrNilEnableEpochsHandler
+	}
+	if check.IfNil(args.BuiltInFuncContainer) {
+		args.BuiltInFuncContainer = builtInFunctions.NewBuiltInFunctionContainer()
+	}
+
+	gasCostConfig, err := config.CreateGasConfig(args.GasSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzer := &Analyzer{
+		gasCategories:        make(map[string]string),
+		builtInFuncContainer: args.BuiltInFuncContainer,
+		enableEpochsHandler:  args.EnableEpochsHandler,
+		gasSchedule:          args.GasSchedule,
+	}
+
+	imports, err := analyzer.registerImports()
+	if err != nil {
+		return nil, err
+	}
+	analyzer.apiNames = imports.Names()
+
+	err = wasmer.SetImports(imports)
+	if err != nil {
+		return nil, err
+	}
+
+	opcodeCosts := gasCostConfig.WASMOpcodeCost.ToOpcodeCostsArray()
+	wasmer.SetOpcodeCosts(&opcodeCosts)
+
+	return analyzer, nil
+}
+
+// registerImports builds the same imports as the VM host, recording the gas cost category of each group
+func (analyzer *Analyzer) registerImports() (*wasmer.Imports, error) {
+	imports, err := vmhooks.BaseOpsAPIImports()
+	if err != nil {
+		return nil, err
+	}
+	analyzer.setGasCategory(imports.Names(), "BaseOpsAPICost")
+
+	groups := []struct {
+		gasCategory string
+		addImports  func(*wasmer.Imports) (*wasmer.Imports, error)
+	}{
+		{"BigIntAPICost", vmhooks.BigIntImports},
+		{"BaseOpsAPICost", vmhooks.SmallIntImports},
+		{"CryptoAPICost", cryptoapi.CryptoImports},
+	}
+	for _, group := range groups {
+		groupImports, err := group.addImports(wasmer.NewImports())
+		if err != nil {
+			return nil, err
+		}
+		analyzer.setGasCategory(groupImports.Names(), group.gasCategory)
+
+		imports, err = group.addImports(imports)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return imports, nil
+}
+
+func (analyzer *Analyzer) setGasCategory(names vmcommon.FunctionNames, gasCategory string) {
+	for name := range names {
+		analyzer.gasCategories[name] = gasCategory
+	}
+}
+
+// Analyze produces the report for the given contract code.
+// An error is only returned if the code is not a well-formed WebAssembly binary.
+func (analyzer *Analyzer) Analyze(code []byte) (*Report, error) {
+	m, err := parseModule(code)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		CodeSectionSize: m.codeSectionSize,
+		DataSectionSize: m.dataSectionSize,
+		NumFunctions:    m.numFunctionBodies,
+		NumDataSegments: m.numDataSegments,
+	}
+
+	analyzer.checkImports(m, report)
+	analyzer.checkExports(m, report)
+	analyzer.checkMemory(m, report)
+	analyzer.runValidator(code, report)
+
+	return report, nil
+}
+
+func (analyzer *Analyzer) checkImports(m *module, report *Report) {
+	for _, imp := range m.imports {
+		importReport := &ImportReport{
+			Module: imp.module,
+			Name:   imp.name,
+			Kind:   externalKindNames[imp.kind],
+		}
+		report.Imports = append(report.Imports, importReport)
+
+		if imp.kind != externalFunction {
+			report.addError("unsupported %s import %s.%s", importReport.Kind, imp.module, imp.name)
+			continue
+		}
+
+		_, importReport.Known = analyzer.apiNames[imp.name]
+		importReport.Known = importReport.Known && imp.module == importNamespace
+		importReport.GasCategory = analyzer.gasCategories[imp.name]
+
+		if !importReport.Known {
+			report.addError("unknown import %s.%s", imp.module, imp.name)
+		}
+
+		flag, isGated := hostCore.ImportFlag(imp.name)
+		if !isGated {
+			continue
+		}
+		importReport.Flag = string(flag)
+		if !analyzer.enableEpochsHandler.IsFlagEnabled(flag) {
+			report.addError("import %s requires %s to be enabled", imp.name, flag)
+		}
+	}
+}
+
+func (analyzer *Analyzer) checkExports(m *module, report *Report) {
+	for _, export := range m.exports {
+		exportReport := &ExportReport{
+			Name: export.name,
+			Kind: externalKindNames[export.kind],
+		}
+		if export.kind == externalFunction {
+			signature, found := m.functionSignature(export.index)
+			if !found {
+				report.addError("export %s refers to an unknown function", export.name)
+			}
+			exportReport.NumParams = signature.numParams
+			exportReport.NumResults = signature.numResults
+		}
+		report.Exports = append(report.Exports, exportReport)
+	}
+
+	sort.Slice(report.Exports, func(i, j int) bool {
+		return report.Exports[i].Name < report.Exports[j].Name
+	})
+}
+
+func (analyzer *Analyzer) checkMemory(m *module, report *Report) {
+	memoryReport := &report.Memory
+	memoryReport.MaxMemoryGrow = contexts.MaxMemoryGrow
+	memoryReport.MaxMemoryGrowDelta = contexts.MaxMemoryGrowDelta
+
+	var memoryLimits limits
+	for _, imp := range m.imports {
+		if imp.kind == externalMemory {
+			memoryReport.Imported = true
+			memoryLimits = imp.memory
+		}
+	}
+	if len(m.memories) > 0 {
+		memoryReport.Declared = true
+		memoryLimits = m.memories[0]
+	}
+
+	if !memoryReport.Declared {
+		report.addError("%s", "memory declaration missing")
+		return
+	}
+
+	memoryReport.InitialPages = memoryLimits.initial
+	memoryReport.MaximumPages = memoryLimits.maximum
+	memoryReport.HasMaximum = memoryLimits.hasMaximum
+	memoryReport.MaxReachablePages = uint64(memoryLimits.initial) + contexts.MaxMemoryGrow*contexts.MaxMemoryGrowDelta
+	if memoryLimits.hasMaximum && uint64(memoryLimits.maximum) < memoryReport.MaxReachablePages {
+		memoryReport.MaxReachablePages = uint64(memoryLimits.maximum)
+	}
+
+	if memoryLimits.hasMaximum && uint64(memoryLimits.maximum) > memoryReport.MaxReachablePages {
+		report.addWarning("declared maximum of %d pages cannot be reached", memoryLimits.maximum)
+	}
+	if uint64(report.DataSectionSize) > uint64(memoryLimits.initial)*wasmPageSize {
+		report.addWarning("data section of %d bytes is larger than the initial memory", report.DataSectionSize)
+	}
+}
+
+// runValidator instantiates the contract and runs the same checks as a deploy would.
+// Instantiation is skipped if there are unknown imports, since Wasmer could not link them.
+func (analyzer *Analyzer) runValidator(code []byte, report *Report) {
+	for _, importReport := range report.Imports {
+		if !importReport.Known {
+			report.addWarning("%s", "contract not instantiated because of unknown imports, validator skipped")
+			return
+		}
+	}
+
+	gasCostConfig, err := config.CreateGasConfig(analyzer.gasSchedule)
+	if err != nil {
+		report.addError("gas schedule: %s", err.Error())
+		return
+	}
+
+	options := wasmer.CompilationOptions{
+		GasLimit:           0,
+		UnmeteredLocals:    uint64(gasCostConfig.WASMOpcodeCost.LocalsUnmetered),
+		MaxMemoryGrow:      contexts.MaxMemoryGrow,
+		MaxMemoryGrowDelta: contexts.MaxMemoryGrowDelta,
+		OpcodeTrace:        false,
+		Metering:           true,
+		RuntimeBreakpoints: true,
+	}
+	instance, err := wasmer.NewInstanceWithOptions(code, options)
+	if err != nil {
+		report.addError("instantiation failed: %s", err.Error())
+		return
+	}
+	defer instance.Clean()
+
+	err = contexts.ValidateContractInstance(instance, analyzer.apiNames, analyzer.builtInFuncContainer, analyzer.isImportEnabled)
+	if err != nil {
+		report.addError("validator: %s", err.Error())
+	}
+}
+
+func (analyzer *Analyzer) isImportEnabled(importName string) bool {
+	flag, isGated := hostCore.ImportFlag(importName)
+	return !isGated || analyzer.enableEpochsHandler.IsFlagEnabled(flag)
+}
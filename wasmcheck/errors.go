@@ -0,0 +1,18 @@
+package wasmcheck
+
+import "errors"
+
+// ErrInvalidWasmMagic signals that the code does not start with the WebAssembly magic number
+var ErrInvalidWasmMagic = errors.New("invalid wasm magic number")
+
+// ErrUnsupportedWasmVersion signals that the code is not a version 1 WebAssembly binary
+var ErrUnsupportedWasmVersion = errors.New("unsupported wasm version")
+
+// ErrUnexpectedEndOfWasm signals that the code ends in the middle of a section
+var ErrUnexpectedEndOfWasm = errors.New("unexpected end of wasm code")
+
+// ErrInvalidWasmSection signals that a section could not be decoded
+var ErrInvalidWasmSection = errors.New("invalid wasm section")
+
+// ErrNilGasSchedule signals that the analyzer was created without a gas schedule
+var ErrNilGasSchedule = errors.New("nil gas schedule")
@@ -0,0 +1,51 @@
+package wasmcheck
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteText writes the report in a human-readable form
+func (report *Report) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "imports (%d):\n", len(report.Imports))
+	for _, importReport := range report.Imports {
+		status := importReport.GasCategory
+		if !importReport.Known {
+			status = "UNKNOWN"
+		}
+		if len(importReport.Flag) > 0 {
+			status += ", requires " + importReport.Flag
+		}
+		fmt.Fprintf(w, "  %s.%s (%s) %s\n", importReport.Module, importReport.Name, importReport.Kind, status)
+	}
+
+	fmt.Fprintf(w, "exports (%d):\n", len(report.Exports))
+	for _, exportReport := range report.Exports {
+		fmt.Fprintf(w, "  %s (%s) params: %d, results: %d\n", exportReport.Name, exportReport.Kind, exportReport.NumParams, exportReport.NumResults)
+	}
+
+	memory := report.Memory
+	fmt.Fprintln(w, "memory:")
+	if memory.Declared {
+		maximum := "none"
+		if memory.HasMaximum {
+			maximum = fmt.Sprintf("%d pages", memory.MaximumPages)
+		}
+		fmt.Fprintf(w, "  initial: %d pages, declared maximum: %s\n", memory.InitialPages, maximum)
+		fmt.Fprintf(w, "  memory.grow: at most %d calls of at most %d pages, reachable: %d pages\n",
+			memory.MaxMemoryGrow, memory.MaxMemoryGrowDelta, memory.MaxReachablePages)
+	} else {
+		fmt.Fprintln(w, "  not declared")
+	}
+
+	fmt.Fprintln(w, "sections:")
+	fmt.Fprintf(w, "  code: %d bytes, %d functions\n", report.CodeSectionSize, report.NumFunctions)
+	fmt.Fprintf(w, "  data: %d bytes, %d segments\n", report.DataSectionSize, report.NumDataSegments)
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "WARNING: %s\n", warning)
+	}
+	for _, errorMessage := range report.Errors {
+		fmt.Fprintf(w, "ERROR: %s\n", errorMessage)
+	}
+}
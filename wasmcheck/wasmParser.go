@@ -0,0 +1,353 @@
+package wasmcheck
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	sectionType     = 1
+	sectionImport   = 2
+	sectionFunction = 3
+	sectionMemory   = 5
+	sectionExport   = 7
+	sectionCode     = 10
+	sectionData     = 11
+)
+
+const (
+	externalFunction = 0
+	externalTable    = 1
+	externalMemory   = 2
+	externalGlobal   = 3
+)
+
+const functionTypeForm = 0x60
+
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d}
+var wasmVersion = []byte{0x01, 0x00, 0x00, 0x00}
+
+var externalKindNames = map[byte]string{
+	externalFunction: "function",
+	externalTable:    "table",
+	externalMemory:   "memory",
+	externalGlobal:   "global",
+}
+
+type functionType struct {
+	numParams  int
+	numResults int
+}
+
+type limits struct {
+	initial    uint32
+	maximum    uint32
+	hasMaximum bool
+}
+
+type moduleImport struct {
+	module    string
+	name      string
+	kind      byte
+	typeIndex uint32
+	memory    limits
+}
+
+type moduleExport struct {
+	name  string
+	kind  byte
+	index uint32
+}
+
+// module is the static view of a WebAssembly binary, as far as the analyzer needs it
+type module struct {
+	types             []functionType
+	imports           []moduleImport
+	functionTypes     []uint32
+	memories          []limits
+	exports           []moduleExport
+	codeSectionSize   uint32
+	dataSectionSize   uint32
+	numFunctionBodies uint32
+	numDataSegments   uint32
+}
+
+// functionSignature returns the signature of the function with the given index, counting imports first
+func (m *module) functionSignature(functionIndex uint32) (functionType, bool) {
+	index := int(functionIndex)
+	for _, imp := range m.imports {
+		if imp.kind != externalFunction {
+			continue
+		}
+		if index == 0 {
+			return m.typeAt(imp.typeIndex)
+		}
+		index--
+	}
+
+	if index >= len(m.functionTypes) {
+		return functionType{}, false
+	}
+	return m.typeAt(m.functionTypes[index])
+}
+
+func (m *module) typeAt(typeIndex uint32) (functionType, bool) {
+	if int(typeIndex) >= len(m.types) {
+		return functionType{}, false
+	}
+	return m.types[typeIndex], true
+}
+
+type wasmReader struct {
+	data   []byte
+	offset int
+}
+
+func parseModule(code []byte) (*module, error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], wasmMagic) {
+		return nil, ErrInvalidWasmMagic
+	}
+	if !bytes.Equal(code[4:8], wasmVersion) {
+		return nil, ErrUnsupportedWasmVersion
+	}
+
+	m := &module{}
+	reader := &wasmReader{data: code, offset: 8}
+	for !reader.done() {
+		sectionID, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+		sectionSize, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := reader.readBytes(int(sectionSize))
+		if err != nil {
+			return nil, err
+		}
+
+		err = m.parseSection(sectionID, payload)
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", sectionID, err)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *module) parseSection(sectionID byte, payload []byte) error {
+	reader := &wasmReader{data: payload}
+	switch sectionID {
+	case sectionType:
+		return reader.readVector(func() error {
+			return m.parseFunctionType(reader)
+		})
+	case sectionImport:
+		return reader.readVector(func() error {
+			return m.parseImport(reader)
+		})
+	case sectionFunction:
+		return reader.readVector(func() error {
+			typeIndex, err := reader.readU32()
+			m.functionTypes = append(m.functionTypes, typeIndex)
+			return err
+		})
+	case sectionMemory:
+		return reader.readVector(func() error {
+			memoryLimits, err := reader.readLimits()
+			m.memories = append(m.memories, memoryLimits)
+			return err
+		})
+	case sectionExport:
+		return reader.readVector(func() error {
+			return m.parseExport(reader)
+		})
+	case sectionCode:
+		m.codeSectionSize = uint32(len(payload))
+		count, err := reader.readU32()
+		m.numFunctionBodies = count
+		return err
+	case sectionData:
+		m.dataSectionSize = uint32(len(payload))
+		count, err := reader.readU32()
+		m.numDataSegments = count
+		return err
+	}
+
+	return nil
+}
+
+func (m *module) parseFunctionType(reader *wasmReader) error {
+	form, err := reader.readByte()
+	if err != nil {
+		return err
+	}
+	if form != functionTypeForm {
+		return ErrInvalidWasmSection
+	}
+
+	params, err := reader.readBytesVector()
+	if err != nil {
+		return err
+	}
+	results, err := reader.readBytesVector()
+	if err != nil {
+		return err
+	}
+
+	m.types = append(m.types, functionType{
+		numParams:  len(params),
+		numResults: len(results),
+	})
+	return nil
+}
+
+func (m *module) parseImport(reader *wasmReader) error {
+	moduleName, err := reader.readName()
+	if err != nil {
+		return err
+	}
+	name, err := reader.readName()
+	if err != nil {
+		return err
+	}
+	kind, err := reader.readByte()
+	if err != nil {
+		return err
+	}
+
+	imp := moduleImport{
+		module: moduleName,
+		name:   name,
+		kind:   kind,
+	}
+	switch kind {
+	case externalFunction:
+		imp.typeIndex, err = reader.readU32()
+	case externalTable:
+		_, err = reader.readByte()
+		if err == nil {
+			_, err = reader.readLimits()
+		}
+	case externalMemory:
+		imp.memory, err = reader.readLimits()
+	case externalGlobal:
+		_, err = reader.readBytes(2)
+	default:
+		err = ErrInvalidWasmSection
+	}
+	if err != nil {
+		return err
+	}
+
+	m.imports = append(m.imports, imp)
+	return nil
+}
+
+func (m *module) parseExport(reader *wasmReader) error {
+	name, err := reader.readName()
+	if err != nil {
+		return err
+	}
+	kind, err := reader.readByte()
+	if err != nil {
+		return err
+	}
+	index, err := reader.readU32()
+	if err != nil {
+		return err
+	}
+
+	m.exports = append(m.exports, moduleExport{
+		name:  name,
+		kind:  kind,
+		index: index,
+	})
+	return nil
+}
+
+func (reader *wasmReader) done() bool {
+	return reader.offset >= len(reader.data)
+}
+
+func (reader *wasmReader) readByte() (byte, error) {
+	if reader.done() {
+		return 0, ErrUnexpectedEndOfWasm
+	}
+	b := reader.data[reader.offset]
+	reader.offset++
+	return b, nil
+}
+
+func (reader *wasmReader) readBytes(length int) ([]byte, error) {
+	if length < 0 || reader.offset+length > len(reader.data) {
+		return nil, ErrUnexpectedEndOfWasm
+	}
+	result := reader.data[reader.offset : reader.offset+length]
+	reader.offset += length
+	return result, nil
+}
+
+// readU32 decodes an unsigned LEB128 value of at most 32 bits
+func (reader *wasmReader) readU32() (uint32, error) {
+	result := uint32(0)
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := reader.readByte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+	}
+
+	return 0, ErrInvalidWasmSection
+}
+
+func (reader *wasmReader) readBytesVector() ([]byte, error) {
+	length, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+	return reader.readBytes(int(length))
+}
+
+func (reader *wasmReader) readName() (string, error) {
+	name, err := reader.readBytesVector()
+	return string(name), err
+}
+
+func (reader *wasmReader) readLimits() (limits, error) {
+	flags, err := reader.readByte()
+	if err != nil {
+		return limits{}, err
+	}
+
+	result := limits{}
+	result.initial, err = reader.readU32()
+	if err != nil {
+		return limits{}, err
+	}
+	if flags&0x01 != 0 {
+		result.hasMaximum = true
+		result.maximum, err = reader.readU32()
+	}
+	return result, err
+}
+
+func (reader *wasmReader) readVector(readItem func() error) error {
+	count, err := reader.readU32()
+	if err != nil {
+		return err
+	}
+
+	for i := uint32(0); i < count; i++ {
+		err = readItem()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package wasmcheck
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const counterWasmPath = "./../test/contracts/counter/output/counter.wasm"
+
+// minimalModule imports env.getGasLeft, declares 2 to 16 pages of memory and exports a void "init"
+var minimalModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// type section: () -> (), () -> i64
+	0x01, 0x08, 0x02, 0x60, 0x00, 0x00, 0x60, 0x00, 0x01, 0x7e,
+	// import section: env.getGasLeft, type 1
+	0x02, 0x12, 0x01, 0x03, 'e', 'n', 'v', 0x0a, 'g', 'e', 't', 'G', 'a', 's', 'L', 'e', 'f', 't', 0x00, 0x01,
+	// function section: one function of type 0
+	0x03, 0x02, 0x01, 0x00,
+	// memory section: min 2, max 16
+	0x05, 0x04, 0x01, 0x01, 0x02, 0x10,
+	// export section: "init" -> function 1, "memory" -> memory 0
+	0x07, 0x11, 0x02, 0x04, 'i', 'n', 'i', 't', 0x00, 0x01, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00,
+	// code section: one empty body
+	0x0a, 0x04, 0x01, 0x02, 0x00, 0x0b,
+}
+
+func TestParseModule_Minimal(t *testing.T) {
+	m, err := parseModule(minimalModule)
+	require.Nil(t, err)
+
+	require.Len(t, m.imports, 1)
+	require.Equal(t, "env", m.imports[0].module)
+	require.Equal(t, "getGasLeft", m.imports[0].name)
+
+	require.Len(t, m.memories, 1)
+	require.Equal(t, limits{initial: 2, maximum: 16, hasMaximum: true}, m.memories[0])
+
+	require.Len(t, m.exports, 2)
+	require.Equal(t, "init", m.exports[0].name)
+	signature, found := m.functionSignature(m.exports[0].index)
+	require.True(t, found)
+	require.Equal(t, functionType{numParams: 0, numResults: 0}, signature)
+
+	importSignature, found := m.functionSignature(0)
+	require.True(t, found)
+	require.Equal(t, functionType{numParams: 0, numResults: 1}, importSignature)
+
+	require.Equal(t, uint32(4), m.codeSectionSize)
+	require.Equal(t, uint32(1), m.numFunctionBodies)
+}
+
+func TestParseModule_Invalid(t *testing.T) {
+	_, err := parseModule([]byte("not wasm"))
+	require.Equal(t, ErrInvalidWasmMagic, err)
+
+	_, err = parseModule([]byte{0x00, 0x61, 0x73, 0x6d, 0x02, 0x00, 0x00, 0x00})
+	require.Equal(t, ErrUnsupportedWasmVersion, err)
+
+	_, err = parseModule(minimalModule[:len(minimalModule)-3])
+	require.ErrorIs(t, err, ErrUnexpectedEndOfWasm)
+}
+
+func TestParseModule_Contract(t *testing.T) {
+	code, err := ioutil.ReadFile(counterWasmPath)
+	require.Nil(t, err)
+
+	m, err := parseModule(code)
+	require.Nil(t, err)
+	require.NotEmpty(t, m.imports)
+	require.Len(t, m.memories, 1)
+	require.Greater(t, m.codeSectionSize, uint32(0))
+
+	exportNames := make(map[string]bool)
+	for _, export := range m.exports {
+		exportNames[export.name] = true
+	}
+	require.True(t, exportNames["increment"])
+}
@@ -93,6 +93,12 @@ func cWasmerInstanceGetBreakpointValue(instance *cWasmerInstanceT) uint64 {
 	))
 }
 
+func cWasmerInstanceReset(instance *cWasmerInstanceT) cWasmerResultT {
+	return (cWasmerResultT)(C.wasmer_instance_reset(
+		(*C.wasmer_instance_t)(instance),
+	))
+}
+
 func cWasmerInstanceIsFunctionImported(instance *cWasmerInstanceT, name string) bool {
 	var functionName = cCString(name)
 	return bool(C.wasmer_instance_is_function_imported(
@@ -295,6 +295,12 @@ func (instance *Instance) Cache() ([]byte, error) {
 	return goBytes, nil
 }
 
+// Reset restores the memories and the globals of the instance to their state right after instantiation
+func (instance *Instance) Reset() bool {
+	result := cWasmerInstanceReset(instance.instance)
+	return result == cWasmerOk
+}
+
 // IsFunctionImported returns true if the instance imports the specified function
 func (instance *Instance) IsFunctionImported(name string) bool {
 	return cWasmerInstanceIsFunctionImported(instance.instance, name)
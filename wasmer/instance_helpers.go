@@ -1,7 +1,6 @@
 package wasmer
 
 import (
-	"fmt"
 	"unsafe"
 )
 
@@ -150,8 +149,8 @@ func createExportedFunctionWrapper(
 		)
 
 		if callResult != cWasmerOk {
-			err := fmt.Errorf("Failed to call the `%s` exported function.", exportedFunctionName)
-			return Void(), newWrappedError(err)
+			isBreakpoint := cWasmerInstanceGetBreakpointValue(c_instance) != 0
+			return Void(), newTrapError(exportedFunctionName, isBreakpoint)
 		}
 
 		value, err := convertWasmOutputToValue(wasmFunctionOutputsArity, wasmOutputs, exportedFunctionName)
@@ -17,6 +17,7 @@ type InstanceHandler interface {
 	GetInstanceCtxMemory() MemoryHandler
 	GetMemory() MemoryHandler
 	IsFunctionImported(name string) bool
+	Reset() bool
 }
 
 // MemoryHandler defines the functionality of the memory of a Wasmer instance
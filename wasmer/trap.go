@@ -0,0 +1,106 @@
+package wasmer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TrapKind tells why the execution of an exported function was interrupted
+type TrapKind int
+
+const (
+	// TrapUnknown is a trap whose reason was not reported by Wasmer
+	TrapUnknown TrapKind = iota
+
+	// TrapRuntimeBreakpoint is an interruption requested by the host through a runtime breakpoint
+	TrapRuntimeBreakpoint
+
+	// TrapUnreachable is the execution of an "unreachable" instruction
+	TrapUnreachable
+
+	// TrapMemoryOutOfBounds is an access outside of the linear memory
+	TrapMemoryOutOfBounds
+
+	// TrapCallIndirectOutOfBounds is a "call_indirect" outside of the table
+	TrapCallIndirectOutOfBounds
+
+	// TrapCallIndirectSignature is a "call_indirect" to a function with another signature
+	TrapCallIndirectSignature
+
+	// TrapIllegalArithmetic is an integer division by zero or an integer overflow
+	TrapIllegalArithmetic
+
+	// TrapMisalignedAtomicAccess is a misaligned atomic memory access
+	TrapMisalignedAtomicAccess
+)
+
+// the names of the Wasmer exception codes, as they appear in its error messages
+var trapKindNames = []struct {
+	name string
+	kind TrapKind
+}{
+	{"IncorrectCallIndirectSignature", TrapCallIndirectSignature},
+	{"CallIndirectOOB", TrapCallIndirectOutOfBounds},
+	{"MemoryOutOfBounds", TrapMemoryOutOfBounds},
+	{"IllegalArithmetic", TrapIllegalArithmetic},
+	{"MisalignedAtomicAccess", TrapMisalignedAtomicAccess},
+	{"Unreachable", TrapUnreachable},
+}
+
+// String returns the name of the trap kind
+func (kind TrapKind) String() string {
+	switch kind {
+	case TrapRuntimeBreakpoint:
+		return "runtime breakpoint"
+	case TrapUnreachable:
+		return "unreachable"
+	case TrapMemoryOutOfBounds:
+		return "memory out of bounds"
+	case TrapCallIndirectOutOfBounds:
+		return "call_indirect out of bounds"
+	case TrapCallIndirectSignature:
+		return "call_indirect signature mismatch"
+	case TrapIllegalArithmetic:
+		return "illegal arithmetic"
+	case TrapMisalignedAtomicAccess:
+		return "misaligned atomic access"
+	}
+	return "unknown"
+}
+
+// TrapError is returned by exported functions whose execution was interrupted
+type TrapError struct {
+	FunctionName string
+	Kind         TrapKind
+	Details      string
+}
+
+func newTrapError(functionName string, isBreakpoint bool) *TrapError {
+	details, err := GetLastError()
+	if err != nil {
+		details = "unknown details"
+	}
+
+	trap := &TrapError{
+		FunctionName: functionName,
+		Kind:         TrapUnknown,
+		Details:      details,
+	}
+	if isBreakpoint {
+		trap.Kind = TrapRuntimeBreakpoint
+		return trap
+	}
+	for _, trapKindName := range trapKindNames {
+		if strings.Contains(details, trapKindName.name) {
+			trap.Kind = trapKindName.kind
+			break
+		}
+	}
+
+	return trap
+}
+
+// Error keeps the message format of the errors previously returned by exported functions
+func (trap *TrapError) Error() string {
+	return fmt.Sprintf("Failed to call the `%s` exported function.: %s", trap.FunctionName, trap.Details)
+}
@@ -0,0 +1,603 @@
+package wasmcov
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ImportModule is the module of the function called by the probes of instrumented code
+const ImportModule = "env"
+
+// ImportName is the name of the function called by the probes of instrumented code.
+// It receives the identifier of the probe as its only argument.
+const ImportName = "coverageProbe"
+
+// ErrInvalidWasmMagic signals code which is not a wasm module
+var ErrInvalidWasmMagic = errors.New("invalid wasm magic number")
+
+// ErrUnsupportedWasmVersion signals a wasm binary format other than version 1
+var ErrUnsupportedWasmVersion = errors.New("unsupported wasm version")
+
+// ErrUnsupportedOpcode signals an instruction the instrumentation cannot decode
+var ErrUnsupportedOpcode = errors.New("unsupported wasm opcode")
+
+// ErrUnsupportedElementSegment signals an element segment other than an active segment of function indices
+var ErrUnsupportedElementSegment = errors.New("unsupported element segment")
+
+// ErrInvalidFunctionBody signals a function body with instructions after its final "end"
+var ErrInvalidFunctionBody = errors.New("invalid function body")
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+const (
+	sectionCustom    = 0
+	sectionType      = 1
+	sectionImport    = 2
+	sectionExport    = 7
+	sectionStart     = 8
+	sectionElement   = 9
+	sectionCode      = 10
+	sectionDataCount = 12
+)
+
+const externalKindFunction = 0
+
+// the position of the sections in a module, which differs from their id for the data count section
+func sectionOrder(sectionID byte) int {
+	if sectionID == sectionDataCount {
+		return sectionCode
+	}
+	if sectionID >= sectionCode {
+		return int(sectionID) + 1
+	}
+	return int(sectionID)
+}
+
+// the identifiers of the probes are spread over 32 bits, so that combining
+// the identifiers of consecutive probes into edges rarely collides
+func probeID(probeIndex uint32) int32 {
+	return int32((probeIndex + 1) * 0x9e3779b1)
+}
+
+// InstrumentedModule is a wasm module rewritten to report its execution to the coverage import.
+// FunctionProbes and FunctionNames describe the functions defined by the module, in order:
+// the identifier of the probe at the start of each function, and its name, taken from
+// its export, from the name section, or "func[<index>]" otherwise.
+type InstrumentedModule struct {
+	Code           []byte
+	NumProbes      int
+	FunctionProbes []int32
+	FunctionNames  []string
+}
+
+type section struct {
+	id      byte
+	payload []byte
+}
+
+type instrumenter struct {
+	numTypes         uint32
+	numFuncImports   uint32
+	probeFuncIndex   uint32
+	firstProbeIndex  uint32
+	numProbes        int
+	functionProbes   []int32
+	hasTypeSection   bool
+	hasImportSection bool
+}
+
+// Instrument adds a call to the coverage import at the start of each function, of each block,
+// loop and branch of "if", after the end of each block, and after each conditional branch.
+// The coverage import is added after the other imported functions, shifting the indices of
+// the functions defined by the module; custom sections are dropped, since the name section
+// refers to these indices.
+// The probes are numbered from firstProbeIndex, so that the probes of different contracts
+// can be told apart; the same code instrumented with the same index yields the same probes.
+func Instrument(code []byte, firstProbeIndex uint32) (*InstrumentedModule, error) {
+	if len(code) < len(wasmHeader) || !bytes.Equal(code[:4], wasmHeader[:4]) {
+		return nil, ErrInvalidWasmMagic
+	}
+	if !bytes.Equal(code[4:8], wasmHeader[4:]) {
+		return nil, ErrUnsupportedWasmVersion
+	}
+
+	sections, nameSection, err := readSections(code[len(wasmHeader):])
+	if err != nil {
+		return nil, err
+	}
+
+	inst := &instrumenter{
+		firstProbeIndex: firstProbeIndex,
+	}
+	for _, sect := range sections {
+		switch sect.id {
+		case sectionType:
+			inst.hasTypeSection = true
+			inst.numTypes, err = (&wasmReader{data: sect.payload}).readU32()
+		case sectionImport:
+			inst.hasImportSection = true
+			inst.numFuncImports, err = countFunctionImports(sect.payload)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	inst.probeFuncIndex = inst.numFuncImports
+
+	if !inst.hasTypeSection {
+		sections = append(sections, &section{id: sectionType, payload: []byte{0}})
+	}
+	if !inst.hasImportSection {
+		sections = append(sections, &section{id: sectionImport, payload: []byte{0}})
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sectionOrder(sections[i].id) < sectionOrder(sections[j].id)
+	})
+
+	instrumented := append([]byte{}, wasmHeader...)
+	for _, sect := range sections {
+		payload, err := inst.rewriteSection(sect)
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", sect.id, err)
+		}
+		instrumented = appendSection(instrumented, sect.id, payload)
+	}
+
+	return &InstrumentedModule{
+		Code:           instrumented,
+		NumProbes:      inst.numProbes,
+		FunctionProbes: inst.functionProbes,
+		FunctionNames:  inst.functionNames(sections, nameSection),
+	}, nil
+}
+
+// readSections yields the non-custom sections, and the payload of the name section, if any
+func readSections(data []byte) ([]*section, []byte, error) {
+	reader := &wasmReader{data: data}
+	sections := make([]*section, 0)
+	var nameSection []byte
+	for !reader.done() {
+		id, err := reader.readByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		size, err := reader.readU32()
+		if err != nil {
+			return nil, nil, err
+		}
+		payload, err := reader.readBytes(int(size))
+		if err != nil {
+			return nil, nil, err
+		}
+		if id == sectionCustom {
+			nameSection = readNameSectionPayload(payload, nameSection)
+			continue
+		}
+		sections = append(sections, &section{id: id, payload: payload})
+	}
+	return sections, nameSection, nil
+}
+
+func readNameSectionPayload(customSection []byte, previous []byte) []byte {
+	reader := &wasmReader{data: customSection}
+	nameLength, err := reader.readU32()
+	if err != nil {
+		return previous
+	}
+	name, err := reader.readBytes(int(nameLength))
+	if err != nil || string(name) != "name" {
+		return previous
+	}
+	return customSection[reader.pos:]
+}
+
+func countFunctionImports(payload []byte) (uint32, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return 0, err
+	}
+
+	numFuncImports := uint32(0)
+	for i := uint32(0); i < count; i++ {
+		for name := 0; name < 2; name++ {
+			length, err := reader.readU32()
+			if err != nil {
+				return 0, err
+			}
+			_, err = reader.readBytes(int(length))
+			if err != nil {
+				return 0, err
+			}
+		}
+		kind, err := reader.readByte()
+		if err != nil {
+			return 0, err
+		}
+		switch kind {
+		case externalKindFunction:
+			numFuncImports++
+			_, err = reader.readU32()
+		case 1: // table
+			_, err = reader.readByte()
+			if err == nil {
+				err = skipLimits(reader)
+			}
+		case 2: // memory
+			err = skipLimits(reader)
+		case 3: // global
+			_, err = reader.readBytes(2)
+		default:
+			err = fmt.Errorf("invalid import kind %d", kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return numFuncImports, nil
+}
+
+func skipLimits(reader *wasmReader) error {
+	flags, err := reader.readByte()
+	if err != nil {
+		return err
+	}
+	_, err = reader.readU32()
+	if err != nil || flags&1 == 0 {
+		return err
+	}
+	_, err = reader.readU32()
+	return err
+}
+
+func (inst *instrumenter) remapFunction(index uint32) uint32 {
+	if index >= inst.probeFuncIndex {
+		return index + 1
+	}
+	return index
+}
+
+func (inst *instrumenter) rewriteSection(sect *section) ([]byte, error) {
+	switch sect.id {
+	case sectionType:
+		return inst.rewriteTypes(sect.payload)
+	case sectionImport:
+		return inst.rewriteImports(sect.payload)
+	case sectionExport:
+		return inst.rewriteExports(sect.payload)
+	case sectionStart:
+		index, err := (&wasmReader{data: sect.payload}).readU32()
+		if err != nil {
+			return nil, err
+		}
+		return appendU32(nil, inst.remapFunction(index)), nil
+	case sectionElement:
+		return inst.rewriteElements(sect.payload)
+	case sectionCode:
+		return inst.rewriteCode(sect.payload)
+	}
+	return sect.payload, nil
+}
+
+// the probe type, (i32) -> (), is added after the other types
+func (inst *instrumenter) rewriteTypes(payload []byte) ([]byte, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := appendU32(nil, count+1)
+	rewritten = append(rewritten, payload[reader.pos:]...)
+	return append(rewritten, 0x60, 0x01, 0x7f, 0x00), nil
+}
+
+func (inst *instrumenter) rewriteImports(payload []byte) ([]byte, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := appendU32(nil, count+1)
+	rewritten = append(rewritten, payload[reader.pos:]...)
+	rewritten = appendName(rewritten, ImportModule)
+	rewritten = appendName(rewritten, ImportName)
+	rewritten = append(rewritten, externalKindFunction)
+	return appendU32(rewritten, inst.numTypes), nil
+}
+
+func (inst *instrumenter) rewriteExports(payload []byte) ([]byte, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := appendU32(nil, count)
+	for i := uint32(0); i < count; i++ {
+		nameLength, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		name, err := reader.readBytes(int(nameLength))
+		if err != nil {
+			return nil, err
+		}
+		kind, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+		index, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		if kind == externalKindFunction {
+			index = inst.remapFunction(index)
+		}
+
+		rewritten = appendName(rewritten, string(name))
+		rewritten = append(rewritten, kind)
+		rewritten = appendU32(rewritten, index)
+	}
+	return rewritten, nil
+}
+
+func (inst *instrumenter) rewriteElements(payload []byte) ([]byte, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := appendU32(nil, count)
+	for i := uint32(0); i < count; i++ {
+		flags, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		if flags != 0 {
+			return nil, fmt.Errorf("%w: flags %d", ErrUnsupportedElementSegment, flags)
+		}
+		rewritten = appendU32(rewritten, flags)
+
+		offsetStart := reader.pos
+		err = skipConstantExpression(reader)
+		if err != nil {
+			return nil, err
+		}
+		rewritten = append(rewritten, payload[offsetStart:reader.pos]...)
+
+		numFunctions, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		rewritten = appendU32(rewritten, numFunctions)
+		for j := uint32(0); j < numFunctions; j++ {
+			index, err := reader.readU32()
+			if err != nil {
+				return nil, err
+			}
+			rewritten = appendU32(rewritten, inst.remapFunction(index))
+		}
+	}
+	return rewritten, nil
+}
+
+func skipConstantExpression(reader *wasmReader) error {
+	for {
+		opcode, err := reader.readByte()
+		if err != nil {
+			return err
+		}
+		if opcode == 0x0b {
+			return nil
+		}
+		err = skipImmediates(reader, opcode)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (inst *instrumenter) rewriteCode(payload []byte) ([]byte, error) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := appendU32(nil, count)
+	for i := uint32(0); i < count; i++ {
+		size, err := reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		body, err := reader.readBytes(int(size))
+		if err != nil {
+			return nil, err
+		}
+		instrumentedBody, err := inst.instrumentBody(body)
+		if err != nil {
+			return nil, fmt.Errorf("function body %d: %w", i, err)
+		}
+		rewritten = appendU32(rewritten, uint32(len(instrumentedBody)))
+		rewritten = append(rewritten, instrumentedBody...)
+	}
+	return rewritten, nil
+}
+
+func (inst *instrumenter) nextProbeID() int32 {
+	return probeID(inst.firstProbeIndex + uint32(inst.numProbes))
+}
+
+func (inst *instrumenter) appendProbe(buffer []byte) []byte {
+	buffer = append(buffer, 0x41) // i32.const
+	buffer = appendS32(buffer, inst.nextProbeID())
+	buffer = append(buffer, 0x10) // call
+	inst.numProbes++
+	return appendU32(buffer, inst.probeFuncIndex)
+}
+
+func (inst *instrumenter) instrumentBody(body []byte) ([]byte, error) {
+	reader := &wasmReader{data: body}
+	numLocalGroups, err := reader.readU32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < numLocalGroups; i++ {
+		_, err = reader.readU32()
+		if err != nil {
+			return nil, err
+		}
+		_, err = reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	instrumented := append([]byte{}, body[:reader.pos]...)
+	inst.functionProbes = append(inst.functionProbes, inst.nextProbeID())
+	instrumented = inst.appendProbe(instrumented)
+
+	depth := 0
+	for !reader.done() {
+		start := reader.pos
+		opcode, err := reader.readByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case 0x10, 0xd2: // call, ref.func
+			index, err := reader.readU32()
+			if err != nil {
+				return nil, err
+			}
+			instrumented = append(instrumented, opcode)
+			instrumented = appendU32(instrumented, inst.remapFunction(index))
+			continue
+		case 0x0b: // end
+			instrumented = append(instrumented, opcode)
+			if depth == 0 {
+				if !reader.done() {
+					return nil, ErrInvalidFunctionBody
+				}
+				return instrumented, nil
+			}
+			depth--
+			instrumented = inst.appendProbe(instrumented)
+			continue
+		}
+
+		err = skipImmediates(reader, opcode)
+		if err != nil {
+			return nil, err
+		}
+		instrumented = append(instrumented, body[start:reader.pos]...)
+
+		switch opcode {
+		case 0x02, 0x03, 0x04: // block, loop, if
+			depth++
+			instrumented = inst.appendProbe(instrumented)
+		case 0x05, 0x0d: // else, br_if
+			instrumented = inst.appendProbe(instrumented)
+		}
+	}
+
+	return nil, ErrUnexpectedEndOfWasm
+}
+
+func skipBlockType(reader *wasmReader) error {
+	if reader.done() {
+		return ErrUnexpectedEndOfWasm
+	}
+	switch reader.data[reader.pos] {
+	case 0x40, 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		reader.pos++
+		return nil
+	}
+	return reader.skipLEB128(33)
+}
+
+func skipU32s(reader *wasmReader, count int) error {
+	for i := 0; i < count; i++ {
+		_, err := reader.readU32()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipImmediates skips the immediate arguments of the instructions of the MVP,
+// of the sign extension, non-trapping conversion, bulk memory and reference types proposals
+func skipImmediates(reader *wasmReader, opcode byte) error {
+	switch {
+	case opcode <= 0x01, opcode == 0x05, opcode == 0x0b, opcode == 0x0f, opcode == 0x1a, opcode == 0x1b:
+		return nil
+	case opcode >= 0x02 && opcode <= 0x04:
+		return skipBlockType(reader)
+	case opcode == 0x0c, opcode == 0x0d, opcode == 0x10:
+		return skipU32s(reader, 1)
+	case opcode == 0x0e:
+		numLabels, err := reader.readU32()
+		if err != nil {
+			return err
+		}
+		return skipU32s(reader, int(numLabels)+1)
+	case opcode == 0x11:
+		return skipU32s(reader, 2)
+	case opcode == 0x1c:
+		numTypes, err := reader.readU32()
+		if err != nil {
+			return err
+		}
+		_, err = reader.readBytes(int(numTypes))
+		return err
+	case opcode >= 0x20 && opcode <= 0x26:
+		return skipU32s(reader, 1)
+	case opcode >= 0x28 && opcode <= 0x3e:
+		return skipU32s(reader, 2)
+	case opcode == 0x3f, opcode == 0x40:
+		return skipU32s(reader, 1)
+	case opcode == 0x41:
+		return reader.skipLEB128(32)
+	case opcode == 0x42:
+		return reader.skipLEB128(64)
+	case opcode == 0x43:
+		_, err := reader.readBytes(4)
+		return err
+	case opcode == 0x44:
+		_, err := reader.readBytes(8)
+		return err
+	case opcode >= 0x45 && opcode <= 0xc4, opcode == 0xd1:
+		return nil
+	case opcode == 0xd0:
+		_, err := reader.readByte()
+		return err
+	case opcode == 0xd2:
+		return skipU32s(reader, 1)
+	case opcode == 0xfc:
+		return skipMiscImmediates(reader)
+	}
+	return fmt.Errorf("%w: 0x%02x", ErrUnsupportedOpcode, opcode)
+}
+
+func skipMiscImmediates(reader *wasmReader) error {
+	subOpcode, err := reader.readU32()
+	if err != nil {
+		return err
+	}
+	switch {
+	case subOpcode <= 7:
+		return nil
+	case subOpcode == 8, subOpcode == 10, subOpcode == 12, subOpcode == 14:
+		return skipU32s(reader, 2)
+	case subOpcode <= 17:
+		return skipU32s(reader, 1)
+	}
+	return fmt.Errorf("%w: 0xfc %d", ErrUnsupportedOpcode, subOpcode)
+}
@@ -0,0 +1,226 @@
+package wasmcov
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func probe(probeIndex uint32, probeFuncIndex uint32) []byte {
+	code := appendS32([]byte{0x41}, probeID(probeIndex))
+	code = append(code, 0x10)
+	return appendU32(code, probeFuncIndex)
+}
+
+func concat(parts ...[]byte) []byte {
+	result := make([]byte, 0)
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+func codeSection(bodies ...[]byte) []byte {
+	payload := appendU32(nil, uint32(len(bodies)))
+	for _, body := range bodies {
+		payload = appendU32(payload, uint32(len(body)))
+		payload = append(payload, body...)
+	}
+	return appendSection(nil, sectionCode, payload)
+}
+
+func exportSection(name string, funcIndex uint32) []byte {
+	payload := appendName([]byte{1}, name)
+	payload = append(payload, externalKindFunction)
+	return appendSection(nil, sectionExport, appendU32(payload, funcIndex))
+}
+
+func importSection(extraImports ...[]byte) []byte {
+	payload := appendU32(nil, uint32(1+len(extraImports)))
+	payload = appendName(payload, "env")
+	payload = appendName(payload, "getGasLeft")
+	payload = append(payload, externalKindFunction, 1)
+	return appendSection(nil, sectionImport, concat(payload, concat(extraImports...)))
+}
+
+func probeImport(typeIndex byte) []byte {
+	entry := appendName(nil, ImportModule)
+	entry = appendName(entry, ImportName)
+	return append(entry, externalKindFunction, typeIndex)
+}
+
+// types: () -> (), () -> i64
+var testTypes = []byte{0x02, 0x60, 0x00, 0x00, 0x60, 0x00, 0x01, 0x7e}
+
+// functions 1 and 2, both of type 0
+var testFunctions = appendSection(nil, 3, []byte{0x02, 0x00, 0x00})
+
+// call 2; end
+var testInitBody = []byte{0x00, 0x10, 0x02, 0x0b}
+
+// block; i32.const 0; br_if 0; end; call 0; drop; end
+var testBranchBody = []byte{0x00, 0x02, 0x40, 0x41, 0x00, 0x0d, 0x00, 0x0b, 0x10, 0x00, 0x1a, 0x0b}
+
+// the name section names functions 1 and 2
+func nameSection() []byte {
+	functionNames := appendU32(nil, 2)
+	functionNames = appendName(appendU32(functionNames, 1), "initImpl")
+	functionNames = appendName(appendU32(functionNames, 2), "branch")
+	payload := appendName(nil, "name")
+	payload = append(payload, nameSubsectionFunctions)
+	payload = appendU32(payload, uint32(len(functionNames)))
+	return appendSection(nil, sectionCustom, append(payload, functionNames...))
+}
+
+func TestInstrument(t *testing.T) {
+	module := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, testTypes),
+		importSection(),
+		testFunctions,
+		exportSection("init", 1),
+		codeSection(testInitBody, testBranchBody),
+		nameSection(),
+	)
+
+	instrumented, err := Instrument(module, 0)
+	require.Nil(t, err)
+	require.Equal(t, 5, instrumented.NumProbes)
+	require.Equal(t, []int32{probeID(0), probeID(1)}, instrumented.FunctionProbes)
+	require.Equal(t, []string{"init", "branch"}, instrumented.FunctionNames)
+
+	expectedTypes := append([]byte{0x03}, testTypes[1:]...)
+	expectedTypes = append(expectedTypes, 0x60, 0x01, 0x7f, 0x00)
+	expected := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, expectedTypes),
+		importSection(probeImport(2)),
+		testFunctions,
+		exportSection("init", 2),
+		codeSection(
+			concat([]byte{0x00}, probe(0, 1), []byte{0x10, 0x03, 0x0b}),
+			concat(
+				[]byte{0x00}, probe(1, 1),
+				[]byte{0x02, 0x40}, probe(2, 1),
+				[]byte{0x41, 0x00, 0x0d, 0x00}, probe(3, 1),
+				[]byte{0x0b}, probe(4, 1),
+				[]byte{0x10, 0x00, 0x1a, 0x0b},
+			),
+		),
+	)
+	require.Equal(t, expected, instrumented.Code)
+}
+
+func TestInstrument_FirstProbeIndex(t *testing.T) {
+	module := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, testTypes),
+		importSection(),
+		testFunctions,
+		codeSection(testInitBody, testBranchBody),
+	)
+
+	first, err := Instrument(module, 100)
+	require.Nil(t, err)
+	require.Equal(t, []int32{probeID(100), probeID(101)}, first.FunctionProbes)
+	require.Equal(t, []string{"func[1]", "func[2]"}, first.FunctionNames)
+
+	second, err := Instrument(module, 100)
+	require.Nil(t, err)
+	require.Equal(t, first.Code, second.Code)
+}
+
+func TestInstrument_WithoutImports(t *testing.T) {
+	// one function with one i32 local, which is also the start function
+	module := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, []byte{0x01, 0x60, 0x00, 0x00}),
+		appendSection(nil, 3, []byte{0x01, 0x00}),
+		appendSection(nil, sectionStart, []byte{0x00}),
+		codeSection([]byte{0x01, 0x01, 0x7f, 0x0b}),
+	)
+
+	instrumented, err := Instrument(module, 0)
+	require.Nil(t, err)
+	require.Equal(t, 1, instrumented.NumProbes)
+	require.Equal(t, []string{"func[0]"}, instrumented.FunctionNames)
+
+	expected := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, []byte{0x02, 0x60, 0x00, 0x00, 0x60, 0x01, 0x7f, 0x00}),
+		appendSection(nil, sectionImport, concat([]byte{0x01}, probeImport(1))),
+		appendSection(nil, 3, []byte{0x01, 0x00}),
+		appendSection(nil, sectionStart, []byte{0x01}),
+		codeSection(concat([]byte{0x01, 0x01, 0x7f}, probe(0, 0), []byte{0x0b})),
+	)
+	require.Equal(t, expected, instrumented.Code)
+}
+
+func TestInstrument_ElementSegments(t *testing.T) {
+	// active segment at offset "i32.const 0", with functions 0 and 1
+	elements := []byte{0x01, 0x00, 0x41, 0x00, 0x0b, 0x02, 0x00, 0x01}
+	module := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, testTypes),
+		importSection(),
+		testFunctions,
+		appendSection(nil, sectionElement, elements),
+		codeSection(testInitBody, testBranchBody),
+	)
+
+	instrumented, err := Instrument(module, 0)
+	require.Nil(t, err)
+
+	expectedElements := appendSection(nil, sectionElement, []byte{0x01, 0x00, 0x41, 0x00, 0x0b, 0x02, 0x00, 0x02})
+	require.Contains(t, string(instrumented.Code), string(expectedElements))
+
+	passiveElements := []byte{0x01, 0x01, 0x00, 0x01, 0x00}
+	module = concat(
+		wasmHeader,
+		appendSection(nil, sectionType, testTypes),
+		appendSection(nil, sectionElement, passiveElements),
+	)
+	_, err = Instrument(module, 0)
+	require.True(t, errors.Is(err, ErrUnsupportedElementSegment))
+}
+
+func TestInstrument_InvalidCode(t *testing.T) {
+	_, err := Instrument([]byte("not wasm"), 0)
+	require.Equal(t, ErrInvalidWasmMagic, err)
+
+	_, err = Instrument([]byte{0x00, 0x61, 0x73, 0x6d, 0x02, 0x00, 0x00, 0x00}, 0)
+	require.Equal(t, ErrUnsupportedWasmVersion, err)
+
+	truncated := concat(wasmHeader, []byte{sectionType, 0x05, 0x01})
+	_, err = Instrument(truncated, 0)
+	require.Equal(t, ErrUnexpectedEndOfWasm, err)
+
+	simd := concat(
+		wasmHeader,
+		appendSection(nil, sectionType, testTypes),
+		codeSection([]byte{0x00, 0xfd, 0x0c, 0x0b}),
+	)
+	_, err = Instrument(simd, 0)
+	require.True(t, errors.Is(err, ErrUnsupportedOpcode))
+
+	afterEnd := concat(
+		wasmHeader,
+		codeSection([]byte{0x00, 0x0b, 0x01}),
+	)
+	_, err = Instrument(afterEnd, 0)
+	require.True(t, errors.Is(err, ErrInvalidFunctionBody))
+}
+
+func TestAppendS32(t *testing.T) {
+	require.Equal(t, []byte{0x00}, appendS32(nil, 0))
+	require.Equal(t, []byte{0x3f}, appendS32(nil, 63))
+	require.Equal(t, []byte{0xc0, 0x00}, appendS32(nil, 64))
+	require.Equal(t, []byte{0x7f}, appendS32(nil, -1))
+	require.Equal(t, []byte{0x40}, appendS32(nil, -64))
+	require.Equal(t, []byte{0xbf, 0x7f}, appendS32(nil, -65))
+
+	reader := &wasmReader{data: appendS32(nil, probeID(7))}
+	require.Nil(t, reader.skipLEB128(32))
+	require.True(t, reader.done())
+}
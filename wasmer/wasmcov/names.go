@@ -0,0 +1,111 @@
+package wasmcov
+
+import (
+	"fmt"
+)
+
+const nameSubsectionFunctions = 1
+
+// functionNames names the functions defined by the module, indexed from 0 for the first
+// defined function, preferring the export names, which are the names of the endpoints
+func (inst *instrumenter) functionNames(sections []*section, nameSection []byte) []string {
+	names := make([]string, len(inst.functionProbes))
+	for index, name := range readFunctionNames(nameSection) {
+		inst.setFunctionName(names, index, name)
+	}
+	for _, sect := range sections {
+		if sect.id != sectionExport {
+			continue
+		}
+		for index, name := range readExportedFunctionNames(sect.payload) {
+			inst.setFunctionName(names, index, name)
+		}
+	}
+
+	for i := range names {
+		if len(names[i]) == 0 {
+			names[i] = fmt.Sprintf("func[%d]", inst.numFuncImports+uint32(i))
+		}
+	}
+	return names
+}
+
+func (inst *instrumenter) setFunctionName(names []string, functionIndex uint32, name string) {
+	if functionIndex < inst.numFuncImports {
+		return
+	}
+	definedIndex := functionIndex - inst.numFuncImports
+	if definedIndex < uint32(len(names)) {
+		names[definedIndex] = name
+	}
+}
+
+// readExportedFunctionNames yields the first export name of each exported function;
+// the export section was already validated by rewriteExports
+func readExportedFunctionNames(payload []byte) map[uint32]string {
+	names := make(map[uint32]string)
+	reader := &wasmReader{data: payload}
+	count, _ := reader.readU32()
+	for i := uint32(0); i < count; i++ {
+		nameLength, _ := reader.readU32()
+		name, _ := reader.readBytes(int(nameLength))
+		kind, _ := reader.readByte()
+		index, err := reader.readU32()
+		if err != nil {
+			break
+		}
+		_, alreadyNamed := names[index]
+		if kind == externalKindFunction && !alreadyNamed {
+			names[index] = string(name)
+		}
+	}
+	return names
+}
+
+// readFunctionNames yields the function names of the name section, ignoring a malformed section,
+// since the name section is not validated by Wasmer either
+func readFunctionNames(nameSection []byte) map[uint32]string {
+	names := make(map[uint32]string)
+	reader := &wasmReader{data: nameSection}
+	for !reader.done() {
+		subsectionID, err := reader.readByte()
+		if err != nil {
+			break
+		}
+		size, err := reader.readU32()
+		if err != nil {
+			break
+		}
+		subsection, err := reader.readBytes(int(size))
+		if err != nil {
+			break
+		}
+		if subsectionID == nameSubsectionFunctions {
+			readNameMap(subsection, names)
+		}
+	}
+	return names
+}
+
+func readNameMap(payload []byte, names map[uint32]string) {
+	reader := &wasmReader{data: payload}
+	count, err := reader.readU32()
+	if err != nil {
+		return
+	}
+	for i := uint32(0); i < count; i++ {
+		index, err := reader.readU32()
+		if err != nil {
+			return
+		}
+		nameLength, err := reader.readU32()
+		if err != nil {
+			return
+		}
+		name, err := reader.readBytes(int(nameLength))
+		if err != nil {
+			return
+		}
+		names[index] = string(name)
+	}
+}
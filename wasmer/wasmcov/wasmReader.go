@@ -0,0 +1,102 @@
+package wasmcov
+
+import (
+	"errors"
+)
+
+// ErrUnexpectedEndOfWasm signals a truncated module
+var ErrUnexpectedEndOfWasm = errors.New("unexpected end of wasm")
+
+// ErrInvalidLEB128 signals an integer encoded on too many bytes
+var ErrInvalidLEB128 = errors.New("invalid LEB128 integer")
+
+type wasmReader struct {
+	data []byte
+	pos  int
+}
+
+func (reader *wasmReader) done() bool {
+	return reader.pos >= len(reader.data)
+}
+
+func (reader *wasmReader) readByte() (byte, error) {
+	if reader.done() {
+		return 0, ErrUnexpectedEndOfWasm
+	}
+	b := reader.data[reader.pos]
+	reader.pos++
+	return b, nil
+}
+
+func (reader *wasmReader) readBytes(length int) ([]byte, error) {
+	if length < 0 || reader.pos+length > len(reader.data) {
+		return nil, ErrUnexpectedEndOfWasm
+	}
+	bytes := reader.data[reader.pos : reader.pos+length]
+	reader.pos += length
+	return bytes, nil
+}
+
+func (reader *wasmReader) readU32() (uint32, error) {
+	var result uint32
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := reader.readByte()
+		if err != nil {
+			return 0, err
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+	}
+	return 0, ErrInvalidLEB128
+}
+
+// skipLEB128 skips a signed or unsigned integer of at most maxBits bits
+func (reader *wasmReader) skipLEB128(maxBits uint) error {
+	for shift := uint(0); shift < maxBits+7; shift += 7 {
+		b, err := reader.readByte()
+		if err != nil {
+			return err
+		}
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+	return ErrInvalidLEB128
+}
+
+func appendU32(buffer []byte, value uint32) []byte {
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		if value != 0 {
+			buffer = append(buffer, b|0x80)
+			continue
+		}
+		return append(buffer, b)
+	}
+}
+
+func appendS32(buffer []byte, value int32) []byte {
+	for {
+		b := byte(value & 0x7f)
+		value >>= 7
+		isLast := (value == 0 && b&0x40 == 0) || (value == -1 && b&0x40 != 0)
+		if isLast {
+			return append(buffer, b)
+		}
+		buffer = append(buffer, b|0x80)
+	}
+}
+
+func appendName(buffer []byte, name string) []byte {
+	buffer = appendU32(buffer, uint32(len(name)))
+	return append(buffer, name...)
+}
+
+func appendSection(buffer []byte, sectionID byte, payload []byte) []byte {
+	buffer = append(buffer, sectionID)
+	buffer = appendU32(buffer, uint32(len(payload)))
+	return append(buffer, payload...)
+}